@@ -74,6 +74,18 @@ type NvmeofConfigSpec struct {
 	CephCsiSecrets *CephCsiSecretsSpec `json:"cephCsiSecrets,omitempty"`
 }
 
+// InlineVolumesSpec defines the access to CSI inline ephemeral volumes
+type InlineVolumesSpec struct {
+	// Namespaces allowed to use inline ephemeral volumes with this profile, "*" allows all
+	// namespaces. Inline volumes requested by pods of other namespaces are rejected on admission
+	//+kubebuilder:validation:Required
+	//+kubebuilder:validation:MinItems:=1
+	//+kubebuilder:validation:items:Pattern:=`^(\*|[a-z0-9]([-a-z0-9]*[a-z0-9])?)$`
+	//+kubebuilder:validation:items:MaxLength:=63
+	//+listType=set
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
 // ClientProfileSpec defines the desired state of Ceph CSI
 // configuration for volumes and snapshots configured to use
 // this profile
@@ -93,6 +105,11 @@ type ClientProfileSpec struct {
 
 	//+kubebuilder:validation:Optional
 	Nvmeof *NvmeofConfigSpec `json:"nvmeof,omitempty"`
+
+	// Access to CSI inline ephemeral volumes using this profile, for drivers with the
+	// Ephemeral volume lifecycle mode enabled. Inline volumes are rejected when not set
+	//+kubebuilder:validation:Optional
+	InlineVolumes *InlineVolumesSpec `json:"inlineVolumes,omitempty"`
 }
 
 // ClientProfileStatus defines the observed state of Ceph CSI
@@ -106,6 +123,16 @@ type ClientProfileStatus struct {
 	// Message provides human-readable details about the current phase
 	// +optional
 	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the generation of the ClientProfile the status was computed for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the ClientProfile state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 const (
@@ -119,9 +146,77 @@ const (
 	ClientProfilePhasePending = "Pending"
 )
 
+const (
+	// ClientProfileCephConnectionResolvedCondition reports whether the referenced CephConnection
+	// was found and is owned by the profile
+	ClientProfileCephConnectionResolvedCondition = "CephConnectionResolved"
+
+	// ClientProfileReplicationResolvedCondition reports whether the replication destination of the
+	// profile was resolved from the ClientProfileReplications referencing it
+	ClientProfileReplicationResolvedCondition = "ReplicationResolved"
+
+	// ClientProfileConfigPublishedCondition reports whether the profile is published to the Ceph CSI
+	// configuration
+	ClientProfileConfigPublishedCondition = "ConfigPublished"
+
+	// ClientProfileReadyCondition reports whether the profile is reconciled successfully
+	ClientProfileReadyCondition = "Ready"
+)
+
+const (
+	// The condition was not evaluated yet
+	ClientProfileReconcilingReason = "Reconciling"
+
+	// The referenced CephConnection was found
+	ClientProfileCephConnectionFoundReason = "CephConnectionFound"
+
+	// The profile does not reference a CephConnection
+	ClientProfileCephConnectionRefMissingReason = "CephConnectionRefMissing"
+
+	// The referenced CephConnection does not exist
+	ClientProfileCephConnectionNotFoundReason = "CephConnectionNotFound"
+
+	// The referenced CephConnection could not be loaded or updated
+	ClientProfileCephConnectionErrorReason = "CephConnectionError"
+
+	// No Ready ClientProfileReplication references the profile
+	ClientProfileNoReplicationReason = "NoReplication"
+
+	// A single Ready ClientProfileReplication references the profile
+	ClientProfileReplicationFoundReason = "ReplicationFound"
+
+	// More than one Ready ClientProfileReplication references the profile
+	ClientProfileMultipleReadyReplicationsReason = "MultipleReadyReplications"
+
+	// The ClientProfileReplications referencing the profile could not be loaded or updated
+	ClientProfileReplicationErrorReason = "ReplicationError"
+
+	// The profile cannot be deleted while ClientProfileReplications reference it
+	ClientProfileReferencedByReplicationsReason = "ReferencedByReplications"
+
+	// The profile is published to the Ceph CSI configuration
+	ClientProfileConfigPublishedReason = "ConfigPublished"
+
+	// The Ceph CSI configuration could not be updated
+	ClientProfileConfigPublishFailedReason = "ConfigPublishFailed"
+
+	// The profile is reconciled successfully
+	ClientProfileReconciledReason = "Reconciled"
+
+	// The profile is being deleted
+	ClientProfileDeletingReason = "Deleting"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:storageversion
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="CephConnection",type=string,JSONPath=`.status.conditions[?(@.type=="CephConnectionResolved")].status`
+//+kubebuilder:printcolumn:name="Replication",type=string,JSONPath=`.status.conditions[?(@.type=="ReplicationResolved")].status`
+//+kubebuilder:printcolumn:name="Published",type=string,JSONPath=`.status.conditions[?(@.type=="ConfigPublished")].status`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ClientProfile is the Schema for the clientprofiles API
 type ClientProfile struct {
@@ -36,19 +36,150 @@ type MappingsSpec struct {
 	BlockPoolIdMapping []BlockPoolIdPair `json:"blockPoolIdMapping,omitempty"`
 }
 
+// BlockPoolNamesSpec names the block pools of a local ClientProfile
+type BlockPoolNamesSpec struct {
+	//+kubebuilder:validation:Required
+	//+kubebuilder:validation:MinLength:=1
+	ClientProfile string `json:"clientProfile"`
+
+	// Names of the block pools by pool ID
+	//+kubebuilder:validation:Required
+	Names map[string]string `json:"names"`
+}
+
+// MappingMigrationSpec defines the migration of the mappings to ClientProfileReplications
+type MappingMigrationSpec struct {
+	// BlockPoolNames names the local block pools of the mappings, as ClientProfileReplication pool
+	// mappings are keyed by pool name rather than by pool ID
+	//+kubebuilder:validation:Optional
+	//+listType=map
+	//+listMapKey=clientProfile
+	BlockPoolNames []BlockPoolNamesSpec `json:"blockPoolNames,omitempty"`
+}
+
 // ClientProfileMappingSpec defines the desired state of ClientProfileMapping
 type ClientProfileMappingSpec struct {
 	//+kubebuilder:validation:Required
 	Mappings []MappingsSpec `json:"mappings,omitempty"`
+
+	// Migration opts the mappings in to their migration to ClientProfileReplications. The operator
+	// generates a ClientProfileReplication per local and remote ClientProfile pair, verifies the replication
+	// destinations published to the Ceph CSI config match the mappings and then marks the ClientProfileMapping as
+	// superseded. The mappings remain published for the volumes using the mapped cluster IDs.
+	//+kubebuilder:validation:Optional
+	Migration *MappingMigrationSpec `json:"migration,omitempty"`
+}
+
+// MappingErrorStatus reports why a mapping was excluded from the Ceph CSI cluster mappings
+type MappingErrorStatus struct {
+	// Index of the mapping in .spec.mappings
+	Index int `json:"index"`
+
+	LocalClientProfile string `json:"localClientProfile,omitempty"`
+
+	RemoteClientProfile string `json:"remoteClientProfile,omitempty"`
+
+	// Reason is a machine readable code of the validation error
+	Reason string `json:"reason"`
+
+	// Message is a human readable description of the validation error
+	Message string `json:"message,omitempty"`
 }
 
 // ClientProfileMappingStatus defines the observed state of ClientProfileMapping
 type ClientProfileMappingStatus struct {
+	// ObservedGeneration is the generation of the ClientProfileMapping the status was computed for
+	//+kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// MappingErrors lists the mappings that are invalid and were not published to the Ceph CSI config
+	//+kubebuilder:validation:Optional
+	MappingErrors []MappingErrorStatus `json:"mappingErrors,omitempty"`
+
+	// Migration reports the progress of the migration to ClientProfileReplications
+	//+kubebuilder:validation:Optional
+	Migration *MappingMigrationStatus `json:"migration,omitempty"`
+
+	// Conditions represent the latest available observations of the ClientProfileMapping state
+	//+kubebuilder:validation:Optional
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// MappingMigrationStatus defines the observed state of the migration to ClientProfileReplications
+type MappingMigrationStatus struct {
+	// ClientProfileReplications replicating the mappings
+	//+kubebuilder:validation:Optional
+	ClientProfileReplications []string `json:"clientProfileReplications,omitempty"`
+
+	// UnsupportedMappings lists the mappings that cannot be expressed as ClientProfileReplications
+	//+kubebuilder:validation:Optional
+	UnsupportedMappings []MappingErrorStatus `json:"unsupportedMappings,omitempty"`
 }
 
+const (
+	// ClientProfileMappingReadyCondition reports whether all the mappings are valid and published
+	// to the Ceph CSI config
+	ClientProfileMappingReadyCondition = "Ready"
+
+	// ClientProfileMappingSupersededCondition reports whether all the mappings are replicated by
+	// ClientProfileReplications, only set when the migration is enabled
+	ClientProfileMappingSupersededCondition = "Superseded"
+)
+
+const (
+	// All the mappings are valid and published
+	ClientProfileMappingPublishedReason = "MappingsPublished"
+
+	// Some of the mappings are invalid, the valid mappings are published
+	ClientProfileMappingInvalidMappingsReason = "InvalidMappings"
+
+	// The mappings could not be written to the Ceph CSI config
+	ClientProfileMappingPublishFailedReason = "PublishFailed"
+
+	// The local ClientProfile of a mapping does not exist
+	ClientProfileMappingLocalClientProfileNotFoundReason = "LocalClientProfileNotFound"
+
+	// The remote ClientProfile of a mapping does not exist
+	ClientProfileMappingRemoteClientProfileNotFoundReason = "RemoteClientProfileNotFound"
+
+	// A block pool ID pair of a mapping conflicts with a pair of the same mapping or of an older one
+	ClientProfileMappingConflictingPoolIdReason = "ConflictingBlockPoolId"
+
+	// All the mappings are replicated by ClientProfileReplications and published to the Ceph CSI config
+	ClientProfileMappingMigratedReason = "Migrated"
+
+	// Some mappings cannot be expressed as ClientProfileReplications
+	ClientProfileMappingMigrationIncompleteReason = "MigrationIncomplete"
+
+	// The replication destinations published to the Ceph CSI config do not match the mappings yet
+	ClientProfileMappingVerificationPendingReason = "VerificationPending"
+
+	// The mapping is invalid and is not migrated
+	ClientProfileMappingInvalidMappingReason = "InvalidMapping"
+
+	// A local block pool ID of the mapping is not named in the migration block pool names
+	ClientProfileMappingUnknownBlockPoolNameReason = "UnknownBlockPoolName"
+
+	// Several local block pool IDs of the local ClientProfile have the same name
+	ClientProfileMappingDuplicateBlockPoolNameReason = "DuplicateBlockPoolName"
+
+	// Another ClientProfileReplication replicates the local ClientProfile to the remote ClientProfile with
+	// different mappings
+	ClientProfileMappingConflictingReplicationReason = "ConflictingClientProfileReplication"
+
+	// A ClientProfileReplication that was not generated by the migration has the name of the generated one
+	ClientProfileMappingReplicationNameConflictReason = "ClientProfileReplicationNameConflict"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:storageversion
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+//+kubebuilder:printcolumn:name="Superseded",type=string,JSONPath=`.status.conditions[?(@.type=="Superseded")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ClientProfileMapping is the Schema for the clientprofilemappings API
 type ClientProfileMapping struct {
@@ -33,9 +33,20 @@ type ClientProfileReplicationSpec struct {
 	// +kubebuilder:validation:Required
 	RemoteClientProfile string `json:"remoteClientProfile"`
 
+	// ExternalRemoteClientProfile declares that the remote client profile is not defined by a
+	// ClientProfile CR of this cluster, e.g. when it only exists on the remote cluster. Otherwise the
+	// ClientProfileReplication is rejected until the remote ClientProfile exists.
+	// +optional
+	ExternalRemoteClientProfile bool `json:"externalRemoteClientProfile,omitempty"`
+
 	// RBD contains RBD-specific replication configuration
 	// +optional
 	RBD *RBDReplicationSpec `json:"rbd,omitempty"`
+
+	// CephFS contains CephFS-specific replication configuration, for volumes replicated with CephFS
+	// snapshot mirroring
+	// +optional
+	CephFS *CephFSReplicationSpec `json:"cephFS,omitempty"`
 }
 
 // RBDReplicationSpec defines RBD-specific replication configuration
@@ -45,6 +56,47 @@ type RBDReplicationSpec struct {
 	PoolMapping []PoolMappingSpec `json:"poolMapping,omitempty"`
 }
 
+// CephFSReplicationSpec defines CephFS-specific replication configuration
+type CephFSReplicationSpec struct {
+	// FilesystemMapping maps local filesystem names to the remote filesystems
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	FilesystemMapping []FilesystemMappingSpec `json:"filesystemMapping,omitempty"`
+
+	// SubvolumeGroupMapping maps local subvolume group names to remote subvolume group names
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	SubvolumeGroupMapping []SubvolumeGroupMappingSpec `json:"subvolumeGroupMapping,omitempty"`
+}
+
+// FilesystemMappingSpec defines the mapping for a single filesystem
+type FilesystemMappingSpec struct {
+	// Name is the local filesystem name
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// RemoteName is the filesystem name on the remote cluster, defaults to the local name
+	// +optional
+	RemoteName string `json:"remoteName,omitempty"`
+
+	// RemoteID is the filesystem ID (fscid) on the remote cluster
+	// +kubebuilder:validation:Required
+	RemoteID string `json:"remoteID"`
+}
+
+// SubvolumeGroupMappingSpec defines the mapping for a single subvolume group
+type SubvolumeGroupMappingSpec struct {
+	// Name is the local subvolume group name
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// RemoteName is the subvolume group name on the remote cluster
+	// +kubebuilder:validation:Required
+	RemoteName string `json:"remoteName"`
+}
+
 // PoolMappingSpec defines the mapping for a single pool
 type PoolMappingSpec struct {
 	// Name is the pool name (must be consistent across clusters)
@@ -65,6 +117,16 @@ type ClientProfileReplicationStatus struct {
 	// Message provides human-readable details about the current phase
 	// +optional
 	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the generation of the ClientProfileReplication the status was computed for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the ClientProfileReplication state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 const (
@@ -78,9 +140,58 @@ const (
 	ClientProfileReplicationPhasePending = "Pending"
 )
 
+const (
+	// ClientProfileReplicationClientProfileResolvedCondition reports whether the local and remote
+	// ClientProfiles exist
+	ClientProfileReplicationClientProfileResolvedCondition = "ClientProfileResolved"
+
+	// ClientProfileReplicationReadyCondition reports whether the ClientProfileReplication is accepted
+	// as the replication destination of its local ClientProfile
+	ClientProfileReplicationReadyCondition = "Ready"
+)
+
+const (
+	// The condition was not evaluated yet
+	ClientProfileReplicationReconcilingReason = "Reconciling"
+
+	// The local and remote ClientProfiles were found
+	ClientProfileReplicationClientProfileFoundReason = "ClientProfileFound"
+
+	// The local ClientProfile does not exist
+	ClientProfileReplicationClientProfileNotFoundReason = "ClientProfileNotFound"
+
+	// The remote ClientProfile does not exist and is not declared external
+	ClientProfileReplicationRemoteClientProfileNotFoundReason = "RemoteClientProfileNotFound"
+
+	// Several pool mappings have the same pool name
+	ClientProfileReplicationDuplicatePoolNameReason = "DuplicatePoolName"
+
+	// A remote pool or filesystem ID is not a non-negative integer
+	ClientProfileReplicationInvalidRemoteIDReason = "InvalidRemoteID"
+
+	// The ClientProfileReplication replicates a ClientProfile to itself, or closes a cycle of older
+	// Ready ClientProfileReplications through more than two ClientProfiles
+	ClientProfileReplicationCycleReason = "ReplicationCycle"
+
+	// The local ClientProfile or its ClientProfileReplications could not be loaded
+	ClientProfileReplicationLookupFailedReason = "LookupFailed"
+
+	// The ClientProfileReplication is the active replication destination of its local ClientProfile
+	ClientProfileReplicationAcceptedReason = "Accepted"
+
+	// An older ClientProfileReplication is already active for the same local and remote ClientProfiles
+	ClientProfileReplicationConflictReason = "ConflictingReplication"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Local Profile",type=string,JSONPath=`.spec.localClientProfile`
+// +kubebuilder:printcolumn:name="Remote Profile",type=string,JSONPath=`.spec.remoteClientProfile`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ClientProfileReplication is the Schema for the clientprofilereplications API
 type ClientProfileReplication struct {
@@ -0,0 +1,197 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClientProfileReplicationPairSpec defines two ClientProfiles replicated to each other
+// +kubebuilder:validation:XValidation:rule="self.primaryClientProfile != self.secondaryClientProfile",message="primaryClientProfile and secondaryClientProfile must be different"
+type ClientProfileReplicationPairSpec struct {
+	// PrimaryClientProfile is the name of the ClientProfile of the primary cluster
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	PrimaryClientProfile string `json:"primaryClientProfile"`
+
+	// SecondaryClientProfile is the name of the ClientProfile of the secondary cluster
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	SecondaryClientProfile string `json:"secondaryClientProfile"`
+
+	// RBD contains the RBD pools replicated between the clusters
+	// +optional
+	RBD *RBDReplicationPairSpec `json:"rbd,omitempty"`
+
+	// CephFS contains the CephFS filesystems and subvolume groups replicated between the clusters
+	// +optional
+	CephFS *CephFSReplicationPairSpec `json:"cephFS,omitempty"`
+}
+
+// RBDReplicationPairSpec defines the RBD pools replicated between the clusters of a pair
+type RBDReplicationPairSpec struct {
+	// Pools lists the IDs of the replicated pools on both clusters
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Pools []ReplicationPairPoolSpec `json:"pools,omitempty"`
+}
+
+// ReplicationPairPoolSpec defines the IDs of a replicated pool on both clusters
+type ReplicationPairPoolSpec struct {
+	// Name is the pool name (must be consistent across clusters)
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// PrimaryID is the pool ID on the primary cluster
+	// +kubebuilder:validation:Required
+	PrimaryID string `json:"primaryID"`
+
+	// SecondaryID is the pool ID on the secondary cluster
+	// +kubebuilder:validation:Required
+	SecondaryID string `json:"secondaryID"`
+}
+
+// CephFSReplicationPairSpec defines the CephFS filesystems and subvolume groups replicated between
+// the clusters of a pair
+type CephFSReplicationPairSpec struct {
+	// Filesystems lists the names and IDs of the replicated filesystems on both clusters
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Filesystems []ReplicationPairFilesystemSpec `json:"filesystems,omitempty"`
+
+	// SubvolumeGroups lists the names of the replicated subvolume groups on both clusters
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	SubvolumeGroups []ReplicationPairSubvolumeGroupSpec `json:"subvolumeGroups,omitempty"`
+}
+
+// ReplicationPairFilesystemSpec defines the names and IDs of a replicated filesystem on both clusters
+type ReplicationPairFilesystemSpec struct {
+	// Name is the filesystem name on the primary cluster
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// SecondaryName is the filesystem name on the secondary cluster, defaults to Name
+	// +optional
+	SecondaryName string `json:"secondaryName,omitempty"`
+
+	// PrimaryID is the filesystem ID (fscid) on the primary cluster
+	// +kubebuilder:validation:Required
+	PrimaryID string `json:"primaryID"`
+
+	// SecondaryID is the filesystem ID (fscid) on the secondary cluster
+	// +kubebuilder:validation:Required
+	SecondaryID string `json:"secondaryID"`
+}
+
+// ReplicationPairSubvolumeGroupSpec defines the names of a replicated subvolume group on both clusters
+type ReplicationPairSubvolumeGroupSpec struct {
+	// Name is the subvolume group name on the primary cluster
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// SecondaryName is the subvolume group name on the secondary cluster
+	// +kubebuilder:validation:Required
+	SecondaryName string `json:"secondaryName"`
+}
+
+// ClientProfileReplicationPairStatus defines the observed state of ClientProfileReplicationPair.
+type ClientProfileReplicationPairStatus struct {
+	// PrimaryToSecondary is the name of the ClientProfileReplication replicating the primary
+	// ClientProfile to the secondary one
+	// +optional
+	PrimaryToSecondary string `json:"primaryToSecondary,omitempty"`
+
+	// SecondaryToPrimary is the name of the ClientProfileReplication replicating the secondary
+	// ClientProfile to the primary one
+	// +optional
+	SecondaryToPrimary string `json:"secondaryToPrimary,omitempty"`
+
+	// ObservedGeneration is the generation of the ClientProfileReplicationPair the status was computed for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the ClientProfileReplicationPair state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+const (
+	// ClientProfileReplicationPairReadyCondition reports whether both ClientProfileReplications of the
+	// pair are generated and Ready
+	ClientProfileReplicationPairReadyCondition = "Ready"
+)
+
+const (
+	// The condition was not evaluated yet
+	ClientProfileReplicationPairReconcilingReason = "Reconciling"
+
+	// Both ClientProfileReplications of the pair are Ready
+	ClientProfileReplicationPairReplicationsReadyReason = "ReplicationsReady"
+
+	// The ClientProfileReplications of the pair are not validated yet
+	ClientProfileReplicationPairReplicationsPendingReason = "ReplicationsPending"
+
+	// A ClientProfileReplication of the pair is rejected
+	ClientProfileReplicationPairReplicationRejectedReason = "ReplicationRejected"
+
+	// A ClientProfileReplication that is not owned by the pair has the name of a generated one
+	ClientProfileReplicationPairNameConflictReason = "NameConflict"
+
+	// The ClientProfileReplications of the pair could not be generated
+	ClientProfileReplicationPairReconcileFailedReason = "ReconcileFailed"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Primary Profile",type=string,JSONPath=`.spec.primaryClientProfile`
+// +kubebuilder:printcolumn:name="Secondary Profile",type=string,JSONPath=`.spec.secondaryClientProfile`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ClientProfileReplicationPair is the Schema for the clientprofilereplicationpairs API. It generates
+// and owns the ClientProfileReplications of both directions between two ClientProfiles.
+type ClientProfileReplicationPair struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of ClientProfileReplicationPair
+	// +required
+	Spec ClientProfileReplicationPairSpec `json:"spec"`
+
+	// status defines the observed state of ClientProfileReplicationPair
+	// +optional
+	Status ClientProfileReplicationPairStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClientProfileReplicationPairList contains a list of ClientProfileReplicationPair
+type ClientProfileReplicationPairList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []ClientProfileReplicationPair `json:"items"`
+}
@@ -96,6 +96,80 @@ type VolumeSpec struct {
 	Mount corev1.VolumeMount `json:"mount,omitempty"`
 }
 
+type WorkloadPatchType string
+
+const (
+	// A Kubernetes strategic merge patch, merging lists by their patch merge keys (e.g. containers by name)
+	StrategicMergeWorkloadPatchType WorkloadPatchType = "StrategicMerge"
+
+	// A JSON patch as defined by RFC 6902
+	JSON6902WorkloadPatchType WorkloadPatchType = "JSON6902"
+)
+
+// ContainerOverridesSpec defines customizations for a single driver container
+type ContainerOverridesSpec struct {
+	// Extra environment variables for the container.
+	// A variable replaces any variable with the same name set by the operator.
+	//+kubebuilder:validation:Optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Extra sources to populate environment variables in the container
+	//+kubebuilder:validation:Optional
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Security context for the container, replaces the security context set by the operator
+	//+kubebuilder:validation:Optional
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// Periodic probe of the container liveness, replaces the probe set by the operator
+	//+kubebuilder:validation:Optional
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+
+	// Periodic probe of the container service readiness, replaces the probe set by the operator
+	//+kubebuilder:validation:Optional
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+
+	// Probe indicating that the container has successfully initialized,
+	// replaces the probe set by the operator
+	//+kubebuilder:validation:Optional
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+}
+
+// WorkloadPatchSpec describes a patch to apply on top of an operator generated workload
+type WorkloadPatchSpec struct {
+	// Type of the patch, supported values are StrategicMerge and JSON6902
+	//+kubebuilder:validation:Required
+	//+kubebuilder:validation:Enum:=StrategicMerge;JSON6902
+	Type WorkloadPatchType `json:"type,omitempty"`
+
+	// The patch content, in either YAML or JSON format.
+	// A strategic merge patch is expressed as a partial workload object, a JSON6902 patch
+	// is expressed as a list of operations.
+	//+kubebuilder:validation:Required
+	//+kubebuilder:validation:MinLength:=1
+	Patch string `json:"patch,omitempty"`
+}
+
+// ExtraContainerSpec defines a user container to run alongside the driver containers
+type ExtraContainerSpec struct {
+	// The container definition.
+	// The container schema is not embedded in the CRD to keep its size in check, the
+	// definition is validated by the API server when the driver workloads are updated.
+	//+kubebuilder:validation:Required
+	//+kubebuilder:validation:Schemaless
+	//+kubebuilder:pruning:PreserveUnknownFields
+	//+kubebuilder:validation:Type=object
+	Container corev1.Container `json:"container"`
+
+	// Names of built-in driver volumes to mount into the container, using the same mount
+	// path as the driver containers. socket-dir is available in controller plugin pods,
+	// plugin-dir is available in node plugin pods and logs-dir is available in both
+	// when log rotation is enabled.
+	//+kubebuilder:validation:Optional
+	//+kubebuilder:validation:items:Enum:=socket-dir;plugin-dir;logs-dir
+	BuiltinVolumeMounts []string `json:"builtinVolumeMounts,omitempty"`
+}
+
 type PodCommonSpec struct {
 	// Service account name to be used for driver's pods
 	//+kubebuilder:validation:Optional
@@ -130,6 +204,15 @@ type PodCommonSpec struct {
 	// To indicate the image pull policy to be applied to all the containers in the csi driver pods.
 	//+kubebuilder:validation:Optional
 	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy"`
+
+	// Additional containers to run in the pod, next to the driver containers.
+	// A container with the same name as a driver container replaces it.
+	//+kubebuilder:validation:Optional
+	ExtraContainers []ExtraContainerSpec `json:"extraContainers,omitempty"`
+
+	// Init containers to run in the pod before the driver containers are started
+	//+kubebuilder:validation:Optional
+	InitContainers []ExtraContainerSpec `json:"initContainers,omitempty"`
 }
 
 type NodePluginResourcesSpec struct {
@@ -155,6 +238,48 @@ type TopologySpec struct {
 	//+kubebuilder:validation:Required
 	DomainLabels []string `json:"domainLabels,omitempty"`
 }
+
+// NodePoolSpec defines node plugin overrides for a subset of the cluster nodes
+type NodePoolSpec struct {
+	// Name of the pool, used to generate the name of the pool's node plugin daemonset
+	//+kubebuilder:validation:Required
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:MaxLength:=20
+	//+kubebuilder:validation:Pattern:=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Node labels selecting the nodes of the pool. A node matching the selectors of
+	// multiple pools is assigned to the first of them.
+	//+kubebuilder:validation:Required
+	//+kubebuilder:validation:MinProperties:=1
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// kubelet directory path for the nodes of the pool, overrides the node plugin kubeletDirPath
+	//+kubebuilder:validation:Optional
+	KubeletDirPath string `json:"kubeletDirPath,omitempty"`
+
+	// Resource requirements for the pool's containers, overrides the node plugin resources
+	// on a per container basis
+	//+kubebuilder:validation:Optional
+	Resources NodePluginResourcesSpec `json:"resources,omitempty"`
+
+	// Tolerations for the pool's pods, overrides the node plugin tolerations
+	//+kubebuilder:validation:Optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Update strategy for the pool's daemonset, overrides the node plugin update strategy
+	//+kubebuilder:validation:Optional
+	UpdateStrategy *appsv1.DaemonSetUpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// Kernel mount options for the nodes of the pool, overrides the driver kernelMountOptions
+	//+kubebuilder:validation:Optional
+	KernelMountOptions map[string]string `json:"kernelMountOptions,omitempty"`
+
+	// Fuse mount options for the nodes of the pool, overrides the driver fuseMountOptions
+	//+kubebuilder:validation:Optional
+	FuseMountOptions map[string]string `json:"fuseMountOptions,omitempty"`
+}
+
 type NodePluginSpec struct {
 	// Embedded common pods spec
 	PodCommonSpec `json:",inline"`
@@ -169,6 +294,7 @@ type NodePluginSpec struct {
 	Resources NodePluginResourcesSpec `json:"resources,omitempty"`
 
 	// kubelet directory path, if kubelet configured to use other than /var/lib/kubelet path.
+	// When not set, the path is detected from the nodes the plugin is scheduled on
 	//+kubebuilder:validation:Optional
 	KubeletDirPath string `json:"kubeletDirPath,omitempty"`
 
@@ -181,12 +307,35 @@ type NodePluginSpec struct {
 
 	// Extra arguments for containers in the node plugin daemonset.
 	// The key is the container name, the value is a list of CLI arguments.
-	// The operator will not override the default CLI argument values it's setting.
-	// This is more for customizing what comes as default with cephcsi.
+	// A flag replaces any flag with the same name set by the operator, and a flag
+	// prefixed with '!' (e.g. "!--pidlimit") removes it.
 	// Examples of container names: csi-rbdplugin, driver-registrar, csi-addons,
 	// liveness-prometheus, etc.
 	//+kubebuilder:validation:Optional
 	ContainerExtraArgs map[string][]string `json:"containerExtraArgs,omitempty"`
+
+	// Overrides for containers in the node plugin daemonsets.
+	// The key is the container name, the value holds the container customizations.
+	// Examples of container names: csi-rbdplugin, driver-registrar, csi-addons,
+	// liveness-prometheus, etc.
+	//+kubebuilder:validation:Optional
+	ContainerOverrides map[string]ContainerOverridesSpec `json:"containerOverrides,omitempty"`
+
+	// Named groups of nodes requiring different node plugin settings. The operator deploys a
+	// dedicated node plugin daemonset for each of the pools, the default node plugin daemonset
+	// is scheduled only on nodes not matched by any of the pools.
+	//+kubebuilder:validation:Optional
+	//+kubebuilder:validation:MaxItems:=16
+	//+listType=map
+	//+listMapKey=name
+	NodePools []NodePoolSpec `json:"nodePools,omitempty"`
+
+	// A list of patches to apply, in order, on top of the generated node plugin daemonset.
+	// Patches are applied after the operator rendered the daemonset and can be used to
+	// customize any aspect of it that is not otherwise exposed by the API.
+	// A patch is not allowed to modify the daemonset name, namespace or selector.
+	//+kubebuilder:validation:Optional
+	Patches []WorkloadPatchSpec `json:"patches,omitempty"`
 }
 
 type ControllerPluginResourcesSpec struct {
@@ -244,17 +393,32 @@ type ControllerPluginSpec struct {
 	// To enable logrotation for csi pods,
 	// Some platforms require controller plugin to run privileged,
 	// For example, OpenShift with SELinux restrictions requires the pod to be privileged to write to hostPath.
+	// Defaults to true when log rotation is enabled on a detected OpenShift cluster
 	//+kubebuilder:validation:Optional
 	Privileged *bool `json:"privileged,omitempty"`
 
 	// Extra arguments for containers in the controller plugin deployment.
 	// The key is the container name, the value is a list of CLI arguments.
-	// The operator will not override the default CLI argument values it's setting.
-	// This is more for customizing what comes as default with cephcsi.
+	// A flag replaces any flag with the same name set by the operator, and a flag
+	// prefixed with '!' (e.g. "!--extra-create-metadata") removes it.
 	// Examples of container names: csi-rbdplugin, csi-provisioner, csi-attacher,
 	// csi-resizer, csi-snapshotter, csi-omap-generator, liveness-prometheus, etc.
 	//+kubebuilder:validation:Optional
 	ContainerExtraArgs map[string][]string `json:"containerExtraArgs,omitempty"`
+
+	// Overrides for containers in the controller plugin deployment.
+	// The key is the container name, the value holds the container customizations.
+	// Examples of container names: csi-rbdplugin, csi-provisioner, csi-attacher,
+	// csi-resizer, csi-snapshotter, csi-omap-generator, liveness-prometheus, etc.
+	//+kubebuilder:validation:Optional
+	ContainerOverrides map[string]ContainerOverridesSpec `json:"containerOverrides,omitempty"`
+
+	// A list of patches to apply, in order, on top of the generated controller plugin deployment.
+	// Patches are applied after the operator rendered the deployment and can be used to
+	// customize any aspect of it that is not otherwise exposed by the API.
+	// A patch is not allowed to modify the deployment name, namespace or selector.
+	//+kubebuilder:validation:Optional
+	Patches []WorkloadPatchSpec `json:"patches,omitempty"`
 }
 
 type LivenessSpec struct {
@@ -263,6 +427,71 @@ type LivenessSpec struct {
 	//+kubebuilder:validation:Minimum:=1024
 	//+kubebuilder:validation:Maximum:=65535
 	MetricsPort int `json:"metricsPort,omitempty"`
+
+	// Port of the health endpoint queried by the liveness probe of the plugin container.
+	// Defaults to 9808 for rbd, 9818 for cephfs, 9828 for nfs and 9838 for nvmeof
+	//+kubebuilder:validation:Optional
+	//+kubebuilder:validation:Minimum:=1024
+	//+kubebuilder:validation:Maximum:=65535
+	HealthPort *int32 `json:"healthPort,omitempty"`
+
+	// Serve the liveness metrics over TLS through a kube-rbac-proxy sidecar, using a
+	// certificate issued as configured in the driver certificates settings. Scrapers
+	// must be authorized to get the /metrics non-resource URL
+	//+kubebuilder:validation:Optional
+	TLS *bool `json:"tls,omitempty"`
+}
+
+// MonitoringSpec defines the Prometheus monitoring settings of a driver
+type MonitoringSpec struct {
+	// Labels added to the ServiceMonitor and PrometheusRule, e.g. to match the
+	// selectors of the Prometheus instance
+	//+kubebuilder:validation:Optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Interval at which the driver metrics are scraped. Defaults to 30s
+	//+kubebuilder:validation:Optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// First port of the metrics endpoints of the controller plugin CSI sidecars, the
+	// provisioner, resizer, attacher and snapshotter sidecars use consecutive ports.
+	// Defaults to 8090 for rbd, 8190 for cephfs, 8290 for nfs and 8390 for nvmeof
+	//+kubebuilder:validation:Optional
+	//+kubebuilder:validation:Minimum:=1024
+	//+kubebuilder:validation:Maximum:=65532
+	SidecarMetricsPort *int32 `json:"sidecarMetricsPort,omitempty"`
+
+	// Settings of the default alerts
+	//+kubebuilder:validation:Optional
+	Alerts *MonitoringAlertsSpec `json:"alerts,omitempty"`
+}
+
+// MonitoringAlertsSpec defines the settings of the default driver alerts
+type MonitoringAlertsSpec struct {
+	// Deploy the PrometheusRule holding the default alerts. Defaults to true
+	//+kubebuilder:validation:Optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// How long a plugin has to be down before alerting. Defaults to 5m
+	//+kubebuilder:validation:Optional
+	PluginDownFor *metav1.Duration `json:"pluginDownFor,omitempty"`
+
+	// Percentage of failed gRPC calls of the controller plugin above which to alert.
+	// Defaults to 10
+	//+kubebuilder:validation:Optional
+	//+kubebuilder:validation:Minimum:=1
+	//+kubebuilder:validation:Maximum:=100
+	GrpcErrorRatioPercent *int32 `json:"grpcErrorRatioPercent,omitempty"`
+
+	// How long the gRPC error ratio has to exceed the threshold before alerting.
+	// Defaults to 15m
+	//+kubebuilder:validation:Optional
+	GrpcErrorRatioFor *metav1.Duration `json:"grpcErrorRatioFor,omitempty"`
+
+	// How long a node plugin pod has to be missing on a node before alerting.
+	// Defaults to 10m
+	//+kubebuilder:validation:Optional
+	NodePluginMissingFor *metav1.Duration `json:"nodePluginMissingFor,omitempty"`
 }
 
 type LeaderElectionSpec struct {
@@ -295,6 +524,198 @@ const (
 	// FuseCephFsClient       CephFsClientType = "fuse"
 )
 
+// VolumeHealthSpec defines the volume health monitoring settings
+type VolumeHealthSpec struct {
+	// Interval between volume health checks of the external health monitor controller.
+	// Defaults to 1m
+	//+kubebuilder:validation:Optional
+	MonitorInterval *metav1.Duration `json:"monitorInterval,omitempty"`
+
+	// Resource requirements for the external health monitor controller container
+	//+kubebuilder:validation:Optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Whether the node plugins report the condition of mounted volumes, requires CSI-Addons
+	// to be deployed. Defaults to true when CSI-Addons is deployed
+	//+kubebuilder:validation:Optional
+	NodeReporting *bool `json:"nodeReporting,omitempty"`
+}
+
+// CsiAddonsFeaturesSpec defines which CSI-Addons operations are served by the driver
+type CsiAddonsFeaturesSpec struct {
+	// Space reclamation of volumes (sparsify and fstrim). Defaults to true
+	//+kubebuilder:validation:Optional
+	ReclaimSpace *bool `json:"reclaimSpace,omitempty"`
+
+	// Rotation of the encryption keys of encrypted volumes. Defaults to true
+	//+kubebuilder:validation:Optional
+	KeyRotation *bool `json:"keyRotation,omitempty"`
+
+	// Fencing of client networks from the Ceph cluster. Defaults to true
+	//+kubebuilder:validation:Optional
+	NetworkFence *bool `json:"networkFence,omitempty"`
+
+	// Mirroring of individual volumes. Defaults to true
+	//+kubebuilder:validation:Optional
+	VolumeReplication *bool `json:"volumeReplication,omitempty"`
+
+	// Mirroring of volume groups. Defaults to true
+	//+kubebuilder:validation:Optional
+	VolumeGroupReplication *bool `json:"volumeGroupReplication,omitempty"`
+
+	// Reporting of the condition of mounted volumes by the node plugins.
+	// Defaults to the value of volumeHealth.nodeReporting
+	//+kubebuilder:validation:Optional
+	VolumeCondition *bool `json:"volumeCondition,omitempty"`
+}
+
+// CsiAddonsSidecarSpec defines the settings of a CSI-Addons sidecar
+type CsiAddonsSidecarSpec struct {
+	// Port the sidecar serves the CSI-Addons controller on
+	//+kubebuilder:validation:Optional
+	//+kubebuilder:validation:Minimum:=1024
+	//+kubebuilder:validation:Maximum:=65535
+	Port *int32 `json:"port,omitempty"`
+
+	// Resource requirements of the sidecar container, takes precedence over the
+	// addons resources of the plugin
+	//+kubebuilder:validation:Optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+type TLSVersion string
+
+const (
+	TLSVersion12 TLSVersion = "VersionTLS12"
+	TLSVersion13 TLSVersion = "VersionTLS13"
+)
+
+// CsiAddonsTLSSpec defines the TLS settings of the CSI-Addons sidecars
+type CsiAddonsTLSSpec struct {
+	// Name of a kubernetes.io/tls secret, in the namespace of the driver, holding the
+	// serving certificate of the sidecars. A certificate is issued as configured in the
+	// driver certificates settings when not set
+	//+kubebuilder:validation:Optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Minimum TLS version accepted by the sidecars
+	//+kubebuilder:validation:Optional
+	//+kubebuilder:validation:Enum:=VersionTLS12;VersionTLS13
+	MinVersion TLSVersion `json:"minVersion,omitempty"`
+}
+
+// CsiAddonsSpec defines the CSI-Addons deployment of a driver
+type CsiAddonsSpec struct {
+	// Whether to deploy the CSI-Addons sidecars, regardless of the enabled features. Defaults to true
+	//+kubebuilder:validation:Optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Operations served through CSI-Addons, all operations are enabled by default.
+	// The controller plugin sidecar is only deployed when a controller side operation is
+	// enabled, the node plugin sidecar when a node side operation is enabled.
+	//+kubebuilder:validation:Optional
+	Features *CsiAddonsFeaturesSpec `json:"features,omitempty"`
+
+	// Settings of the controller plugin sidecar
+	//+kubebuilder:validation:Optional
+	Controller *CsiAddonsSidecarSpec `json:"controller,omitempty"`
+
+	// Settings of the node plugin sidecar
+	//+kubebuilder:validation:Optional
+	Node *CsiAddonsSidecarSpec `json:"node,omitempty"`
+
+	// TLS settings of the sidecars, connections are not encrypted when not set
+	//+kubebuilder:validation:Optional
+	TLS *CsiAddonsTLSSpec `json:"tls,omitempty"`
+}
+
+type CertificateIssuerType string
+
+const (
+	// Certificates are issued by a certificate authority managed by the operator
+	InternalCertificateIssuer CertificateIssuerType = "Internal"
+	// Certificates are issued by cert-manager
+	CertManagerCertificateIssuer CertificateIssuerType = "CertManager"
+	// Certificates are provided by the user, reported in the status only
+	ExternalCertificateIssuer CertificateIssuerType = "External"
+)
+
+// CertManagerIssuerReference references the cert-manager issuer signing the driver certificates
+type CertManagerIssuerReference struct {
+	//+kubebuilder:validation:Required
+	//+kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	// Defaults to Issuer
+	//+kubebuilder:validation:Optional
+	//+kubebuilder:validation:Enum:=Issuer;ClusterIssuer
+	Kind string `json:"kind,omitempty"`
+
+	// Defaults to cert-manager.io
+	//+kubebuilder:validation:Optional
+	Group string `json:"group,omitempty"`
+}
+
+// CertificatesSpec defines how the serving certificates of the driver components are issued
+// +kubebuilder:validation:XValidation:rule=!has(self.issuer) || self.issuer != 'CertManager' || has(self.certManagerIssuerRef),message="certManagerIssuerRef is required with the CertManager issuer"
+type CertificatesSpec struct {
+	// Issuer of the certificates, either an internal certificate authority managed by the
+	// operator or cert-manager. Defaults to Internal
+	//+kubebuilder:validation:Optional
+	//+kubebuilder:validation:Enum:=Internal;CertManager
+	Issuer CertificateIssuerType `json:"issuer,omitempty"`
+
+	// The cert-manager issuer signing the certificates, required with the CertManager issuer
+	//+kubebuilder:validation:Optional
+	CertManagerIssuerRef *CertManagerIssuerReference `json:"certManagerIssuerRef,omitempty"`
+
+	// Validity period of the issued certificates. Defaults to 2160h (90 days)
+	//+kubebuilder:validation:Optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// How long before expiry the certificates are renewed, must be shorter than the
+	// duration. Defaults to 720h (30 days)
+	//+kubebuilder:validation:Optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// SnapshotMetadataSpec defines the settings of the CSI snapshot-metadata sidecar
+type SnapshotMetadataSpec struct {
+	// Name of a kubernetes.io/tls secret, in the namespace of the driver, holding the
+	// serving certificate of the sidecar. A certificate is issued as configured in the
+	// driver certificates settings when not set
+	//+kubebuilder:validation:Optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Resource requirements of the sidecar container
+	//+kubebuilder:validation:Optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Service accounts of the backup applications allowed to query the snapshot metadata
+	// of the driver volumes
+	//+kubebuilder:validation:Optional
+	//+listType=atomic
+	ClientServiceAccounts []ServiceAccountReference `json:"clientServiceAccounts,omitempty"`
+
+	// Whether the operator allows the client service accounts to request tokens for
+	// themselves, through a Role in their namespace. This requires granting the operator
+	// the creation of service account tokens, which it is not granted by default.
+	// Defaults to false, tokens are then granted to the clients by the admin
+	//+kubebuilder:validation:Optional
+	ManageClientTokens *bool `json:"manageClientTokens,omitempty"`
+}
+
+// ServiceAccountReference references a service account in a given namespace
+type ServiceAccountReference struct {
+	//+kubebuilder:validation:Required
+	//+kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	//+kubebuilder:validation:Required
+	//+kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+}
+
 // DriverSpec defines the desired state of Driver
 type DriverSpec struct {
 	// Logging configuration for driver's pods
@@ -365,6 +786,48 @@ type DriverSpec struct {
 	//+kubebuilder:validation:Optional
 	AttachRequired *bool `json:"attachRequired,omitempty"`
 
+	// Whether kubelet passes pod information (name, namespace, UID and service account) as volume
+	// context on NodePublishVolume calls. Defaults to true
+	//+kubebuilder:validation:Optional
+	PodInfoOnMount *bool `json:"podInfoOnMount,omitempty"`
+
+	// Whether the driver supports the "-o context" mount option, allowing kubelet to mount volumes
+	// with the pod's SELinux context. Defaults to true
+	//+kubebuilder:validation:Optional
+	SeLinuxMount *bool `json:"seLinuxMount,omitempty"`
+
+	// Service account tokens kubelet passes to the driver on NodePublishVolume calls,
+	// one per audience
+	//+kubebuilder:validation:Optional
+	//+listType=map
+	//+listMapKey=audience
+	TokenRequests []storagev1.TokenRequest `json:"tokenRequests,omitempty"`
+
+	// Whether kubelet periodically calls NodePublishVolume on mounted volumes,
+	// e.g. to refresh service account tokens. Defaults to false
+	//+kubebuilder:validation:Optional
+	RequiresRepublish *bool `json:"requiresRepublish,omitempty"`
+
+	// Whether the scheduler takes the storage capacity reported by the driver into account.
+	// Supported by the rbd and cephfs drivers only. Defaults to false
+	//+kubebuilder:validation:Optional
+	StorageCapacity *bool `json:"storageCapacity,omitempty"`
+
+	// The volume lifecycle modes supported by the driver, supported values are Persistent
+	// and Ephemeral. Ephemeral is supported by the rbd and cephfs drivers only, the namespaces
+	// allowed to use inline ephemeral volumes are set on each ClientProfile. Defaults to Persistent
+	//+kubebuilder:validation:Optional
+	//+kubebuilder:validation:MinItems:=1
+	//+kubebuilder:validation:items:Enum:=Persistent;Ephemeral
+	//+listType=set
+	VolumeLifecycleModes []storagev1.VolumeLifecycleMode `json:"volumeLifecycleModes,omitempty"`
+
+	// Interval in seconds at which kubelet refreshes the allocatable volume count reported by the
+	// driver on each node. Disabled when not set
+	//+kubebuilder:validation:Optional
+	//+kubebuilder:validation:Minimum:=10
+	NodeAllocatableUpdatePeriodSeconds *int64 `json:"nodeAllocatableUpdatePeriodSeconds,omitempty"`
+
 	// Liveness metrics configuration.
 	// disabled by default.
 	//+kubebuilder:validation:Optional
@@ -374,11 +837,35 @@ type DriverSpec struct {
 	//+kubebuilder:validation:Optional
 	LeaderElection *LeaderElectionSpec `json:"leaderElection,omitempty"`
 
-	// TODO: do we want Csi addon specific field? or should we generalize to
-	// a list of additional sidecars?
+	// Deprecated: use csiAddons instead, ignored when csiAddons is set
 	//+kubebuilder:validation:Optional
 	DeployCsiAddons *bool `json:"deployCsiAddons,omitempty"`
 
+	// CSI-Addons deployment settings, takes precedence over deployCsiAddons.
+	// Not supported by the nfs driver
+	//+kubebuilder:validation:Optional
+	CsiAddons *CsiAddonsSpec `json:"csiAddons,omitempty"`
+
+	// Deploy the CSI snapshot-metadata sidecar in the controller plugin, serving changed
+	// block tracking requests of backup applications, along with its Service and
+	// SnapshotMetadataService. Supported by the rbd driver only
+	//+kubebuilder:validation:Optional
+	SnapshotMetadata *SnapshotMetadataSpec `json:"snapshotMetadata,omitempty"`
+
+	// Issuing settings of the serving certificates of the driver components
+	//+kubebuilder:validation:Optional
+	Certificates *CertificatesSpec `json:"certificates,omitempty"`
+
+	// Prometheus monitoring settings. A ServiceMonitor and a PrometheusRule are created
+	// when the monitoring.coreos.com CRDs are installed. Requires liveness to be set
+	//+kubebuilder:validation:Optional
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+
+	// Volume health monitoring settings, abnormal volume conditions are reported as events
+	// on the affected PVCs. Supported by the rbd and cephfs drivers only, disabled when not set
+	//+kubebuilder:validation:Optional
+	VolumeHealth *VolumeHealthSpec `json:"volumeHealth,omitempty"`
+
 	// Select between between cephfs kernel driver and ceph-fuse
 	// If you select a non-kernel client, your application may be disrupted during upgrade.
 	// See the upgrade guide: https://rook.io/docs/rook/latest/ceph-upgrade.html
@@ -397,8 +884,125 @@ type DriverSpec struct {
 	FuseMountOptions map[string]string `json:"fuseMountOptions,omitempty"`
 }
 
+const (
+	// DriverPatchesAppliedCondition reports whether all user defined workload patches
+	// were successfully applied on top of the generated workloads
+	DriverPatchesAppliedCondition = "PatchesApplied"
+)
+
+const (
+	// All user defined workload patches were applied
+	DriverPatchesAppliedReason = "PatchesApplied"
+
+	// At least one user defined workload patch could not be applied
+	DriverPatchFailedReason = "PatchFailed"
+
+	// A patched workload failed to reconcile, its patches may not be applied
+	DriverWorkloadReconcileFailedReason = "WorkloadReconcileFailed"
+)
+
+// ContainerArgsStatus records the final list of arguments of a driver container
+type ContainerArgsStatus struct {
+	// Name of the deployment or daemonset running the container
+	Workload string `json:"workload"`
+
+	// Name of the container
+	Container string `json:"container"`
+
+	// The list of arguments, after merging user provided extra arguments
+	//+kubebuilder:validation:Optional
+	Args []string `json:"args,omitempty"`
+}
+
+// PlatformType is the Kubernetes distribution a driver is deployed on
+type PlatformType string
+
+const (
+	KubernetesPlatformType PlatformType = "Kubernetes"
+	OpenShiftPlatformType  PlatformType = "OpenShift"
+	K3sPlatformType        PlatformType = "K3s"
+	K0sPlatformType        PlatformType = "K0s"
+	MicroK8sPlatformType   PlatformType = "MicroK8s"
+	RKE2PlatformType       PlatformType = "RKE2"
+)
+
+// KubeletDirPathMismatchStatus reports nodes on which the kubelet root directory detected
+// by the operator differs from the one used by the node plugin daemonset scheduled on them
+type KubeletDirPathMismatchStatus struct {
+	// Name of the node plugin daemonset
+	DaemonSet string `json:"daemonSet"`
+
+	// Name of the node pool, empty for the default node plugin daemonset
+	//+kubebuilder:validation:Optional
+	NodePool string `json:"nodePool,omitempty"`
+
+	// The kubelet root directory used by the node plugin daemonset
+	KubeletDirPath string `json:"kubeletDirPath"`
+
+	// The kubelet root directory detected on the nodes
+	DetectedKubeletDirPath string `json:"detectedKubeletDirPath"`
+
+	// Number of mismatching nodes
+	NodeCount int `json:"nodeCount"`
+
+	// Names of the first mismatching nodes, in alphabetical order
+	//+kubebuilder:validation:Optional
+	Nodes []string `json:"nodes,omitempty"`
+}
+
+// CertificateStatus reports the serving certificate of a driver component
+type CertificateStatus struct {
+	// The driver component serving the certificate, one of csi-addons, snapshot-metadata
+	// or metrics
+	Component string `json:"component"`
+
+	// Name of the secret holding the certificate
+	SecretName string `json:"secretName"`
+
+	// Issuer of the certificate
+	Issuer CertificateIssuerType `json:"issuer"`
+
+	// Expiry time of the certificate, not set while the certificate is not issued yet
+	//+kubebuilder:validation:Optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// Time the operator renews the certificate, set for certificates of the Internal issuer
+	//+kubebuilder:validation:Optional
+	RenewalTime *metav1.Time `json:"renewalTime,omitempty"`
+}
+
 // DriverStatus defines the observed state of Driver
 type DriverStatus struct {
+	// The platform detected from the cluster nodes and the API server version
+	//+kubebuilder:validation:Optional
+	Platform PlatformType `json:"platform,omitempty"`
+
+	// Node plugin daemonsets using a kubelet root directory that differs from the one
+	// detected on the nodes they are scheduled on
+	//+kubebuilder:validation:Optional
+	//+listType=map
+	//+listMapKey=daemonSet
+	//+listMapKey=detectedKubeletDirPath
+	KubeletDirPathMismatches []KubeletDirPathMismatchStatus `json:"kubeletDirPathMismatches,omitempty"`
+
+	// Conditions represent the latest available observations of the driver's state
+	//+kubebuilder:validation:Optional
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The final list of arguments of each of the driver containers
+	//+kubebuilder:validation:Optional
+	//+listType=map
+	//+listMapKey=workload
+	//+listMapKey=container
+	ContainerArgs []ContainerArgsStatus `json:"containerArgs,omitempty"`
+
+	// The serving certificates of the driver components
+	//+kubebuilder:validation:Optional
+	//+listType=map
+	//+listMapKey=component
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -406,6 +1010,12 @@ type DriverStatus struct {
 //+kubebuilder:subresource:status
 
 // +kubebuilder:validation:XValidation:rule=self.metadata.name.matches('^(.+\\.)?(rbd|cephfs|nfs|nvmeof)?\\.csi\\.ceph\\.com$'),message=".metadata.name must match: '[<prefix>.](rbd|cephfs|nfs|nvmeof).csi.ceph.com'"
+// +kubebuilder:validation:XValidation:rule=!has(self.spec) || !has(self.spec.storageCapacity) || !self.spec.storageCapacity || self.metadata.name.matches('^(.+\\.)?(rbd|cephfs)\\.csi\\.ceph\\.com$'),message=".spec.storageCapacity is supported by the rbd and cephfs drivers only"
+// +kubebuilder:validation:XValidation:rule=!has(self.spec) || !has(self.spec.volumeLifecycleModes) || !('Ephemeral' in self.spec.volumeLifecycleModes) || self.metadata.name.matches('^(.+\\.)?(rbd|cephfs)\\.csi\\.ceph\\.com$'),message="the Ephemeral volume lifecycle mode is supported by the rbd and cephfs drivers only"
+// +kubebuilder:validation:XValidation:rule=!has(self.spec) || !has(self.spec.volumeHealth) || self.metadata.name.matches('^(.+\\.)?(rbd|cephfs)\\.csi\\.ceph\\.com$'),message=".spec.volumeHealth is supported by the rbd and cephfs drivers only"
+// +kubebuilder:validation:XValidation:rule=!has(self.spec) || !has(self.spec.volumeHealth) || !has(self.spec.volumeHealth.nodeReporting) || !self.spec.volumeHealth.nodeReporting || (has(self.spec.csiAddons) && (!has(self.spec.csiAddons.enabled) || self.spec.csiAddons.enabled)) || (!has(self.spec.csiAddons) && has(self.spec.deployCsiAddons) && self.spec.deployCsiAddons),message=".spec.volumeHealth.nodeReporting requires CSI-Addons to be deployed"
+// +kubebuilder:validation:XValidation:rule=!has(self.spec) || !has(self.spec.snapshotMetadata) || self.metadata.name.matches('^(.+\\.)?rbd\\.csi\\.ceph\\.com$'),message=".spec.snapshotMetadata is supported by the rbd driver only"
+// +kubebuilder:validation:XValidation:rule=!has(self.spec) || !has(self.spec.volumeLifecycleModes) || !('Ephemeral' in self.spec.volumeLifecycleModes) || !has(self.spec.podInfoOnMount) || self.spec.podInfoOnMount,message="the Ephemeral volume lifecycle mode requires .spec.podInfoOnMount"
 // Driver is the Schema for the drivers API
 type Driver struct {
 	metav1.TypeMeta   `json:",inline"`
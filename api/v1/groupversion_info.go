@@ -46,6 +46,7 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&OperatorConfig{}, &OperatorConfigList{},
 		&Driver{}, &DriverList{},
 		&ClientProfileReplication{}, &ClientProfileReplicationList{},
+		&ClientProfileReplicationPair{}, &ClientProfileReplicationPairList{},
 	)
 	metav1.AddToGroupVersion(scheme, GroupVersion)
 	return nil
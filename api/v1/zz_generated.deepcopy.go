@@ -23,6 +23,8 @@ package v1
 import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -45,6 +47,28 @@ func (in BlockPoolIdPair) DeepCopy() BlockPoolIdPair {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlockPoolNamesSpec) DeepCopyInto(out *BlockPoolNamesSpec) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlockPoolNamesSpec.
+func (in *BlockPoolNamesSpec) DeepCopy() *BlockPoolNamesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BlockPoolNamesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CephConnection) DeepCopyInto(out *CephConnection) {
 	*out = *in
@@ -161,6 +185,56 @@ func (in *CephCsiSecretsSpec) DeepCopy() *CephCsiSecretsSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephFSReplicationPairSpec) DeepCopyInto(out *CephFSReplicationPairSpec) {
+	*out = *in
+	if in.Filesystems != nil {
+		in, out := &in.Filesystems, &out.Filesystems
+		*out = make([]ReplicationPairFilesystemSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.SubvolumeGroups != nil {
+		in, out := &in.SubvolumeGroups, &out.SubvolumeGroups
+		*out = make([]ReplicationPairSubvolumeGroupSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFSReplicationPairSpec.
+func (in *CephFSReplicationPairSpec) DeepCopy() *CephFSReplicationPairSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CephFSReplicationPairSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephFSReplicationSpec) DeepCopyInto(out *CephFSReplicationSpec) {
+	*out = *in
+	if in.FilesystemMapping != nil {
+		in, out := &in.FilesystemMapping, &out.FilesystemMapping
+		*out = make([]FilesystemMappingSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.SubvolumeGroupMapping != nil {
+		in, out := &in.SubvolumeGroupMapping, &out.SubvolumeGroupMapping
+		*out = make([]SubvolumeGroupMappingSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFSReplicationSpec.
+func (in *CephFSReplicationSpec) DeepCopy() *CephFSReplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CephFSReplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CephFsConfigSpec) DeepCopyInto(out *CephFsConfigSpec) {
 	*out = *in
@@ -200,13 +274,81 @@ func (in *CephFsConfigSpec) DeepCopy() *CephFsConfigSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerReference) DeepCopyInto(out *CertManagerIssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerReference.
+func (in *CertManagerIssuerReference) DeepCopy() *CertManagerIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
+	*out = *in
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.RenewalTime != nil {
+		in, out := &in.RenewalTime, &out.RenewalTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificatesSpec) DeepCopyInto(out *CertificatesSpec) {
+	*out = *in
+	if in.CertManagerIssuerRef != nil {
+		in, out := &in.CertManagerIssuerRef, &out.CertManagerIssuerRef
+		*out = new(CertManagerIssuerReference)
+		**out = **in
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatesSpec.
+func (in *CertificatesSpec) DeepCopy() *CertificatesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificatesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientProfile) DeepCopyInto(out *ClientProfile) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientProfile.
@@ -265,7 +407,7 @@ func (in *ClientProfileMapping) DeepCopyInto(out *ClientProfileMapping) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientProfileMapping.
@@ -328,6 +470,11 @@ func (in *ClientProfileMappingSpec) DeepCopyInto(out *ClientProfileMappingSpec)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(MappingMigrationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientProfileMappingSpec.
@@ -343,6 +490,23 @@ func (in *ClientProfileMappingSpec) DeepCopy() *ClientProfileMappingSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientProfileMappingStatus) DeepCopyInto(out *ClientProfileMappingStatus) {
 	*out = *in
+	if in.MappingErrors != nil {
+		in, out := &in.MappingErrors, &out.MappingErrors
+		*out = make([]MappingErrorStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(MappingMigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientProfileMappingStatus.
@@ -361,7 +525,7 @@ func (in *ClientProfileReplication) DeepCopyInto(out *ClientProfileReplication)
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientProfileReplication.
@@ -414,6 +578,112 @@ func (in *ClientProfileReplicationList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientProfileReplicationPair) DeepCopyInto(out *ClientProfileReplicationPair) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientProfileReplicationPair.
+func (in *ClientProfileReplicationPair) DeepCopy() *ClientProfileReplicationPair {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientProfileReplicationPair)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClientProfileReplicationPair) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientProfileReplicationPairList) DeepCopyInto(out *ClientProfileReplicationPairList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClientProfileReplicationPair, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientProfileReplicationPairList.
+func (in *ClientProfileReplicationPairList) DeepCopy() *ClientProfileReplicationPairList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientProfileReplicationPairList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClientProfileReplicationPairList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientProfileReplicationPairSpec) DeepCopyInto(out *ClientProfileReplicationPairSpec) {
+	*out = *in
+	if in.RBD != nil {
+		in, out := &in.RBD, &out.RBD
+		*out = new(RBDReplicationPairSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CephFS != nil {
+		in, out := &in.CephFS, &out.CephFS
+		*out = new(CephFSReplicationPairSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientProfileReplicationPairSpec.
+func (in *ClientProfileReplicationPairSpec) DeepCopy() *ClientProfileReplicationPairSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientProfileReplicationPairSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientProfileReplicationPairStatus) DeepCopyInto(out *ClientProfileReplicationPairStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientProfileReplicationPairStatus.
+func (in *ClientProfileReplicationPairStatus) DeepCopy() *ClientProfileReplicationPairStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientProfileReplicationPairStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientProfileReplicationSpec) DeepCopyInto(out *ClientProfileReplicationSpec) {
 	*out = *in
@@ -422,6 +692,11 @@ func (in *ClientProfileReplicationSpec) DeepCopyInto(out *ClientProfileReplicati
 		*out = new(RBDReplicationSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CephFS != nil {
+		in, out := &in.CephFS, &out.CephFS
+		*out = new(CephFSReplicationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientProfileReplicationSpec.
@@ -437,6 +712,13 @@ func (in *ClientProfileReplicationSpec) DeepCopy() *ClientProfileReplicationSpec
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientProfileReplicationStatus) DeepCopyInto(out *ClientProfileReplicationStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientProfileReplicationStatus.
@@ -473,6 +755,11 @@ func (in *ClientProfileSpec) DeepCopyInto(out *ClientProfileSpec) {
 		*out = new(NvmeofConfigSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.InlineVolumes != nil {
+		in, out := &in.InlineVolumes, &out.InlineVolumes
+		*out = new(InlineVolumesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientProfileSpec.
@@ -488,6 +775,13 @@ func (in *ClientProfileSpec) DeepCopy() *ClientProfileSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientProfileStatus) DeepCopyInto(out *ClientProfileStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientProfileStatus.
@@ -500,6 +794,75 @@ func (in *ClientProfileStatus) DeepCopy() *ClientProfileStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerArgsStatus) DeepCopyInto(out *ContainerArgsStatus) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerArgsStatus.
+func (in *ContainerArgsStatus) DeepCopy() *ContainerArgsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerArgsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerOverridesSpec) DeepCopyInto(out *ContainerOverridesSpec) {
+	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]corev1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerOverridesSpec.
+func (in *ContainerOverridesSpec) DeepCopy() *ContainerOverridesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerOverridesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControllerPluginResourcesSpec) DeepCopyInto(out *ControllerPluginResourcesSpec) {
 	*out = *in
@@ -601,6 +964,18 @@ func (in *ControllerPluginSpec) DeepCopyInto(out *ControllerPluginSpec) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.ContainerOverrides != nil {
+		in, out := &in.ContainerOverrides, &out.ContainerOverrides
+		*out = make(map[string]ContainerOverridesSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]WorkloadPatchSpec, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerPluginSpec.
@@ -614,25 +989,150 @@ func (in *ControllerPluginSpec) DeepCopy() *ControllerPluginSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Driver) DeepCopyInto(out *Driver) {
+func (in *CsiAddonsFeaturesSpec) DeepCopyInto(out *CsiAddonsFeaturesSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Driver.
-func (in *Driver) DeepCopy() *Driver {
-	if in == nil {
-		return nil
+	if in.ReclaimSpace != nil {
+		in, out := &in.ReclaimSpace, &out.ReclaimSpace
+		*out = new(bool)
+		**out = **in
 	}
-	out := new(Driver)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+	if in.KeyRotation != nil {
+		in, out := &in.KeyRotation, &out.KeyRotation
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NetworkFence != nil {
+		in, out := &in.NetworkFence, &out.NetworkFence
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VolumeReplication != nil {
+		in, out := &in.VolumeReplication, &out.VolumeReplication
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VolumeGroupReplication != nil {
+		in, out := &in.VolumeGroupReplication, &out.VolumeGroupReplication
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VolumeCondition != nil {
+		in, out := &in.VolumeCondition, &out.VolumeCondition
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CsiAddonsFeaturesSpec.
+func (in *CsiAddonsFeaturesSpec) DeepCopy() *CsiAddonsFeaturesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CsiAddonsFeaturesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CsiAddonsSidecarSpec) DeepCopyInto(out *CsiAddonsSidecarSpec) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CsiAddonsSidecarSpec.
+func (in *CsiAddonsSidecarSpec) DeepCopy() *CsiAddonsSidecarSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CsiAddonsSidecarSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CsiAddonsSpec) DeepCopyInto(out *CsiAddonsSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = new(CsiAddonsFeaturesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Controller != nil {
+		in, out := &in.Controller, &out.Controller
+		*out = new(CsiAddonsSidecarSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Node != nil {
+		in, out := &in.Node, &out.Node
+		*out = new(CsiAddonsSidecarSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(CsiAddonsTLSSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CsiAddonsSpec.
+func (in *CsiAddonsSpec) DeepCopy() *CsiAddonsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CsiAddonsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CsiAddonsTLSSpec) DeepCopyInto(out *CsiAddonsTLSSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CsiAddonsTLSSpec.
+func (in *CsiAddonsTLSSpec) DeepCopy() *CsiAddonsTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CsiAddonsTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Driver) DeepCopyInto(out *Driver) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Driver.
+func (in *Driver) DeepCopy() *Driver {
+	if in == nil {
+		return nil
+	}
+	out := new(Driver)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
 func (in *Driver) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
@@ -725,10 +1225,47 @@ func (in *DriverSpec) DeepCopyInto(out *DriverSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.PodInfoOnMount != nil {
+		in, out := &in.PodInfoOnMount, &out.PodInfoOnMount
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SeLinuxMount != nil {
+		in, out := &in.SeLinuxMount, &out.SeLinuxMount
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TokenRequests != nil {
+		in, out := &in.TokenRequests, &out.TokenRequests
+		*out = make([]storagev1.TokenRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RequiresRepublish != nil {
+		in, out := &in.RequiresRepublish, &out.RequiresRepublish
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StorageCapacity != nil {
+		in, out := &in.StorageCapacity, &out.StorageCapacity
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VolumeLifecycleModes != nil {
+		in, out := &in.VolumeLifecycleModes, &out.VolumeLifecycleModes
+		*out = make([]storagev1.VolumeLifecycleMode, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeAllocatableUpdatePeriodSeconds != nil {
+		in, out := &in.NodeAllocatableUpdatePeriodSeconds, &out.NodeAllocatableUpdatePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
 	if in.Liveness != nil {
 		in, out := &in.Liveness, &out.Liveness
 		*out = new(LivenessSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.LeaderElection != nil {
 		in, out := &in.LeaderElection, &out.LeaderElection
@@ -740,6 +1277,31 @@ func (in *DriverSpec) DeepCopyInto(out *DriverSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.CsiAddons != nil {
+		in, out := &in.CsiAddons, &out.CsiAddons
+		*out = new(CsiAddonsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SnapshotMetadata != nil {
+		in, out := &in.SnapshotMetadata, &out.SnapshotMetadata
+		*out = new(SnapshotMetadataSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = new(CertificatesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(MonitoringSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeHealth != nil {
+		in, out := &in.VolumeHealth, &out.VolumeHealth
+		*out = new(VolumeHealthSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.KernelMountOptions != nil {
 		in, out := &in.KernelMountOptions, &out.KernelMountOptions
 		*out = make(map[string]string, len(*in))
@@ -769,6 +1331,34 @@ func (in *DriverSpec) DeepCopy() *DriverSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DriverStatus) DeepCopyInto(out *DriverStatus) {
 	*out = *in
+	if in.KubeletDirPathMismatches != nil {
+		in, out := &in.KubeletDirPathMismatches, &out.KubeletDirPathMismatches
+		*out = make([]KubeletDirPathMismatchStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ContainerArgs != nil {
+		in, out := &in.ContainerArgs, &out.ContainerArgs
+		*out = make([]ContainerArgsStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]CertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverStatus.
@@ -797,6 +1387,82 @@ func (in *EncryptionSpec) DeepCopy() *EncryptionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtraContainerSpec) DeepCopyInto(out *ExtraContainerSpec) {
+	*out = *in
+	in.Container.DeepCopyInto(&out.Container)
+	if in.BuiltinVolumeMounts != nil {
+		in, out := &in.BuiltinVolumeMounts, &out.BuiltinVolumeMounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtraContainerSpec.
+func (in *ExtraContainerSpec) DeepCopy() *ExtraContainerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtraContainerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemMappingSpec) DeepCopyInto(out *FilesystemMappingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemMappingSpec.
+func (in *FilesystemMappingSpec) DeepCopy() *FilesystemMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InlineVolumesSpec) DeepCopyInto(out *InlineVolumesSpec) {
+	*out = *in
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InlineVolumesSpec.
+func (in *InlineVolumesSpec) DeepCopy() *InlineVolumesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InlineVolumesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletDirPathMismatchStatus) DeepCopyInto(out *KubeletDirPathMismatchStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletDirPathMismatchStatus.
+func (in *KubeletDirPathMismatchStatus) DeepCopy() *KubeletDirPathMismatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletDirPathMismatchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LeaderElectionSpec) DeepCopyInto(out *LeaderElectionSpec) {
 	*out = *in
@@ -815,6 +1481,16 @@ func (in *LeaderElectionSpec) DeepCopy() *LeaderElectionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LivenessSpec) DeepCopyInto(out *LivenessSpec) {
 	*out = *in
+	if in.HealthPort != nil {
+		in, out := &in.HealthPort, &out.HealthPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LivenessSpec.
@@ -863,6 +1539,68 @@ func (in *LogSpec) DeepCopy() *LogSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MappingErrorStatus) DeepCopyInto(out *MappingErrorStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MappingErrorStatus.
+func (in *MappingErrorStatus) DeepCopy() *MappingErrorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MappingErrorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MappingMigrationSpec) DeepCopyInto(out *MappingMigrationSpec) {
+	*out = *in
+	if in.BlockPoolNames != nil {
+		in, out := &in.BlockPoolNames, &out.BlockPoolNames
+		*out = make([]BlockPoolNamesSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MappingMigrationSpec.
+func (in *MappingMigrationSpec) DeepCopy() *MappingMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MappingMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MappingMigrationStatus) DeepCopyInto(out *MappingMigrationStatus) {
+	*out = *in
+	if in.ClientProfileReplications != nil {
+		in, out := &in.ClientProfileReplications, &out.ClientProfileReplications
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnsupportedMappings != nil {
+		in, out := &in.UnsupportedMappings, &out.UnsupportedMappings
+		*out = make([]MappingErrorStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MappingMigrationStatus.
+func (in *MappingMigrationStatus) DeepCopy() *MappingMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MappingMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MappingsSpec) DeepCopyInto(out *MappingsSpec) {
 	*out = *in
@@ -889,6 +1627,83 @@ func (in *MappingsSpec) DeepCopy() *MappingsSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringAlertsSpec) DeepCopyInto(out *MonitoringAlertsSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PluginDownFor != nil {
+		in, out := &in.PluginDownFor, &out.PluginDownFor
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.GrpcErrorRatioPercent != nil {
+		in, out := &in.GrpcErrorRatioPercent, &out.GrpcErrorRatioPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.GrpcErrorRatioFor != nil {
+		in, out := &in.GrpcErrorRatioFor, &out.GrpcErrorRatioFor
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.NodePluginMissingFor != nil {
+		in, out := &in.NodePluginMissingFor, &out.NodePluginMissingFor
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringAlertsSpec.
+func (in *MonitoringAlertsSpec) DeepCopy() *MonitoringAlertsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringAlertsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SidecarMetricsPort != nil {
+		in, out := &in.SidecarMetricsPort, &out.SidecarMetricsPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = new(MonitoringAlertsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSpec.
+func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NfsConfigSpec) DeepCopyInto(out *NfsConfigSpec) {
 	*out = *in
@@ -980,6 +1795,25 @@ func (in *NodePluginSpec) DeepCopyInto(out *NodePluginSpec) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.ContainerOverrides != nil {
+		in, out := &in.ContainerOverrides, &out.ContainerOverrides
+		*out = make(map[string]ContainerOverridesSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePoolSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]WorkloadPatchSpec, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePluginSpec.
@@ -992,6 +1826,55 @@ func (in *NodePluginSpec) DeepCopy() *NodePluginSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolSpec) DeepCopyInto(out *NodePoolSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(appsv1.DaemonSetUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KernelMountOptions != nil {
+		in, out := &in.KernelMountOptions, &out.KernelMountOptions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.FuseMountOptions != nil {
+		in, out := &in.FuseMountOptions, &out.FuseMountOptions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolSpec.
+func (in *NodePoolSpec) DeepCopy() *NodePoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NvmeofConfigSpec) DeepCopyInto(out *NvmeofConfigSpec) {
 	*out = *in
@@ -1172,6 +2055,20 @@ func (in *PodCommonSpec) DeepCopyInto(out *PodCommonSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ExtraContainers != nil {
+		in, out := &in.ExtraContainers, &out.ExtraContainers
+		*out = make([]ExtraContainerSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]ExtraContainerSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodCommonSpec.
@@ -1199,6 +2096,26 @@ func (in *PoolMappingSpec) DeepCopy() *PoolMappingSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBDReplicationPairSpec) DeepCopyInto(out *RBDReplicationPairSpec) {
+	*out = *in
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]ReplicationPairPoolSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBDReplicationPairSpec.
+func (in *RBDReplicationPairSpec) DeepCopy() *RBDReplicationPairSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RBDReplicationPairSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RBDReplicationSpec) DeepCopyInto(out *RBDReplicationSpec) {
 	*out = *in
@@ -1259,6 +2176,111 @@ func (in *ReadAffinitySpec) DeepCopy() *ReadAffinitySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationPairFilesystemSpec) DeepCopyInto(out *ReplicationPairFilesystemSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationPairFilesystemSpec.
+func (in *ReplicationPairFilesystemSpec) DeepCopy() *ReplicationPairFilesystemSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationPairFilesystemSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationPairPoolSpec) DeepCopyInto(out *ReplicationPairPoolSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationPairPoolSpec.
+func (in *ReplicationPairPoolSpec) DeepCopy() *ReplicationPairPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationPairPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationPairSubvolumeGroupSpec) DeepCopyInto(out *ReplicationPairSubvolumeGroupSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationPairSubvolumeGroupSpec.
+func (in *ReplicationPairSubvolumeGroupSpec) DeepCopy() *ReplicationPairSubvolumeGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationPairSubvolumeGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountReference) DeepCopyInto(out *ServiceAccountReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountReference.
+func (in *ServiceAccountReference) DeepCopy() *ServiceAccountReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotMetadataSpec) DeepCopyInto(out *SnapshotMetadataSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientServiceAccounts != nil {
+		in, out := &in.ClientServiceAccounts, &out.ClientServiceAccounts
+		*out = make([]ServiceAccountReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManageClientTokens != nil {
+		in, out := &in.ManageClientTokens, &out.ManageClientTokens
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotMetadataSpec.
+func (in *SnapshotMetadataSpec) DeepCopy() *SnapshotMetadataSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotMetadataSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubvolumeGroupMappingSpec) DeepCopyInto(out *SubvolumeGroupMappingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubvolumeGroupMappingSpec.
+func (in *SubvolumeGroupMappingSpec) DeepCopy() *SubvolumeGroupMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SubvolumeGroupMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TopologySpec) DeepCopyInto(out *TopologySpec) {
 	*out = *in
@@ -1279,6 +2301,36 @@ func (in *TopologySpec) DeepCopy() *TopologySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeHealthSpec) DeepCopyInto(out *VolumeHealthSpec) {
+	*out = *in
+	if in.MonitorInterval != nil {
+		in, out := &in.MonitorInterval, &out.MonitorInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeReporting != nil {
+		in, out := &in.NodeReporting, &out.NodeReporting
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeHealthSpec.
+func (in *VolumeHealthSpec) DeepCopy() *VolumeHealthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeHealthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeSpec) DeepCopyInto(out *VolumeSpec) {
 	*out = *in
@@ -1295,3 +2347,18 @@ func (in *VolumeSpec) DeepCopy() *VolumeSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadPatchSpec) DeepCopyInto(out *WorkloadPatchSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadPatchSpec.
+func (in *WorkloadPatchSpec) DeepCopy() *WorkloadPatchSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadPatchSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -17,25 +17,31 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
@@ -45,6 +51,13 @@ import (
 	//+kubebuilder:scaffold:imports
 )
 
+const (
+	// Identical events recorded on an object within this window are dropped
+	eventDeduplicationWindow = 10 * time.Minute
+	// Upper bound for exporting the pending traces on exit
+	tracingShutdownTimeout = 10 * time.Second
+)
+
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
@@ -65,6 +78,7 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var tlsOpts []func(*tls.Config)
+	var tracingOpts utils.TracingOptions
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -79,6 +93,15 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics")
+	flag.StringVar(&tracingOpts.Endpoint, "tracing-endpoint", "",
+		"The OTLP gRPC endpoint (host:port) reconcile traces are exported to. Tracing is disabled when neither "+
+			"--tracing-endpoint nor --tracing-file is set.")
+	flag.BoolVar(&tracingOpts.Insecure, "tracing-insecure", false,
+		"If set, traces are exported to the OTLP endpoint without TLS.")
+	flag.StringVar(&tracingOpts.File, "tracing-file", "",
+		"A file reconcile traces are appended to as JSON.")
+	flag.Float64Var(&tracingOpts.SamplingRatio, "tracing-sampling-ratio", 1,
+		"The fraction of reconcile iterations that are traced, between 0 and 1.")
 
 	opts := zap.Options{
 		Development: true,
@@ -183,42 +206,77 @@ func main() {
 		// after the manager stops then its usage might be unsafe.
 		// LeaderElectionReleaseOnCancel: true,
 		Cache: cache.Options{DefaultNamespaces: defaultNamespaces},
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				// The snapshot-metadata client roles live in the namespaces of the backup
+				// applications, which are not cached
+				DisableFor: []client.Object{&rbacv1.Role{}, &rbacv1.RoleBinding{}},
+			},
+		},
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	var shutdownTracing func(context.Context) error
+	reconcilerClient := mgr.GetClient()
+	if tracingOpts.Enabled() {
+		setupLog.Info("Enabling reconcile tracing", "endpoint", tracingOpts.Endpoint, "file", tracingOpts.File)
+		if shutdownTracing, err = utils.SetupTracing(context.Background(), tracingOpts); err != nil {
+			setupLog.Error(err, "unable to set up tracing")
+			os.Exit(1)
+		}
+		reconcilerClient = utils.NewTracingClient(reconcilerClient)
+	}
+
 	if err = (&controller.DriverReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   reconcilerClient,
+		Scheme:   mgr.GetScheme(),
+		Recorder: newEventRecorder(mgr, "driver-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Driver")
 		os.Exit(1)
 	}
 	if err = (&controller.ClientProfileReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   reconcilerClient,
+		Scheme:   mgr.GetScheme(),
+		Recorder: newEventRecorder(mgr, "clientprofile-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ClientProfile")
 		os.Exit(1)
 	}
 	if err = (&controller.ClientProfileMappingReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   reconcilerClient,
+		Scheme:   mgr.GetScheme(),
+		Recorder: newEventRecorder(mgr, "clientprofilemapping-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ClientProfileMapping")
 		os.Exit(1)
 	}
 	if err := (&controller.ClientProfileReplicationReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   reconcilerClient,
+		Scheme:   mgr.GetScheme(),
+		Recorder: newEventRecorder(mgr, "clientprofilereplication-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "Failed to create controller", "controller", "ClientProfileReplication")
 		os.Exit(1)
 	}
+	if err := (&controller.ClientProfileReplicationPairReconciler{
+		Client:   reconcilerClient,
+		Scheme:   mgr.GetScheme(),
+		Recorder: newEventRecorder(mgr, "clientprofilereplicationpair-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "Failed to create controller", "controller", "ClientProfileReplicationPair")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
+	if err := controller.RegisterMetrics(metrics.Registry, mgr.GetClient()); err != nil {
+		setupLog.Error(err, "unable to register operator metrics")
+		os.Exit(1)
+	}
+
 	if metricsCertWatcher != nil {
 		setupLog.Info("Adding metrics certificate watcher to manager")
 		if err := mgr.Add(metricsCertWatcher); err != nil {
@@ -237,12 +295,25 @@ func main() {
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctrl.SetupSignalHandler())
+	if shutdownTracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		if err := shutdownTracing(ctx); err != nil {
+			setupLog.Error(err, "failed to flush traces")
+		}
+		cancel()
+	}
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
 
+// newEventRecorder returns a deduplicating event recorder for the named controller
+func newEventRecorder(mgr ctrl.Manager, name string) events.EventRecorder {
+	return utils.NewDeduplicatingEventRecorder(mgr.GetEventRecorder(name), eventDeduplicationWindow)
+}
+
 // getWatchNamespace returns the Namespace the operator should be watching for changes
 func getWatchNamespace() (string, error) {
 	var watchNamespaceEnvVar = "WATCH_NAMESPACE"
@@ -0,0 +1,523 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	csiv1 "github.com/ceph/ceph-csi-operator/api/v1"
+	"github.com/ceph/ceph-csi-operator/internal/utils"
+)
+
+// Driver components serving TLS
+const (
+	csiAddonsCertificateComponent        = "csi-addons"
+	snapshotMetadataCertificateComponent = "snapshot-metadata"
+	metricsCertificateComponent          = "metrics"
+)
+
+const (
+	defaultCertificateDuration    = 90 * 24 * time.Hour
+	defaultCertificateRenewBefore = 30 * 24 * time.Hour
+	certificateAuthorityValidity  = 10 * 365 * 24 * time.Hour
+
+	// Key of the CA certificate in secrets and config maps, as used by cert-manager
+	caCertKey = "ca.crt"
+	// Key of the previous CA certificate, trusted until it expires after a CA rotation
+	previousCACertKey = "previous.crt"
+
+	// Pod annotation rolling driver pods when a mounted certificate is renewed
+	certificatesHashAnnotationKey = "csi.ceph.io/certificates-hash"
+	// Annotation set by cert-manager on the secrets it manages
+	certManagerCertificateNameAnnotationKey = "cert-manager.io/certificate-name"
+
+	// Delay before checking again for a certificate that is being issued by cert-manager
+	certManagerPendingRequeueDelay = 30 * time.Second
+
+	certificateIssuedReason = "CertificateIssued"
+)
+
+var certManagerCertificateGroupVersionKind = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+// certificateRequest describes the serving certificate of a driver component
+type certificateRequest struct {
+	component  string
+	secretName string
+	// Whether the certificate is issued by the operator or cert-manager, user provided otherwise
+	managed  bool
+	dnsNames []string
+}
+
+// certificateSecretName returns the name of the secret holding the managed certificate of a component
+func (r *driverReconcile) certificateSecretName(component string) string {
+	return r.generateName(component + "-tls")
+}
+
+// serviceDNSNames returns the in cluster DNS names of a service in the driver namespace
+func (r *driverReconcile) serviceDNSNames(serviceName string) []string {
+	return []string{
+		fmt.Sprintf("%s.%s.svc", serviceName, r.driver.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", serviceName, r.driver.Namespace),
+	}
+}
+
+// certificateRequests returns the serving certificates required by the driver components
+func (r *driverReconcile) certificateRequests() []certificateRequest {
+	requests := []certificateRequest{}
+
+	if csiAddons := r.csiAddonsConfig(); csiAddons.enabled && csiAddons.tls != nil {
+		requests = append(requests, certificateRequest{
+			component:  csiAddonsCertificateComponent,
+			secretName: csiAddons.tlsSecretName,
+			managed:    csiAddons.tls.SecretName == "",
+			dnsNames: slices.Concat(
+				r.serviceDNSNames(r.generateServiceName("ctrlplugin")),
+				r.serviceDNSNames(r.generateServiceName("nodeplugin-csi-addons")),
+			),
+		})
+	}
+	if snapshotMetadata := r.driver.Spec.SnapshotMetadata; r.isRbdDriver() && snapshotMetadata != nil {
+		requests = append(requests, certificateRequest{
+			component:  snapshotMetadataCertificateComponent,
+			secretName: r.snapshotMetadataSecretName(),
+			managed:    snapshotMetadata.SecretName == "",
+			dnsNames:   r.serviceDNSNames(r.generateServiceName("snapshot-metadata")),
+		})
+	}
+	if r.metricsTLSEnabled() {
+		ctrlPluginServiceName, nodePluginServiceName := r.metricsServiceNames()
+		requests = append(requests, certificateRequest{
+			component:  metricsCertificateComponent,
+			secretName: r.certificateSecretName(metricsCertificateComponent),
+			managed:    true,
+			dnsNames: slices.Concat(
+				r.serviceDNSNames(ctrlPluginServiceName),
+				r.serviceDNSNames(nodePluginServiceName),
+			),
+		})
+	}
+	return requests
+}
+
+// snapshotMetadataSecretName returns the name of the secret holding the snapshot-metadata certificate
+func (r *driverReconcile) snapshotMetadataSecretName() string {
+	snapshotMetadata := cmp.Or(r.driver.Spec.SnapshotMetadata, &csiv1.SnapshotMetadataSpec{})
+	return cmp.Or(snapshotMetadata.SecretName, r.certificateSecretName(snapshotMetadataCertificateComponent))
+}
+
+// snapshotMetadataLegacyTlsVolumeIndex returns the index of the deprecated tls-key controller plugin
+// volume, which deploys the snapshot-metadata sidecar when snapshotMetadata is not set
+func (r *driverReconcile) snapshotMetadataLegacyTlsVolumeIndex() int {
+	if !r.isRbdDriver() || r.driver.Spec.SnapshotMetadata != nil {
+		return -1
+	}
+	pluginSpec := cmp.Or(r.driver.Spec.ControllerPlugin, &csiv1.ControllerPluginSpec{})
+	return slices.IndexFunc(pluginSpec.Volumes, func(vol csiv1.VolumeSpec) bool {
+		return vol.Volume.Name == "tls-key"
+	})
+}
+
+// metricsTLSEnabled returns whether the liveness metrics are served over TLS
+func (r *driverReconcile) metricsTLSEnabled() bool {
+	return r.driver.Spec.Liveness != nil && ptr.Deref(r.driver.Spec.Liveness.TLS, false)
+}
+
+// reconcileCertificates issues and renews the serving certificates of the driver components, and
+// reports them in the driver status. It runs before the workloads are reconciled, so that pods are
+// rolled when a mounted certificate changes.
+func (r *driverReconcile) reconcileCertificates(ctx context.Context) error {
+	spec := cmp.Or(r.driver.Spec.Certificates, &csiv1.CertificatesSpec{})
+	issuer := cmp.Or(spec.Issuer, csiv1.InternalCertificateIssuer)
+	requests := r.certificateRequests()
+
+	internalIssuer := issuer == csiv1.InternalCertificateIssuer && slices.ContainsFunc(
+		requests,
+		func(req certificateRequest) bool { return req.managed },
+	)
+	var ca *utils.CertificateAuthority
+	var caBundle []byte
+	if internalIssuer {
+		var err error
+		if ca, caBundle, err = r.reconcileCertificateAuthority(ctx); err != nil {
+			return err
+		}
+	} else if err := r.deleteCertificateAuthority(ctx); err != nil {
+		return err
+	}
+
+	statuses := []csiv1.CertificateStatus{}
+	certificateHashes := map[string]string{}
+	for _, req := range requests {
+		var status *csiv1.CertificateStatus
+		var certPEM []byte
+		var err error
+		switch {
+		case !req.managed:
+			status, certPEM, err = r.loadExternalCertificate(ctx, req)
+		case internalIssuer:
+			status, certPEM, err = r.reconcileInternalCertificate(ctx, req, ca, caBundle)
+		default:
+			status, certPEM, err = r.reconcileCertManagerCertificate(ctx, req)
+		}
+		if err != nil {
+			return err
+		}
+		statuses = append(statuses, *status)
+		if len(certPEM) > 0 {
+			hash := sha256.Sum256(certPEM)
+			certificateHashes[req.component] = hex.EncodeToString(hash[:])
+		}
+	}
+
+	// Remove the managed certificates of components that no longer need one
+	for _, component := range []string{
+		csiAddonsCertificateComponent,
+		snapshotMetadataCertificateComponent,
+		metricsCertificateComponent,
+	} {
+		if !slices.ContainsFunc(requests, func(req certificateRequest) bool {
+			return req.managed && req.component == component
+		}) {
+			if err := r.deleteManagedCertificate(ctx, component); err != nil {
+				return err
+			}
+		}
+	}
+
+	r.certificateStatuses = statuses
+	r.certificateHashes = certificateHashes
+	return nil
+}
+
+// certificateDurations returns the validity and renewal period of the issued certificates
+func (r *driverReconcile) certificateDurations() (time.Duration, time.Duration) {
+	spec := cmp.Or(r.driver.Spec.Certificates, &csiv1.CertificatesSpec{})
+	duration := ptr.Deref(spec.Duration, metav1.Duration{Duration: defaultCertificateDuration}).Duration
+	renewBefore := ptr.Deref(spec.RenewBefore, metav1.Duration{Duration: defaultCertificateRenewBefore}).Duration
+	return duration, renewBefore
+}
+
+// requeueBefore makes sure the driver is reconciled again before the given time
+func (r *driverReconcile) requeueBefore(t time.Time) {
+	delay := max(time.Until(t), time.Second)
+	if r.requeueAfter == 0 || delay < r.requeueAfter {
+		r.requeueAfter = delay
+	}
+}
+
+// reconcileCertificateAuthority loads or generates the driver certificate authority, and publishes
+// the bundle of trusted CA certificates to a config map. The CA is rotated when it would expire
+// before a certificate issued now, the previous CA stays trusted until it expires.
+func (r *driverReconcile) reconcileCertificateAuthority(ctx context.Context) (*utils.CertificateAuthority, []byte, error) {
+	secret := &corev1.Secret{}
+	secret.Name = r.generateName("ca")
+	secret.Namespace = r.driver.Namespace
+
+	log := r.log.WithValues("caSecretName", secret.Name)
+	duration, _ := r.certificateDurations()
+	now := time.Now()
+
+	var ca *utils.CertificateAuthority
+	opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if err := ctrlutil.SetControllerReference(&r.driver, secret, r.Scheme); err != nil {
+			return err
+		}
+		current, err := utils.ParseCertificateAuthority(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+		if err == nil && now.Add(duration).Before(current.Cert.NotAfter) {
+			ca = current
+		} else {
+			if ca, err = utils.NewCertificateAuthority(r.driver.Name, certificateAuthorityValidity, now); err != nil {
+				return err
+			}
+			if current != nil && now.Before(current.Cert.NotAfter) {
+				secret.Data = map[string][]byte{previousCACertKey: current.CertPEM}
+			} else {
+				secret.Data = map[string][]byte{}
+			}
+			secret.Data[corev1.TLSCertKey] = ca.CertPEM
+			secret.Data[corev1.TLSPrivateKeyKey] = ca.KeyPEM
+		}
+		if previous, err := utils.ParseCertificate(secret.Data[previousCACertKey]); err != nil || now.After(previous.NotAfter) {
+			delete(secret.Data, previousCACertKey)
+		}
+		secret.Type = corev1.SecretTypeTLS
+		return nil
+	})
+	logCreateOrUpdateResult(log, "certificate authority secret", secret, opResult, err)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.requeueBefore(ca.Cert.NotAfter.Add(-duration))
+
+	caBundle := slices.Concat(ca.CertPEM, secret.Data[previousCACertKey])
+	configMap := &corev1.ConfigMap{}
+	configMap.Name = r.generateName("ca-bundle")
+	configMap.Namespace = r.driver.Namespace
+	opResult, err = ctrlutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if err := ctrlutil.SetControllerReference(&r.driver, configMap, r.Scheme); err != nil {
+			return err
+		}
+		configMap.Data = map[string]string{caCertKey: string(caBundle)}
+		return nil
+	})
+	logCreateOrUpdateResult(log, "CA bundle configmap", configMap, opResult, err)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ca, caBundle, nil
+}
+
+// deleteCertificateAuthority removes the driver certificate authority when no certificate is
+// issued by it anymore
+func (r *driverReconcile) deleteCertificateAuthority(ctx context.Context) error {
+	secret := &corev1.Secret{}
+	secret.Name = r.generateName("ca")
+	secret.Namespace = r.driver.Namespace
+	if err := r.Delete(ctx, secret); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	configMap := &corev1.ConfigMap{}
+	configMap.Name = r.generateName("ca-bundle")
+	configMap.Namespace = r.driver.Namespace
+	return client.IgnoreNotFound(r.Delete(ctx, configMap))
+}
+
+// reconcileInternalCertificate issues a certificate signed by the driver CA, and renews it when it
+// enters its renewal period, its DNS names change or it was signed by a previous CA
+func (r *driverReconcile) reconcileInternalCertificate(
+	ctx context.Context,
+	req certificateRequest,
+	ca *utils.CertificateAuthority,
+	caBundle []byte,
+) (*csiv1.CertificateStatus, []byte, error) {
+	secret := &corev1.Secret{}
+	secret.Name = req.secretName
+	secret.Namespace = r.driver.Namespace
+
+	log := r.log.WithValues("certificateSecretName", secret.Name)
+	duration, renewBefore := r.certificateDurations()
+	now := time.Now()
+
+	var cert *x509.Certificate
+	issued := false
+	opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if err := ctrlutil.SetControllerReference(&r.driver, secret, r.Scheme); err != nil {
+			return err
+		}
+		current, err := utils.ParseCertificate(secret.Data[corev1.TLSCertKey])
+		if err != nil ||
+			!now.Before(current.NotAfter.Add(-renewBefore)) ||
+			!slices.Equal(current.DNSNames, req.dnsNames) ||
+			current.CheckSignatureFrom(ca.Cert) != nil {
+			certPEM, keyPEM, err := ca.IssueServingCertificate(req.dnsNames, duration, now)
+			if err != nil {
+				return err
+			}
+			if current, err = utils.ParseCertificate(certPEM); err != nil {
+				return err
+			}
+			secret.Data = map[string][]byte{
+				corev1.TLSCertKey:       certPEM,
+				corev1.TLSPrivateKeyKey: keyPEM,
+			}
+			issued = true
+		}
+		secret.Data[caCertKey] = caBundle
+		secret.Type = corev1.SecretTypeTLS
+		cert = current
+		return nil
+	})
+	logCreateOrUpdateResult(log, "certificate secret", secret, opResult, err)
+	if err != nil {
+		return nil, nil, err
+	}
+	if issued {
+		r.recordEvent(
+			corev1.EventTypeNormal,
+			certificateIssuedReason,
+			"Issue",
+			"Issued %s certificate in secret %s, valid until %s",
+			req.component,
+			secret.Name,
+			cert.NotAfter.Format(time.RFC3339),
+		)
+	}
+
+	renewalTime := cert.NotAfter.Add(-renewBefore)
+	r.requeueBefore(renewalTime)
+	return &csiv1.CertificateStatus{
+		Component:   req.component,
+		SecretName:  secret.Name,
+		Issuer:      csiv1.InternalCertificateIssuer,
+		NotAfter:    statusTime(cert.NotAfter),
+		RenewalTime: statusTime(renewalTime),
+	}, secret.Data[corev1.TLSCertKey], nil
+}
+
+// reconcileCertManagerCertificate hands the certificate off to cert-manager, which issues and
+// renews it in the requested secret
+func (r *driverReconcile) reconcileCertManagerCertificate(ctx context.Context, req certificateRequest) (*csiv1.CertificateStatus, []byte, error) {
+	spec := cmp.Or(r.driver.Spec.Certificates, &csiv1.CertificatesSpec{})
+	issuerRef := spec.CertManagerIssuerRef
+	if issuerRef == nil {
+		return nil, nil, fmt.Errorf("certManagerIssuerRef is required with the CertManager issuer")
+	}
+	duration, renewBefore := r.certificateDurations()
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certManagerCertificateGroupVersionKind)
+	certificate.SetName(req.secretName)
+	certificate.SetNamespace(r.driver.Namespace)
+
+	log := r.log.WithValues("certificateName", req.secretName)
+	opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, certificate, func() error {
+		if err := ctrlutil.SetControllerReference(&r.driver, certificate, r.Scheme); err != nil {
+			return err
+		}
+		certificate.Object["spec"] = map[string]any{
+			"secretName":  req.secretName,
+			"dnsNames":    utils.MapSlice(req.dnsNames, func(name string) any { return name }),
+			"duration":    duration.String(),
+			"renewBefore": renewBefore.String(),
+			"usages":      []any{"server auth"},
+			"privateKey": map[string]any{
+				"algorithm":      "ECDSA",
+				"size":           int64(256),
+				"rotationPolicy": "Always",
+			},
+			"issuerRef": map[string]any{
+				"name":  issuerRef.Name,
+				"kind":  cmp.Or(issuerRef.Kind, "Issuer"),
+				"group": cmp.Or(issuerRef.Group, certManagerCertificateGroupVersionKind.Group),
+			},
+		}
+		return nil
+	})
+	logCreateOrUpdateResult(log, "cert-manager certificate", certificate, opResult, err)
+	if meta.IsNoMatchError(err) {
+		return nil, nil, fmt.Errorf("the CertManager issuer requires cert-manager to be installed: %w", err)
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	status, certPEM, err := r.loadExternalCertificate(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	status.Issuer = csiv1.CertManagerCertificateIssuer
+	if status.NotAfter == nil {
+		r.requeueBefore(time.Now().Add(certManagerPendingRequeueDelay))
+	}
+	return status, certPEM, nil
+}
+
+// loadExternalCertificate reports a certificate that is not issued by the operator
+func (r *driverReconcile) loadExternalCertificate(ctx context.Context, req certificateRequest) (*csiv1.CertificateStatus, []byte, error) {
+	status := &csiv1.CertificateStatus{
+		Component:  req.component,
+		SecretName: req.secretName,
+		Issuer:     csiv1.ExternalCertificateIssuer,
+	}
+
+	secret := &corev1.Secret{}
+	secret.Name = req.secretName
+	secret.Namespace = r.driver.Namespace
+	if err := r.Get(ctx, client.ObjectKeyFromObject(secret), secret); k8serrors.IsNotFound(err) {
+		return status, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM := secret.Data[corev1.TLSCertKey]
+	if cert, err := utils.ParseCertificate(certPEM); err == nil {
+		status.NotAfter = statusTime(cert.NotAfter)
+	}
+	return status, certPEM, nil
+}
+
+// deleteManagedCertificate removes the certificate secret of a component, along with the
+// cert-manager certificate issuing it
+func (r *driverReconcile) deleteManagedCertificate(ctx context.Context, component string) error {
+	secret := &corev1.Secret{}
+	secret.Name = r.certificateSecretName(component)
+	secret.Namespace = r.driver.Namespace
+	if err := r.Get(ctx, client.ObjectKeyFromObject(secret), secret); k8serrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if secret.Annotations[certManagerCertificateNameAnnotationKey] != "" {
+		certificate := &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(certManagerCertificateGroupVersionKind)
+		certificate.SetName(secret.Annotations[certManagerCertificateNameAnnotationKey])
+		certificate.SetNamespace(r.driver.Namespace)
+		if err := r.Delete(ctx, certificate); client.IgnoreNotFound(err) != nil &&
+			!meta.IsNoMatchError(err) && !runtime.IsNotRegisteredError(err) {
+			return err
+		}
+	} else if !metav1.IsControlledBy(secret, &r.driver) {
+		// Not a secret issued by the operator or cert-manager
+		return nil
+	}
+	return client.IgnoreNotFound(r.Delete(ctx, secret))
+}
+
+// statusTime converts a certificate time to a status time, in the form it is read back from the API
+// server, to keep the status comparable between reconcile iterations
+func statusTime(t time.Time) *metav1.Time {
+	return &metav1.Time{Time: t.Truncate(time.Second).Local()}
+}
+
+// certificatesHash returns a hash of the certificates served by the given components, used to roll
+// driver pods when one of them is renewed
+func (r *driverReconcile) certificatesHash(components ...string) string {
+	hashes := [][]byte{}
+	for _, component := range components {
+		if hash, ok := r.certificateHashes[component]; ok {
+			hashes = append(hashes, []byte(component+"="+hash))
+		}
+	}
+	if len(hashes) == 0 {
+		return ""
+	}
+	hash := sha256.Sum256(bytes.Join(hashes, []byte(",")))
+	return hex.EncodeToString(hash[:])
+}
@@ -17,16 +17,24 @@ limitations under the License.
 package controller
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"slices"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -51,19 +59,20 @@ import (
 // ClientProfileReconciler reconciles a ClientProfile object
 type ClientProfileReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder events.EventRecorder
 }
 
 // A local reconcile object tied to a single reconcile iteration
 type ClientProfileReconcile struct {
 	ClientProfileReconciler
 
-	ctx                      context.Context
-	log                      logr.Logger
-	clientProfile            csiv1.ClientProfile
-	cephConn                 csiv1.CephConnection
-	clientProfileReplication csiv1.ClientProfileReplication
-	cleanUp                  bool
+	ctx                       context.Context
+	log                       logr.Logger
+	clientProfile             csiv1.ClientProfile
+	cephConn                  csiv1.CephConnection
+	clientProfileReplications []csiv1.ClientProfileReplication
+	cleanUp                   bool
 }
 
 // csiClusterRrcordInfo represent the structure of a serialized csi record
@@ -105,12 +114,20 @@ type csiClusterInfoRecord struct {
 		Enabled             bool     `json:"enabled,omitempty"`
 		CrushLocationLabels []string `json:"crushLocationLabels,omitempty"`
 	} `json:"readAffinity,omitempty"`
-	ReplicationDestination *replicationDestinationInfo `json:"replicationDestination,omitempty"`
+	// The destination of the oldest replication, kept for the consumers of a single destination
+	ReplicationDestination  *replicationDestinationInfo   `json:"replicationDestination,omitempty"`
+	ReplicationDestinations []*replicationDestinationInfo `json:"replicationDestinations,omitempty"`
+	InlineVolumes           *inlineVolumesInfo            `json:"inlineVolumes,omitempty"`
+}
+
+type inlineVolumesInfo struct {
+	AllowedNamespaces []string `json:"allowedNamespaces"`
 }
 
 type replicationDestinationInfo struct {
-	RemoteClusterID string            `json:"remoteClusterID"`
-	Rbd             *remoteRbdDetails `json:"rbd,omitempty"`
+	RemoteClusterID string               `json:"remoteClusterID"`
+	Rbd             *remoteRbdDetails    `json:"rbd,omitempty"`
+	CephFs          *remoteCephFsDetails `json:"cephFS,omitempty"`
 }
 
 type remoteRbdDetails struct {
@@ -121,8 +138,21 @@ type remotePoolDetails struct {
 	PoolID string `json:"poolID"`
 }
 
+type remoteCephFsDetails struct {
+	RemoteFsMapping       map[string]remoteFsDetails `json:"remoteFsMapping,omitempty"`
+	SubvolumeGroupMapping map[string]string          `json:"subvolumeGroupMapping,omitempty"`
+}
+
+type remoteFsDetails struct {
+	FsName string `json:"fsName"`
+	FsID   string `json:"fsID"`
+}
+
 const (
 	cleanupFinalizer = "csi.ceph.com/cleanup"
+
+	// Event reason reported when the deletion of a ClientProfile waits for referencing replications
+	deletionBlockedReason = "DeletionBlocked"
 )
 
 var configMapUpdateLock = sync.Mutex{}
@@ -168,7 +198,8 @@ func (r *ClientProfileReconciler) SetupWithManager(mgr ctrl.Manager) error {
 }
 
 func (r *ClientProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := ctrllog.FromContext(ctx)
+	ctx, span := startReconcileSpan(ctx, "ClientProfile", req)
+	log := utils.LoggerWithTraceID(ctx, ctrllog.FromContext(ctx))
 	log.Info("Starting reconcile iteration for ClientProfile", "req", req)
 
 	reconcileHandler := ClientProfileReconcile{}
@@ -184,15 +215,28 @@ func (r *ClientProfileReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	} else {
 		log.Info("ClientProfile reconciliation completed successfully")
 	}
+	utils.EndSpan(span, err)
 	return ctrl.Result{}, err
 }
 
 func (r *ClientProfileReconcile) reconcile() error {
-	if err := r.loadAndValidate(); err != nil {
+	// Load the ClientProfile
+	if err := r.Get(r.ctx, client.ObjectKeyFromObject(&r.clientProfile), &r.clientProfile); err != nil {
+		r.log.Error(err, "Failed loading ClientProfile")
 		return err
 	}
+	r.cleanUp = r.clientProfile.DeletionTimestamp != nil
+	r.initConditions()
 
-	reconcileErr := r.reconcilePhases()
+	reconcileErr := r.loadAndValidate()
+	if reconcileErr != nil {
+		r.clientProfile.Status.Phase = csiv1.ClientProfilePhaseFailed
+		r.clientProfile.Status.Message = fmt.Sprintf("failed to load ClientProfile dependencies: %v", reconcileErr)
+	} else {
+		reconcileErr = r.reconcilePhases()
+	}
+	r.updateReadyCondition()
+	r.clientProfile.Status.ObservedGeneration = r.clientProfile.Generation
 
 	statusErr := r.Status().Update(r.ctx, &r.clientProfile)
 	if statusErr != nil {
@@ -212,7 +256,7 @@ func (r *ClientProfileReconcile) reconcilePhases() error {
 	if !r.cleanUp {
 		// Ensure a finalizer on the ClientProfile to allow proper clean up
 		if ctrlutil.AddFinalizer(&r.clientProfile, cleanupFinalizer) {
-			if err := r.Update(r.ctx, &r.clientProfile); err != nil {
+			if err := r.updateClientProfile(); err != nil {
 				r.log.Error(err, "Failed to add a cleanup finalizer on ClientProfile")
 				return err
 			}
@@ -222,6 +266,7 @@ func (r *ClientProfileReconcile) reconcilePhases() error {
 	if err := r.reconcileCephConnection(); err != nil {
 		r.clientProfile.Status.Phase = csiv1.ClientProfilePhaseFailed
 		r.clientProfile.Status.Message = fmt.Sprintf("failed to reconcile CephConnection: %v", err)
+		r.setCephConnectionErrorCondition(err)
 		return err
 	}
 	if err := r.reconcileClientProfileReplication(); err != nil {
@@ -232,12 +277,28 @@ func (r *ClientProfileReconcile) reconcilePhases() error {
 	if err := r.reconcileCephCsiClusterInfo(); err != nil {
 		r.clientProfile.Status.Phase = csiv1.ClientProfilePhaseFailed
 		r.clientProfile.Status.Message = fmt.Sprintf("failed to reconcile ConfigMap: %v", err)
+		r.setCondition(
+			csiv1.ClientProfileConfigPublishedCondition,
+			metav1.ConditionFalse,
+			csiv1.ClientProfileConfigPublishFailedReason,
+			err.Error(),
+		)
 		return err
 	}
+	r.setCondition(
+		csiv1.ClientProfileConfigPublishedCondition,
+		utils.If(r.cleanUp, metav1.ConditionFalse, metav1.ConditionTrue),
+		utils.If(r.cleanUp, csiv1.ClientProfileDeletingReason, csiv1.ClientProfileConfigPublishedReason),
+		fmt.Sprintf(
+			"%s the Ceph CSI config map %s",
+			utils.If(r.cleanUp, "Removed from", "Published to"),
+			utils.CsiConfigVolume.Name,
+		),
+	)
 
 	if r.cleanUp {
 		ctrlutil.RemoveFinalizer(&r.clientProfile, cleanupFinalizer)
-		if err := r.Update(r.ctx, &r.clientProfile); err != nil {
+		if err := r.updateClientProfile(); err != nil {
 			r.log.Error(err, "Failed to remove cleanup finalizer on ClientProfile")
 			r.clientProfile.Status.Phase = csiv1.ClientProfilePhaseFailed
 			r.clientProfile.Status.Message = fmt.Sprintf("failed to remove finalizer: %v", err)
@@ -252,17 +313,16 @@ func (r *ClientProfileReconcile) reconcilePhases() error {
 }
 
 func (r *ClientProfileReconcile) loadAndValidate() error {
-	// Load the ClientProfile
-	if err := r.Get(r.ctx, client.ObjectKeyFromObject(&r.clientProfile), &r.clientProfile); err != nil {
-		r.log.Error(err, "Failed loading ClientProfile")
-		return err
-	}
-	r.cleanUp = r.clientProfile.DeletionTimestamp != nil
-
 	// Validate a pointer to a ceph cluster resource
 	if r.clientProfile.Spec.CephConnectionRef.Name == "" {
 		err := fmt.Errorf("validation error")
 		r.log.Error(err, "Invalid ClientProfile, missing .spec.cephConnectionRef.name")
+		r.setCondition(
+			csiv1.ClientProfileCephConnectionResolvedCondition,
+			metav1.ConditionFalse,
+			csiv1.ClientProfileCephConnectionRefMissingReason,
+			".spec.cephConnectionRef.name is not set",
+		)
 		return err
 	}
 
@@ -271,6 +331,16 @@ func (r *ClientProfileReconcile) loadAndValidate() error {
 	r.cephConn.Namespace = r.clientProfile.Namespace
 	if err := r.Get(r.ctx, client.ObjectKeyFromObject(&r.cephConn), &r.cephConn); err != nil {
 		r.log.Error(err, "Failed loading CephConnection")
+		if k8serrors.IsNotFound(err) {
+			r.setCondition(
+				csiv1.ClientProfileCephConnectionResolvedCondition,
+				metav1.ConditionFalse,
+				csiv1.ClientProfileCephConnectionNotFoundReason,
+				fmt.Sprintf("CephConnection %s not found", r.cephConn.Name),
+			)
+		} else {
+			r.setCephConnectionErrorCondition(err)
+		}
 		return err
 	}
 
@@ -287,14 +357,22 @@ func (r *ClientProfileReconcile) loadAndValidate() error {
 	if !cephConnHasOwnerRef {
 		if err := ctrlutil.SetOwnerReference(&r.clientProfile, &r.cephConn, r.Scheme); err != nil {
 			r.log.Error(err, "Failed adding an owner reference on CephConnection")
+			r.setCephConnectionErrorCondition(err)
 			return err
 		}
 		r.log.Info("Owner reference missing on CephConnection, adding")
 		if err := r.Update(r.ctx, &r.cephConn); err != nil {
 			r.log.Error(err, "Failed adding an owner reference to CephConnection")
+			r.setCephConnectionErrorCondition(err)
 			return err
 		}
 	}
+	r.setCondition(
+		csiv1.ClientProfileCephConnectionResolvedCondition,
+		metav1.ConditionTrue,
+		csiv1.ClientProfileCephConnectionFoundReason,
+		fmt.Sprintf("CephConnection %s found", r.cephConn.Name),
+	)
 
 	// Load the ClientProfileReplication
 	// Look up ClientProfileReplication CRs using the field index
@@ -304,32 +382,161 @@ func (r *ClientProfileReconcile) loadAndValidate() error {
 		client.MatchingFields{clientProfileIndexKey: r.clientProfile.Name})
 	if err != nil {
 		r.log.Error(err, "failed to list ClientProfileReplication CRs by localClientProfile")
+		r.setReplicationErrorCondition(err)
 		return err
 	}
 
-	// Filter for Ready state
-	var readyCRs []csiv1.ClientProfileReplication
+	// Filter for Ready state, the profile can be replicated to several remote profiles but only
+	// through a single CR per remote profile
+	r.clientProfileReplications = nil
+	readyCRsByRemote := map[string][]string{}
 	for _, item := range replicationList.Items {
 		if item.Status.Phase == csiv1.ClientProfileReplicationPhaseReady {
-			readyCRs = append(readyCRs, item)
+			r.clientProfileReplications = append(r.clientProfileReplications, item)
+			remote := item.Spec.RemoteClientProfile
+			readyCRsByRemote[remote] = append(readyCRsByRemote[remote], item.Name)
 		}
 	}
-	if len(readyCRs) > 1 {
-		crNames := make([]string, len(readyCRs))
-		for i, item := range readyCRs {
-			crNames[i] = item.Name
+	crNames := []string{}
+	for _, remote := range slices.Sorted(maps.Keys(readyCRsByRemote)) {
+		if len(readyCRsByRemote[remote]) > 1 {
+			crNames = append(crNames, readyCRsByRemote[remote]...)
 		}
-		err := fmt.Errorf("multiple Ready ClientProfileReplication CRs found: %v", crNames)
+	}
+	if len(crNames) > 0 {
+		err := fmt.Errorf("multiple Ready ClientProfileReplication CRs found for the same remote ClientProfile: %v", crNames)
 		r.log.Error(err, "invalid state: multiple Ready ClientProfileReplication CRs")
+		r.setCondition(
+			csiv1.ClientProfileReplicationResolvedCondition,
+			metav1.ConditionFalse,
+			csiv1.ClientProfileMultipleReadyReplicationsReason,
+			err.Error(),
+		)
 		return err
-	} else if len(readyCRs) == 1 {
-		// Store the ready CR for use in ConfigMap composition
-		r.clientProfileReplication = readyCRs[0]
+	} else if len(r.clientProfileReplications) > 0 {
+		// Keep the ready CRs, oldest first, for use in ConfigMap composition
+		sort.Slice(r.clientProfileReplications, func(i, j int) bool {
+			return replicationCreatedBefore(&r.clientProfileReplications[i], &r.clientProfileReplications[j])
+		})
+		names := make([]string, len(r.clientProfileReplications))
+		for i := range r.clientProfileReplications {
+			names[i] = r.clientProfileReplications[i].Name
+		}
+		r.setCondition(
+			csiv1.ClientProfileReplicationResolvedCondition,
+			metav1.ConditionTrue,
+			csiv1.ClientProfileReplicationFoundReason,
+			fmt.Sprintf("Replicating to the destinations of ClientProfileReplications %s", strings.Join(names, ", ")),
+		)
+	} else {
+		r.setCondition(
+			csiv1.ClientProfileReplicationResolvedCondition,
+			metav1.ConditionTrue,
+			csiv1.ClientProfileNoReplicationReason,
+			"No Ready ClientProfileReplication references the profile",
+		)
 	}
 
 	return nil
 }
 
+// updateClientProfile updates the ClientProfile while keeping the in memory status, which the update
+// would otherwise overwrite with the stored one
+func (r *ClientProfileReconcile) updateClientProfile() error {
+	status := r.clientProfile.Status.DeepCopy()
+	err := r.Update(r.ctx, &r.clientProfile)
+	r.clientProfile.Status = *status
+	return err
+}
+
+// initConditions adds the conditions that were never evaluated as Unknown, so that all the
+// conditions are reported from the first reconcile on
+func (r *ClientProfileReconcile) initConditions() {
+	for _, conditionType := range []string{
+		csiv1.ClientProfileCephConnectionResolvedCondition,
+		csiv1.ClientProfileReplicationResolvedCondition,
+		csiv1.ClientProfileConfigPublishedCondition,
+		csiv1.ClientProfileReadyCondition,
+	} {
+		if meta.FindStatusCondition(r.clientProfile.Status.Conditions, conditionType) == nil {
+			r.setCondition(conditionType, metav1.ConditionUnknown, csiv1.ClientProfileReconcilingReason, "")
+		}
+	}
+}
+
+// updateReadyCondition sets the Ready condition based on the conditions of the reconcile steps,
+// reporting the first failing step
+func (r *ClientProfileReconcile) updateReadyCondition() {
+	for _, conditionType := range []string{
+		csiv1.ClientProfileCephConnectionResolvedCondition,
+		csiv1.ClientProfileReplicationResolvedCondition,
+		csiv1.ClientProfileConfigPublishedCondition,
+	} {
+		condition := meta.FindStatusCondition(r.clientProfile.Status.Conditions, conditionType)
+		if condition.Status != metav1.ConditionTrue {
+			r.setCondition(
+				csiv1.ClientProfileReadyCondition,
+				utils.If(condition.Status == metav1.ConditionUnknown, metav1.ConditionUnknown, metav1.ConditionFalse),
+				condition.Reason,
+				condition.Message,
+			)
+			return
+		}
+	}
+	if r.cleanUp {
+		r.setCondition(
+			csiv1.ClientProfileReadyCondition,
+			metav1.ConditionFalse,
+			csiv1.ClientProfileDeletingReason,
+			"ClientProfile is being deleted",
+		)
+		return
+	}
+	r.setCondition(
+		csiv1.ClientProfileReadyCondition,
+		metav1.ConditionTrue,
+		csiv1.ClientProfileReconciledReason,
+		"ClientProfile reconciled successfully",
+	)
+}
+
+// setCephConnectionErrorCondition reports a failure to load or update the CephConnection of the profile
+func (r *ClientProfileReconcile) setCephConnectionErrorCondition(err error) {
+	r.setCondition(
+		csiv1.ClientProfileCephConnectionResolvedCondition,
+		metav1.ConditionFalse,
+		csiv1.ClientProfileCephConnectionErrorReason,
+		err.Error(),
+	)
+}
+
+// setReplicationErrorCondition reports a failure to load or update the ClientProfileReplications
+// referencing the profile
+func (r *ClientProfileReconcile) setReplicationErrorCondition(err error) {
+	r.setCondition(
+		csiv1.ClientProfileReplicationResolvedCondition,
+		metav1.ConditionFalse,
+		csiv1.ClientProfileReplicationErrorReason,
+		err.Error(),
+	)
+}
+
+// setCondition sets a condition of the ClientProfile, observed on its current generation
+func (r *ClientProfileReconcile) setCondition(
+	conditionType string,
+	status metav1.ConditionStatus,
+	reason string,
+	message string,
+) {
+	meta.SetStatusCondition(&r.clientProfile.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: r.clientProfile.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
 func (r *ClientProfileReconcile) reconcileCephConnection() error {
 	log := r.log.WithValues("cephConnectionName", r.cephConn.Name)
 	log.Info("Reconciling CephConnection")
@@ -356,18 +563,21 @@ func (r *ClientProfileReconcile) reconcileClientProfileReplication() error {
 	log := r.log
 	log.Info("Reconciling ClientProfileReplication")
 
-	if r.clientProfileReplication.UID != "" {
+	for i := range r.clientProfileReplications {
+		clientProfileReplication := &r.clientProfileReplications[i]
 		if needsUpdate, err := utils.ToggleOwnerReference(
 			!r.cleanUp,
-			&r.clientProfileReplication,
+			clientProfileReplication,
 			&r.clientProfile,
 			r.Scheme,
 		); err != nil {
-			r.log.Error(err, "Failed to toggle owner reference on CephConnection")
+			r.log.Error(err, "Failed to toggle owner reference on ClientProfileReplication", "name", clientProfileReplication.Name)
+			r.setReplicationErrorCondition(err)
 			return err
 		} else if needsUpdate {
-			if err := r.Update(r.ctx, &r.cephConn); err != nil {
-				r.log.Error(err, "Failed to update CephConnection")
+			if err := r.Update(r.ctx, clientProfileReplication); err != nil {
+				r.log.Error(err, "Failed to update ClientProfileReplication", "name", clientProfileReplication.Name)
+				r.setReplicationErrorCondition(err)
 				return err
 			}
 		}
@@ -382,6 +592,7 @@ func (r *ClientProfileReconcile) reconcileClientProfileReplication() error {
 			client.MatchingFields{clientProfileIndexKey: r.clientProfile.Name})
 		if err != nil {
 			log.Error(err, "failed to list ClientProfileReplication CRs by localClientProfile")
+			r.setReplicationErrorCondition(err)
 			return err
 		}
 		if len(replicationList.Items) > 0 {
@@ -391,6 +602,19 @@ func (r *ClientProfileReconcile) reconcileClientProfileReplication() error {
 			}
 			err := fmt.Errorf("cannot delete: ClientProfileReplication CRs still reference this profile: %v", crNames)
 			log.Error(err, "deletion blocked by referencing ClientProfileReplication CRs")
+			r.setCondition(
+				csiv1.ClientProfileReplicationResolvedCondition,
+				metav1.ConditionFalse,
+				csiv1.ClientProfileReferencedByReplicationsReason,
+				err.Error(),
+			)
+			r.recordEvent(
+				corev1.EventTypeWarning,
+				deletionBlockedReason,
+				"Delete",
+				"Deletion blocked by referencing ClientProfileReplications: %s",
+				strings.Join(crNames, ", "),
+			)
 			return err
 		}
 	}
@@ -398,6 +622,13 @@ func (r *ClientProfileReconcile) reconcileClientProfileReplication() error {
 	return nil
 }
 
+// recordEvent records an event on the client profile, if an event recorder is available
+func (r *ClientProfileReconcile) recordEvent(eventType, reason, action, note string, args ...any) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(&r.clientProfile, nil, eventType, reason, action, note, args...)
+	}
+}
+
 func (r *ClientProfileReconcile) reconcileCephCsiClusterInfo() error {
 	csiConfigMap := corev1.ConfigMap{}
 	csiConfigMap.Name = utils.CsiConfigVolume.Name
@@ -444,7 +675,7 @@ func (r *ClientProfileReconcile) reconcileCephCsiClusterInfo() error {
 
 		if !r.cleanUp {
 			// Overwrite an existing entry or append a new one
-			record := composeCsiClusterInfoRecord(&r.clientProfile, &r.cephConn, &r.clientProfileReplication)
+			record := composeCsiClusterInfoRecord(&r.clientProfile, &r.cephConn, r.clientProfileReplications)
 			if index > -1 {
 				clusterInfoList[index] = record
 			} else {
@@ -470,13 +701,17 @@ func (r *ClientProfileReconcile) reconcileCephCsiClusterInfo() error {
 			return nil
 		}
 	})
+	if err == nil {
+		recordCsiConfigSize(&csiConfigMap)
+	}
 
 	return err
 }
 
 // ComposeCsiClusterInfoRecord composes the desired csi cluster info record for
-// a given ClientProfile and CephConnection specs
-func composeCsiClusterInfoRecord(clientProfile *csiv1.ClientProfile, cephConn *csiv1.CephConnection, clientProfileReplication *csiv1.ClientProfileReplication) *csiClusterInfoRecord {
+// a given ClientProfile and CephConnection specs, and the ClientProfileReplications of the
+// ClientProfile ordered oldest first
+func composeCsiClusterInfoRecord(clientProfile *csiv1.ClientProfile, cephConn *csiv1.CephConnection, clientProfileReplications []csiv1.ClientProfileReplication) *csiClusterInfoRecord {
 	record := csiClusterInfoRecord{}
 	record.ClusterId = clientProfile.Name
 	record.Monitors = cephConn.Spec.Monitors
@@ -515,27 +750,68 @@ func composeCsiClusterInfoRecord(clientProfile *csiv1.ClientProfile, cephConn *c
 		record.ReadAffinity.Enabled = true
 		record.ReadAffinity.CrushLocationLabels = readAffinity.CrushLocationLabels
 	}
+	if inlineVolumes := clientProfile.Spec.InlineVolumes; inlineVolumes != nil {
+		record.InlineVolumes = &inlineVolumesInfo{
+			AllowedNamespaces: inlineVolumes.AllowedNamespaces,
+		}
+	}
 
-	// Add replication destination if a Ready ClientProfileReplication exists
-	if clientProfileReplication != nil && clientProfileReplication.Status.Phase == csiv1.ClientProfileReplicationPhaseReady {
-		replDest := &replicationDestinationInfo{
-			RemoteClusterID: clientProfileReplication.Spec.RemoteClientProfile,
+	// Add a replication destination per Ready ClientProfileReplication, keyed by remote cluster ID
+	for i := range clientProfileReplications {
+		if clientProfileReplications[i].Status.Phase != csiv1.ClientProfileReplicationPhaseReady {
+			continue
+		}
+		replDest := composeReplicationDestination(&clientProfileReplications[i])
+		if record.ReplicationDestination == nil {
+			record.ReplicationDestination = replDest
 		}
+		record.ReplicationDestinations = append(record.ReplicationDestinations, replDest)
+	}
+	slices.SortFunc(record.ReplicationDestinations, func(a, b *replicationDestinationInfo) int {
+		return strings.Compare(a.RemoteClusterID, b.RemoteClusterID)
+	})
+
+	return &record
+}
+
+// composeReplicationDestination composes the replication destination record of a ClientProfileReplication
+func composeReplicationDestination(clientProfileReplication *csiv1.ClientProfileReplication) *replicationDestinationInfo {
+	replDest := &replicationDestinationInfo{
+		RemoteClusterID: clientProfileReplication.Spec.RemoteClientProfile,
+	}
 
-		// Add RBD pool mapping if specified
-		if clientProfileReplication.Spec.RBD != nil && len(clientProfileReplication.Spec.RBD.PoolMapping) > 0 {
-			replDest.Rbd = &remoteRbdDetails{
-				RemotePoolMapping: make(map[string]remotePoolDetails),
+	// Add RBD pool mapping if specified
+	if clientProfileReplication.Spec.RBD != nil && len(clientProfileReplication.Spec.RBD.PoolMapping) > 0 {
+		replDest.Rbd = &remoteRbdDetails{
+			RemotePoolMapping: make(map[string]remotePoolDetails),
+		}
+		for _, poolMapping := range clientProfileReplication.Spec.RBD.PoolMapping {
+			replDest.Rbd.RemotePoolMapping[poolMapping.Name] = remotePoolDetails{
+				PoolID: poolMapping.RemoteID,
 			}
-			for _, poolMapping := range clientProfileReplication.Spec.RBD.PoolMapping {
-				replDest.Rbd.RemotePoolMapping[poolMapping.Name] = remotePoolDetails{
-					PoolID: poolMapping.RemoteID,
+		}
+	}
+
+	// Add CephFS filesystem and subvolume group mappings if specified
+	if cephFs := clientProfileReplication.Spec.CephFS; cephFs != nil &&
+		(len(cephFs.FilesystemMapping) > 0 || len(cephFs.SubvolumeGroupMapping) > 0) {
+		replDest.CephFs = &remoteCephFsDetails{}
+		if len(cephFs.FilesystemMapping) > 0 {
+			replDest.CephFs.RemoteFsMapping = make(map[string]remoteFsDetails)
+			for _, fsMapping := range cephFs.FilesystemMapping {
+				replDest.CephFs.RemoteFsMapping[fsMapping.Name] = remoteFsDetails{
+					FsName: cmp.Or(fsMapping.RemoteName, fsMapping.Name),
+					FsID:   fsMapping.RemoteID,
 				}
 			}
 		}
-
-		record.ReplicationDestination = replDest
+		if len(cephFs.SubvolumeGroupMapping) > 0 {
+			replDest.CephFs.SubvolumeGroupMapping = make(map[string]string)
+			for _, groupMapping := range cephFs.SubvolumeGroupMapping {
+				replDest.CephFs.SubvolumeGroupMapping[groupMapping.Name] = groupMapping.RemoteName
+			}
+		}
 	}
 
-	return &record
+	return replDest
 }
@@ -18,19 +18,24 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	csiv1 "github.com/ceph/ceph-csi-operator/api/v1"
+	"github.com/ceph/ceph-csi-operator/internal/utils"
 )
 
 var _ = Describe("ClientProfile Controller with Fake Client", func() {
@@ -108,6 +113,17 @@ var _ = Describe("ClientProfile Controller with Fake Client", func() {
 			updated := &csiv1.ClientProfile{}
 			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(testClientProfile), updated)).To(Succeed())
 			Expect(updated.Status.Phase).To(Equal(csiv1.ClientProfilePhaseReady))
+			Expect(updated.Status.ObservedGeneration).To(Equal(updated.Generation))
+			for _, conditionType := range []string{
+				csiv1.ClientProfileCephConnectionResolvedCondition,
+				csiv1.ClientProfileReplicationResolvedCondition,
+				csiv1.ClientProfileConfigPublishedCondition,
+				csiv1.ClientProfileReadyCondition,
+			} {
+				Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, conditionType)).To(BeTrue(), conditionType)
+			}
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, csiv1.ClientProfileReplicationResolvedCondition)).
+				To(HaveField("Reason", csiv1.ClientProfileNoReplicationReason))
 		})
 	})
 
@@ -147,10 +163,67 @@ var _ = Describe("ClientProfile Controller with Fake Client", func() {
 			updated := &csiv1.ClientProfile{}
 			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(testClientProfile), updated)).To(Succeed())
 			Expect(updated.Status.Phase).To(Equal(csiv1.ClientProfilePhaseReady))
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, csiv1.ClientProfileReplicationResolvedCondition)).
+				To(HaveField("Reason", csiv1.ClientProfileReplicationFoundReason))
+			Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, csiv1.ClientProfileReadyCondition)).To(BeTrue())
 		})
 	})
 
-	Context("When multiple Ready ClientProfileReplication CRs exist", func() {
+	Context("When Ready ClientProfileReplication CRs exist for several remote profiles", func() {
+		It("should publish a replication destination per remote profile", func() {
+			for _, cpr := range []*csiv1.ClientProfileReplication{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "test-replication-async",
+						Namespace:         "default",
+						CreationTimestamp: metav1.Now(),
+					},
+					Spec: csiv1.ClientProfileReplicationSpec{
+						LocalClientProfile:  testClientProfile.Name,
+						RemoteClientProfile: "async-profile",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "test-replication-metro",
+						Namespace:         "default",
+						CreationTimestamp: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+					},
+					Spec: csiv1.ClientProfileReplicationSpec{
+						LocalClientProfile:  testClientProfile.Name,
+						RemoteClientProfile: "metro-profile",
+					},
+				},
+			} {
+				cpr.Status.Phase = csiv1.ClientProfileReplicationPhaseReady
+				Expect(fakeClient.Create(ctx, cpr)).To(Succeed())
+			}
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testClientProfile.Name,
+					Namespace: testClientProfile.Namespace,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			configMap := &corev1.ConfigMap{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{
+				Name:      utils.CsiConfigVolume.Name,
+				Namespace: testClientProfile.Namespace,
+			}, configMap)).To(Succeed())
+			records := []*csiClusterInfoRecord{}
+			Expect(json.Unmarshal([]byte(configMap.Data[utils.CsiConfigMapConfigKey]), &records)).To(Succeed())
+			Expect(records).To(HaveLen(1))
+			Expect(records[0].ReplicationDestinations).To(HaveExactElements(
+				HaveField("RemoteClusterID", "async-profile"),
+				HaveField("RemoteClusterID", "metro-profile"),
+			))
+			Expect(records[0].ReplicationDestination).To(HaveField("RemoteClusterID", "metro-profile"))
+		})
+	})
+
+	Context("When multiple Ready ClientProfileReplication CRs exist for the same remote profile", func() {
 		It("should fail reconciliation", func() {
 			// Create two Ready ClientProfileReplication CRs
 			cpr1 := &csiv1.ClientProfileReplication{
@@ -176,7 +249,7 @@ var _ = Describe("ClientProfile Controller with Fake Client", func() {
 				},
 				Spec: csiv1.ClientProfileReplicationSpec{
 					LocalClientProfile:  testClientProfile.Name,
-					RemoteClientProfile: "remote-profile-2",
+					RemoteClientProfile: "remote-profile-1",
 				},
 				Status: csiv1.ClientProfileReplicationStatus{
 					Phase:   csiv1.ClientProfileReplicationPhaseReady,
@@ -193,6 +266,13 @@ var _ = Describe("ClientProfile Controller with Fake Client", func() {
 			})
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("multiple Ready ClientProfileReplication CRs found"))
+
+			updated := &csiv1.ClientProfile{}
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(testClientProfile), updated)).To(Succeed())
+			Expect(updated.Status.Phase).To(Equal(csiv1.ClientProfilePhaseFailed))
+			readyCondition := meta.FindStatusCondition(updated.Status.Conditions, csiv1.ClientProfileReadyCondition)
+			Expect(readyCondition).To(HaveField("Status", metav1.ConditionFalse))
+			Expect(readyCondition).To(HaveField("Reason", csiv1.ClientProfileMultipleReadyReplicationsReason))
 		})
 	})
 
@@ -239,9 +319,11 @@ var _ = Describe("ClientProfile Controller with Fake Client", func() {
 				}).
 				Build()
 
+			recorder := events.NewFakeRecorder(10)
 			testReconciler := &ClientProfileReconciler{
-				Client: testFakeClient,
-				Scheme: testScheme,
+				Client:   testFakeClient,
+				Scheme:   testScheme,
+				Recorder: recorder,
 			}
 
 			_, err := testReconciler.Reconcile(ctx, reconcile.Request{
@@ -252,6 +334,60 @@ var _ = Describe("ClientProfile Controller with Fake Client", func() {
 			})
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("ClientProfileReplication CRs still reference this profile"))
+			Expect(recorder.Events).To(Receive(And(
+				HavePrefix(corev1.EventTypeWarning+" "+deletionBlockedReason),
+				ContainSubstring(cpr.Name),
+			)))
+		})
+	})
+
+	Context("composeCsiClusterInfoRecord", func() {
+		It("should not publish a replication destination without replications", func() {
+			record := composeCsiClusterInfoRecord(testClientProfile, testCephConnection, nil)
+			Expect(record.ReplicationDestination).To(BeNil())
+			Expect(record.ReplicationDestinations).To(BeEmpty())
+		})
+
+		It("should publish the inline volumes namespace allowlist", func() {
+			record := composeCsiClusterInfoRecord(testClientProfile, testCephConnection, nil)
+			Expect(record.InlineVolumes).To(BeNil())
+
+			profile := testClientProfile.DeepCopy()
+			profile.Spec.InlineVolumes = &csiv1.InlineVolumesSpec{AllowedNamespaces: []string{"scratch", "ci"}}
+			record = composeCsiClusterInfoRecord(profile, testCephConnection, nil)
+			Expect(record.InlineVolumes).To(Equal(&inlineVolumesInfo{AllowedNamespaces: []string{"scratch", "ci"}}))
+		})
+
+		It("should publish the CephFS replication destination", func() {
+			cpr := &csiv1.ClientProfileReplication{
+				Spec: csiv1.ClientProfileReplicationSpec{
+					LocalClientProfile:  testClientProfile.Name,
+					RemoteClientProfile: "remote-profile",
+					CephFS: &csiv1.CephFSReplicationSpec{
+						FilesystemMapping: []csiv1.FilesystemMappingSpec{
+							{Name: "myfs", RemoteID: "1"},
+							{Name: "otherfs", RemoteName: "remotefs", RemoteID: "3"},
+						},
+						SubvolumeGroupMapping: []csiv1.SubvolumeGroupMappingSpec{
+							{Name: "csi", RemoteName: "csi-remote"},
+						},
+					},
+				},
+				Status: csiv1.ClientProfileReplicationStatus{
+					Phase: csiv1.ClientProfileReplicationPhaseReady,
+				},
+			}
+			record := composeCsiClusterInfoRecord(testClientProfile, testCephConnection, []csiv1.ClientProfileReplication{*cpr})
+			Expect(record.ReplicationDestination).To(Equal(&replicationDestinationInfo{
+				RemoteClusterID: "remote-profile",
+				CephFs: &remoteCephFsDetails{
+					RemoteFsMapping: map[string]remoteFsDetails{
+						"myfs":    {FsName: "myfs", FsID: "1"},
+						"otherfs": {FsName: "remotefs", FsID: "3"},
+					},
+					SubvolumeGroupMapping: map[string]string{"csi": "csi-remote"},
+				},
+			}))
 		})
 	})
 })
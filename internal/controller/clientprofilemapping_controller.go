@@ -19,16 +19,30 @@ package controller
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	csiv1 "github.com/ceph/ceph-csi-operator/api/v1"
 	"github.com/ceph/ceph-csi-operator/internal/utils"
@@ -37,14 +51,23 @@ import (
 //+kubebuilder:rbac:groups=csi.ceph.io,resources=clientprofilemappings,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=csi.ceph.io,resources=clientprofilemappings/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=csi.ceph.io,resources=clientprofilemappings/finalizers,verbs=update
+//+kubebuilder:rbac:groups=csi.ceph.io,resources=clientprofiles,verbs=get;list;watch
+//+kubebuilder:rbac:groups=csi.ceph.io,resources=clientprofilereplications,verbs=get;list;watch;create;update;delete
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;delete
 
 // ClientProfileMappingReconciler reconciles a ClientProfileMapping object
 type ClientProfileMappingReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder events.EventRecorder
 }
 
+const (
+	// Event reasons reported when the mappings of a namespace are published to the Ceph CSI config
+	mappingPublishedReason     = "MappingPublished"
+	mappingPublishFailedReason = "MappingPublishFailed"
+)
+
 // A local reconcile object tied to a single reconcile iteration
 type ClientProfileMappingReconcile struct {
 	ClientProfileMappingReconciler
@@ -53,6 +76,12 @@ type ClientProfileMappingReconcile struct {
 	log                      logr.Logger
 	req                      ctrl.Request
 	clientProfileMappingList csiv1.ClientProfileMappingList
+	// The mappings that passed validation, in the order they are published
+	validMappings []*csiv1.MappingsSpec
+	// The mappings that failed validation, by ClientProfileMapping UID
+	mappingErrors map[types.UID][]csiv1.MappingErrorStatus
+	// The outcome of the migration of the ClientProfileMappings that opted in, by UID
+	migrationResults map[types.UID]*migrationResult
 }
 
 // csiClusterMappingRecord represents the structure to serialize a csi mapping
@@ -66,26 +95,65 @@ type csiClusterMappingRecord struct {
 func (r *ClientProfileMappingReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	genChangedPredicate := predicate.GenerationChangedPredicate{}
 
+	// The mappings of a namespace are all published to the same config map, so reconciliation is keyed
+	// by namespace: every event maps to a single request for the namespace config map, and the events
+	// of a namespace queued while a reconcile is in progress collapse into a single reconcile
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&csiv1.ClientProfileMapping{}).
-		// TODO: This watch is probalematic, it will trigger a reconcile for each
-		// mapping resource that exists in the configmap namespace whenever the configmap
-		// is changed. This is unnececary as this reconile build its desired state based
-		// on all mapping resources within a single reconcile
-		Owns(
+		Named("clientprofilemapping").
+		Watches(
+			&csiv1.ClientProfileMapping{},
+			handler.EnqueueRequestsFromMapFunc(clusterMappingRequest),
+			builder.WithPredicates(genChangedPredicate),
+		).
+		// Only changes to the published mappings and to the published replication destinations the migration
+		// is verified against are of interest, the config map is also updated by the Driver controller
+		Watches(
 			&corev1.ConfigMap{},
-			builder.MatchEveryOwner,
+			handler.EnqueueRequestsFromMapFunc(clusterMappingRequest),
 			builder.WithPredicates(
 				utils.NamePredicate(utils.CsiConfigVolume.Name),
-				genChangedPredicate,
+				predicate.Funcs{
+					UpdateFunc: func(e event.UpdateEvent) bool {
+						oldData := e.ObjectOld.(*corev1.ConfigMap).Data
+						newData := e.ObjectNew.(*corev1.ConfigMap).Data
+						return oldData[utils.CsiConfigMapMappingKey] != newData[utils.CsiConfigMapMappingKey] ||
+							oldData[utils.CsiConfigMapConfigKey] != newData[utils.CsiConfigMapConfigKey]
+					},
+				},
+			),
+		).
+		// The migration to ClientProfileReplications depends on the ClientProfileReplications of the namespace
+		Watches(
+			&csiv1.ClientProfileReplication{},
+			handler.EnqueueRequestsFromMapFunc(clusterMappingRequest),
+			builder.WithPredicates(genChangedPredicate),
+		).
+		// Mappings are validated against the ClientProfiles of the namespace, revalidate them when a
+		// profile is created or deleted
+		Watches(
+			&csiv1.ClientProfile{},
+			handler.EnqueueRequestsFromMapFunc(clusterMappingRequest),
+			builder.WithPredicates(
+				utils.EventTypePredicate(true, false, true, false),
 			),
 		).
 		Complete(r)
 }
 
+// clusterMappingRequest maps an object to the reconcile request of the cluster mappings of its namespace
+func clusterMappingRequest(_ context.Context, obj client.Object) []reconcile.Request {
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Name:      utils.CsiConfigVolume.Name,
+			Namespace: obj.GetNamespace(),
+		},
+	}}
+}
+
 func (r *ClientProfileMappingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := ctrllog.FromContext(ctx)
-	log.Info("Starting reconcile iteration for ClientProfileMapping", "req", req)
+	ctx, span := startReconcileSpan(ctx, "ClientProfileMapping", req)
+	log := utils.LoggerWithTraceID(ctx, ctrllog.FromContext(ctx))
+	log.Info("Starting reconcile iteration for ClientProfileMappings", "namespace", req.Namespace)
 
 	reconcileHandler := ClientProfileMappingReconcile{}
 	reconcileHandler.ClientProfileMappingReconciler = *r
@@ -99,26 +167,186 @@ func (r *ClientProfileMappingReconciler) Reconcile(ctx context.Context, req ctrl
 	} else {
 		log.Info("ClientProfileMapping reconciliation completed successfully")
 	}
+	utils.EndSpan(span, err)
 	return ctrl.Result{}, err
 }
 
 func (r *ClientProfileMappingReconcile) reconcile() error {
 	// This controller behave a differently then other controller. Because of the lack of uniqueness of the mapping
 	// information between 2 or more mapping resources, there is no way to update the csi mapping information without
-	// building it from the ground up on every reconcile. To do that we ignore the specific of the subject resource
-	// and instead load all mapping resources within the namespace.
+	// building it from the ground up on every reconcile. To do that the requests are keyed by namespace and all
+	// mapping resources within the namespace are loaded.
 	if err := r.List(r.ctx, &r.clientProfileMappingList, client.InNamespace(r.req.Namespace)); err != nil {
 		r.log.Error(err, "Failed listing ClientProfileMapping CRs in namespace", "namespace", r.req.Namespace)
 		return err
 	}
 
-	if err := r.reconcileCephCsiBlockPoolMapping(); err != nil {
+	if err := r.validateMappings(); err != nil {
 		return err
 	}
 
+	publishErr := r.reconcileCephCsiBlockPoolMapping()
+	migrationErr := r.reconcileMigration()
+	statusErr := r.reconcileStatus(publishErr, migrationErr)
+	return errors.Join(publishErr, migrationErr, statusErr)
+}
+
+// validateMappings selects the mappings that are published to the Ceph CSI config, and records the
+// reason the other mappings are excluded. Mappings are
+// validated from the oldest ClientProfileMapping to the newest, so that a mapping conflicting with an
+// older one is the one excluded.
+func (r *ClientProfileMappingReconcile) validateMappings() error {
+	clientProfileList := csiv1.ClientProfileList{}
+	if err := r.List(r.ctx, &clientProfileList, client.InNamespace(r.req.Namespace)); err != nil {
+		r.log.Error(err, "Failed listing ClientProfile CRs in namespace", "namespace", r.req.Namespace)
+		return err
+	}
+	clientProfiles := map[string]bool{}
+	for i := range clientProfileList.Items {
+		clientProfiles[clientProfileList.Items[i].Name] = true
+	}
+
+	// Sort by creation timestamp (oldest first)
+	sort.Slice(r.clientProfileMappingList.Items, func(i, j int) bool {
+		mappingI := r.clientProfileMappingList.Items[i]
+		mappingJ := r.clientProfileMappingList.Items[j]
+
+		if !mappingI.CreationTimestamp.Equal(&mappingJ.CreationTimestamp) {
+			return mappingI.CreationTimestamp.Before(&mappingJ.CreationTimestamp)
+		}
+		return mappingI.Name < mappingJ.Name
+	})
+
+	// The block pool ID pairs of the valid mappings, in both directions, by local+remote profile pair
+	type profilePairKey [2]string
+	localToRemote := map[profilePairKey]map[string]string{}
+	remoteToLocal := map[profilePairKey]map[string]string{}
+
+	r.validMappings = []*csiv1.MappingsSpec{}
+	r.mappingErrors = map[types.UID][]csiv1.MappingErrorStatus{}
+	for i := range r.clientProfileMappingList.Items {
+		item := &r.clientProfileMappingList.Items[i]
+
+		for j := range item.Spec.Mappings {
+			mapping := &item.Spec.Mappings[j]
+			mappingError := csiv1.MappingErrorStatus{
+				Index:               j,
+				LocalClientProfile:  mapping.LocalClientProfile,
+				RemoteClientProfile: mapping.RemoteClientProfile,
+			}
+
+			if !clientProfiles[mapping.LocalClientProfile] {
+				mappingError.Reason = csiv1.ClientProfileMappingLocalClientProfileNotFoundReason
+				mappingError.Message = fmt.Sprintf("ClientProfile %s not found", mapping.LocalClientProfile)
+				r.mappingErrors[item.UID] = append(r.mappingErrors[item.UID], mappingError)
+				continue
+			}
+			if !clientProfiles[mapping.RemoteClientProfile] {
+				mappingError.Reason = csiv1.ClientProfileMappingRemoteClientProfileNotFoundReason
+				mappingError.Message = fmt.Sprintf("ClientProfile %s not found", mapping.RemoteClientProfile)
+				r.mappingErrors[item.UID] = append(r.mappingErrors[item.UID], mappingError)
+				continue
+			}
+
+			key := profilePairKey{mapping.LocalClientProfile, mapping.RemoteClientProfile}
+
+			// A local pool maps to a single remote pool and the other way around, check the pairs of the
+			// mapping against the pairs of the valid mappings and against each other
+			mappingLocalToRemote := map[string]string{}
+			mappingRemoteToLocal := map[string]string{}
+			maps.Copy(mappingLocalToRemote, localToRemote[key])
+			maps.Copy(mappingRemoteToLocal, remoteToLocal[key])
+			for _, pair := range mapping.BlockPoolIdMapping {
+				localID, remoteID := pair[0], pair[1]
+				if mapped, ok := mappingLocalToRemote[localID]; ok && mapped != remoteID {
+					mappingError.Message = fmt.Sprintf(
+						"local block pool ID %s is mapped to remote block pool IDs %s and %s",
+						localID,
+						mapped,
+						remoteID,
+					)
+					break
+				}
+				if mapped, ok := mappingRemoteToLocal[remoteID]; ok && mapped != localID {
+					mappingError.Message = fmt.Sprintf(
+						"remote block pool ID %s is mapped from local block pool IDs %s and %s",
+						remoteID,
+						mapped,
+						localID,
+					)
+					break
+				}
+				mappingLocalToRemote[localID] = remoteID
+				mappingRemoteToLocal[remoteID] = localID
+			}
+			if mappingError.Message != "" {
+				mappingError.Reason = csiv1.ClientProfileMappingConflictingPoolIdReason
+				r.mappingErrors[item.UID] = append(r.mappingErrors[item.UID], mappingError)
+				continue
+			}
+
+			localToRemote[key] = mappingLocalToRemote
+			remoteToLocal[key] = mappingRemoteToLocal
+			r.validMappings = append(r.validMappings, mapping)
+		}
+
+		if len(r.mappingErrors[item.UID]) > 0 {
+			r.log.Info(
+				"Excluding invalid mappings from the Ceph CSI config",
+				"clientProfileMapping", item.Name,
+				"mappingErrors", r.mappingErrors[item.UID],
+			)
+		}
+	}
+
 	return nil
 }
 
+// reconcileStatus updates the status of the ClientProfileMappings of the namespace, as the validity of a
+// mapping depends on the other mappings of the namespace
+func (r *ClientProfileMappingReconcile) reconcileStatus(publishErr error, migrationErr error) error {
+	errs := []error{}
+	for i := range r.clientProfileMappingList.Items {
+		item := &r.clientProfileMappingList.Items[i]
+		originalStatus := item.Status.DeepCopy()
+		item.Status.ObservedGeneration = item.Generation
+		item.Status.MappingErrors = r.mappingErrors[item.UID]
+
+		condition := metav1.Condition{
+			Type:               csiv1.ClientProfileMappingReadyCondition,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: item.Generation,
+			Reason:             csiv1.ClientProfileMappingPublishedReason,
+			Message:            fmt.Sprintf("Published to the Ceph CSI config map %s", utils.CsiConfigVolume.Name),
+		}
+		if publishErr != nil {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = csiv1.ClientProfileMappingPublishFailedReason
+			condition.Message = publishErr.Error()
+		} else if len(item.Status.MappingErrors) > 0 {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = csiv1.ClientProfileMappingInvalidMappingsReason
+			condition.Message = fmt.Sprintf(
+				"%d of %d mappings are invalid and not published",
+				len(item.Status.MappingErrors),
+				len(item.Spec.Mappings),
+			)
+		}
+		meta.SetStatusCondition(&item.Status.Conditions, condition)
+		r.updateMigrationStatus(item, migrationErr)
+
+		if equality.Semantic.DeepEqual(originalStatus, &item.Status) {
+			continue
+		}
+		// Mappings deleted since they were listed have no status to update
+		if err := r.Status().Update(r.ctx, item); client.IgnoreNotFound(err) != nil {
+			r.log.Error(err, "Failed to update ClientProfileMapping status", "clientProfileMapping", item.Name)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (r *ClientProfileMappingReconcile) reconcileCephCsiBlockPoolMapping() error {
 	csiConfigMap := corev1.ConfigMap{}
 	csiConfigMap.Name = utils.CsiConfigVolume.Name
@@ -127,82 +355,181 @@ func (r *ClientProfileMappingReconcile) reconcileCephCsiBlockPoolMapping() error
 	log := r.log.WithValues("csiConfigMapName", csiConfigMap.Name)
 	log.Info("Reconciling Ceph CSI Cluster mapping")
 
-	_, err := ctrlutil.CreateOrUpdate(r.ctx, r.Client, &csiConfigMap, func() error {
-		var owner *csiv1.ClientProfileMapping
-		for i := range r.clientProfileMappingList.Items {
-			item := &r.clientProfileMappingList.Items[i]
-			if item.Name == r.req.Name && item.Namespace == r.req.Namespace {
-				owner = item
-				break
+	// Without mappings there is nothing to publish, only clean up what was published by mappings that
+	// were since deleted
+	mappings := r.clientProfileMappingList.Items
+	if len(mappings) == 0 {
+		if err := r.Get(r.ctx, client.ObjectKeyFromObject(&csiConfigMap), &csiConfigMap); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil
 			}
-
+			log.Error(err, "Failed loading Ceph CSI config map")
+			return err
 		}
-		if owner != nil {
-			if _, err := utils.ToggleOwnerReference(true, &csiConfigMap, owner, r.Scheme); err != nil {
+	}
+
+	csiClusterMappings, err := composeCsiClusterMappings(r.validMappings)
+	if err != nil {
+		log.Error(err, "Failed to serialize cluster mappings list")
+		return err
+	}
+
+	opResult, err := ctrlutil.CreateOrUpdate(r.ctx, r.Client, &csiConfigMap, func() error {
+		// The mappings of the namespace are the owners of the published cluster mappings, drop the references
+		// to mappings that no longer exist
+		mappingUIDs := map[types.UID]bool{}
+		for i := range mappings {
+			mappingUIDs[mappings[i].UID] = true
+			if _, err := utils.ToggleOwnerReference(true, &csiConfigMap, &mappings[i], r.Scheme); err != nil {
 				log.Error(err, "Failed toggling owner reference for Ceph CSI config map")
 				return err
 			}
 		}
+		csiConfigMap.OwnerReferences = slices.DeleteFunc(
+			csiConfigMap.OwnerReferences,
+			func(ref metav1.OwnerReference) bool {
+				return ref.APIVersion == csiv1.GroupVersion.String() &&
+					ref.Kind == "ClientProfileMapping" &&
+					!mappingUIDs[ref.UID]
+			},
+		)
+
+		if len(mappings) == 0 {
+			delete(csiConfigMap.Data, utils.CsiConfigMapMappingKey)
+			return nil
+		}
+		if csiConfigMap.Data == nil {
+			csiConfigMap.Data = map[string]string{}
+		}
+		csiConfigMap.Data[utils.CsiConfigMapMappingKey] = csiClusterMappings
+		return nil
+	})
+	if err == nil {
+		recordCsiConfigSize(&csiConfigMap)
+		if opResult == ctrlutil.OperationResultNone {
+			log.Info("Ceph CSI cluster mappings are up to date")
+		}
+	}
 
-		type mappingKey [2]string
-		type duplicationKey [4]string
-		indexByPair := map[mappingKey]int{}
-		csiClusterMappingsList := []csiClusterMappingRecord{}
-		alreadySeen := map[duplicationKey]bool{}
-
-		// Scan every loaded profile mapping CR, for each scan all mappings records.
-		for i := range r.clientProfileMappingList.Items {
-			spec := &r.clientProfileMappingList.Items[i].Spec
-			for j := range spec.Mappings {
-				mapping := &spec.Mappings[j]
-
-				// Create a local+remote key
-				key := mappingKey{mapping.LocalClientProfile, mapping.RemoteClientProfile}
-
-				// Check if we already encountered the local+remote pair. If we didn't,
-				// append a new record at the end, to the csi mapping config
-				index, ok := indexByPair[key]
-				if !ok {
-					index = len(csiClusterMappingsList)
-					indexByPair[key] = index
-					csiClusterMappingsList = append(
-						csiClusterMappingsList,
-						csiClusterMappingRecord{
-							ClusterIdMapping: map[string]string{
-								mapping.LocalClientProfile: mapping.RemoteClientProfile,
-							},
-							RbdPoolIdMapping: []map[string]string{},
-						},
-					)
-				}
-
-				// Transform and copy mapping information from ClientProfileMapping types
-				// into the csi mapping types
-				rbdPoolIdMapping := csiClusterMappingsList[index].RbdPoolIdMapping
-				for _, pair := range mapping.BlockPoolIdMapping {
-					dupKey := duplicationKey{key[0], key[1], pair[0], pair[1]}
-
-					// Skip adding identical items
-					if !alreadySeen[dupKey] {
-						rbdPoolIdMapping = append(rbdPoolIdMapping, map[string]string{pair[0]: pair[1]})
-						alreadySeen[dupKey] = true
-					}
-				}
-				csiClusterMappingsList[index].RbdPoolIdMapping = rbdPoolIdMapping
+	// Report the outcome on the mappings of the namespace, the config map is only written when the
+	// published mappings change
+	if r.Recorder != nil && (err != nil || opResult != ctrlutil.OperationResultNone) {
+		for i := range mappings {
+			if err != nil {
+				r.Recorder.Eventf(
+					&mappings[i],
+					nil,
+					corev1.EventTypeWarning,
+					mappingPublishFailedReason,
+					"Publish",
+					"Failed to publish the cluster mappings to config map %s: %v",
+					csiConfigMap.Name,
+					err,
+				)
+			} else {
+				r.Recorder.Eventf(
+					&mappings[i],
+					nil,
+					corev1.EventTypeNormal,
+					mappingPublishedReason,
+					"Publish",
+					"Published the cluster mappings to config map %s",
+					csiConfigMap.Name,
+				)
 			}
 		}
+	}
 
-		if bytes, err := json.Marshal(csiClusterMappingsList); err != nil {
-			log.Error(err, "Failed to serialize cluster mappings list")
-			return err
-		} else {
-			if csiConfigMap.Data == nil {
-				csiConfigMap.Data = map[string]string{}
+	return err
+}
+
+// updateMigrationStatus reports the progress of the migration to ClientProfileReplications of a
+// ClientProfileMapping that opted in to the migration
+func (r *ClientProfileMappingReconcile) updateMigrationStatus(item *csiv1.ClientProfileMapping, migrationErr error) {
+	if item.Spec.Migration == nil {
+		item.Status.Migration = nil
+		meta.RemoveStatusCondition(&item.Status.Conditions, csiv1.ClientProfileMappingSupersededCondition)
+		return
+	}
+
+	result := r.migrationResults[item.UID]
+	if migrationErr != nil || result == nil {
+		// Keep the last reported progress until the migration can be evaluated again
+		return
+	}
+
+	item.Status.Migration = result.status.DeepCopy()
+	condition := metav1.Condition{
+		Type:               csiv1.ClientProfileMappingSupersededCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: item.Generation,
+		Reason:             csiv1.ClientProfileMappingMigratedReason,
+		Message:            "All the mappings are replicated by ClientProfileReplications",
+	}
+	if len(result.status.UnsupportedMappings) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = csiv1.ClientProfileMappingMigrationIncompleteReason
+		condition.Message = fmt.Sprintf(
+			"%d of %d mappings cannot be expressed as ClientProfileReplications",
+			len(result.status.UnsupportedMappings),
+			len(item.Spec.Mappings),
+		)
+	} else if !result.verified {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = csiv1.ClientProfileMappingVerificationPendingReason
+		condition.Message = fmt.Sprintf(
+			"The replication destinations published to the Ceph CSI config map %s do not match the mappings yet",
+			utils.CsiConfigVolume.Name,
+		)
+	}
+	meta.SetStatusCondition(&item.Status.Conditions, condition)
+}
+
+// composeCsiClusterMappings serializes the mappings into Ceph CSI's cluster mapping config, merging the
+// mappings of the same local and remote profiles into a single record
+func composeCsiClusterMappings(mappings []*csiv1.MappingsSpec) (string, error) {
+	type mappingKey [2]string
+	type duplicationKey [4]string
+	indexByPair := map[mappingKey]int{}
+	csiClusterMappingsList := []csiClusterMappingRecord{}
+	alreadySeen := map[duplicationKey]bool{}
+
+	for _, mapping := range mappings {
+		// Create a local+remote key
+		key := mappingKey{mapping.LocalClientProfile, mapping.RemoteClientProfile}
+
+		// Check if we already encountered the local+remote pair. If we didn't,
+		// append a new record at the end, to the csi mapping config
+		index, ok := indexByPair[key]
+		if !ok {
+			index = len(csiClusterMappingsList)
+			indexByPair[key] = index
+			csiClusterMappingsList = append(
+				csiClusterMappingsList,
+				csiClusterMappingRecord{
+					ClusterIdMapping: map[string]string{
+						mapping.LocalClientProfile: mapping.RemoteClientProfile,
+					},
+					RbdPoolIdMapping: []map[string]string{},
+				},
+			)
+		}
+
+		// Transform and copy mapping information from ClientProfileMapping types
+		// into the csi mapping types
+		rbdPoolIdMapping := csiClusterMappingsList[index].RbdPoolIdMapping
+		for _, pair := range mapping.BlockPoolIdMapping {
+			dupKey := duplicationKey{key[0], key[1], pair[0], pair[1]}
+
+			// Skip adding identical items
+			if !alreadySeen[dupKey] {
+				rbdPoolIdMapping = append(rbdPoolIdMapping, map[string]string{pair[0]: pair[1]})
+				alreadySeen[dupKey] = true
 			}
-			csiConfigMap.Data[utils.CsiConfigMapMappingKey] = string(bytes)
-			return nil
 		}
-	})
+		csiClusterMappingsList[index].RbdPoolIdMapping = rbdPoolIdMapping
+	}
 
-	return err
+	bytes, err := json.Marshal(csiClusterMappingsList)
+	return string(bytes), err
 }
@@ -18,16 +18,26 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	csiv1 "github.com/ceph/ceph-csi-operator/api/v1"
+	"github.com/ceph/ceph-csi-operator/internal/utils"
 )
 
 var _ = Describe("ClientProfileMapping Controller", func() {
@@ -89,3 +99,480 @@ var _ = Describe("ClientProfileMapping Controller", func() {
 		})
 	})
 })
+
+var _ = Describe("ClientProfileMapping Controller with Fake Client", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *ClientProfileMappingReconciler
+	)
+
+	newClientProfile := func(name string) *csiv1.ClientProfile {
+		return &csiv1.ClientProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}
+	}
+
+	newMapping := func(name string, age time.Duration, mappings ...csiv1.MappingsSpec) *csiv1.ClientProfileMapping {
+		return &csiv1.ClientProfileMapping{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				UID:               types.UID(name),
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			},
+			Spec: csiv1.ClientProfileMappingSpec{
+				Mappings: mappings,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		testScheme := runtime.NewScheme()
+		Expect(csiv1.AddToScheme(testScheme)).To(Succeed())
+		Expect(scheme.AddToScheme(testScheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(testScheme).
+			WithObjects(
+				newClientProfile("local"),
+				newClientProfile("remote"),
+				newMapping("older", time.Hour, csiv1.MappingsSpec{
+					LocalClientProfile:  "local",
+					RemoteClientProfile: "remote",
+					BlockPoolIdMapping:  []csiv1.BlockPoolIdPair{{"1", "2"}, {"3", "4"}},
+				}),
+				newMapping("newer", time.Minute,
+					csiv1.MappingsSpec{
+						LocalClientProfile:  "local",
+						RemoteClientProfile: "remote",
+						BlockPoolIdMapping:  []csiv1.BlockPoolIdPair{{"5", "6"}, {"1", "2"}},
+					},
+					csiv1.MappingsSpec{
+						LocalClientProfile:  "local",
+						RemoteClientProfile: "remote",
+						BlockPoolIdMapping:  []csiv1.BlockPoolIdPair{{"1", "7"}},
+					},
+					csiv1.MappingsSpec{
+						LocalClientProfile:  "local",
+						RemoteClientProfile: "remote",
+						BlockPoolIdMapping:  []csiv1.BlockPoolIdPair{{"8", "9"}, {"10", "9"}},
+					},
+					csiv1.MappingsSpec{
+						LocalClientProfile:  "local",
+						RemoteClientProfile: "missing",
+					},
+				),
+			).
+			WithStatusSubresource(&csiv1.ClientProfileMapping{}).
+			Build()
+
+		reconciler = &ClientProfileMappingReconciler{
+			Client: fakeClient,
+			Scheme: testScheme,
+		}
+	})
+
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: utils.CsiConfigVolume.Name, Namespace: "default"},
+	}
+
+	getConfigMap := func() *corev1.ConfigMap {
+		configMap := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, request.NamespacedName, configMap)).To(Succeed())
+		return configMap
+	}
+
+	It("should publish the valid mappings and report the invalid ones", func() {
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		configMap := getConfigMap()
+		records := []csiClusterMappingRecord{}
+		Expect(json.Unmarshal([]byte(configMap.Data[utils.CsiConfigMapMappingKey]), &records)).To(Succeed())
+		Expect(records).To(Equal([]csiClusterMappingRecord{{
+			ClusterIdMapping: map[string]string{"local": "remote"},
+			RbdPoolIdMapping: []map[string]string{{"1": "2"}, {"3": "4"}, {"5": "6"}},
+		}}))
+
+		older := &csiv1.ClientProfileMapping{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "older", Namespace: "default"}, older)).To(Succeed())
+		Expect(older.Status.MappingErrors).To(BeEmpty())
+		Expect(meta.IsStatusConditionTrue(older.Status.Conditions, csiv1.ClientProfileMappingReadyCondition)).To(BeTrue())
+
+		newer := &csiv1.ClientProfileMapping{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "newer", Namespace: "default"}, newer)).To(Succeed())
+		Expect(newer.Status.ObservedGeneration).To(Equal(newer.Generation))
+		Expect(newer.Status.MappingErrors).To(HaveLen(3))
+		Expect(newer.Status.MappingErrors[0]).To(And(
+			HaveField("Index", 1),
+			HaveField("Reason", csiv1.ClientProfileMappingConflictingPoolIdReason),
+		))
+		Expect(newer.Status.MappingErrors[1]).To(And(
+			HaveField("Index", 2),
+			HaveField("Reason", csiv1.ClientProfileMappingConflictingPoolIdReason),
+		))
+		Expect(newer.Status.MappingErrors[2]).To(And(
+			HaveField("Index", 3),
+			HaveField("Reason", csiv1.ClientProfileMappingRemoteClientProfileNotFoundReason),
+		))
+		Expect(meta.FindStatusCondition(newer.Status.Conditions, csiv1.ClientProfileMappingReadyCondition)).To(And(
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", csiv1.ClientProfileMappingInvalidMappingsReason),
+		))
+	})
+})
+
+var _ = Describe("ClientProfileMapping Controller namespace reconciliation", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *ClientProfileMappingReconciler
+		recorder   *events.FakeRecorder
+	)
+
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: utils.CsiConfigVolume.Name, Namespace: "default"},
+	}
+
+	getConfigMap := func() *corev1.ConfigMap {
+		configMap := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, request.NamespacedName, configMap)).To(Succeed())
+		return configMap
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		testScheme := runtime.NewScheme()
+		Expect(csiv1.AddToScheme(testScheme)).To(Succeed())
+		Expect(scheme.AddToScheme(testScheme)).To(Succeed())
+
+		objects := []client.Object{}
+		for _, name := range []string{"local", "remote"} {
+			objects = append(objects, &csiv1.ClientProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			})
+		}
+		for _, name := range []string{"first", "second"} {
+			objects = append(objects, &csiv1.ClientProfileMapping{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name)},
+				Spec: csiv1.ClientProfileMappingSpec{
+					Mappings: []csiv1.MappingsSpec{{
+						LocalClientProfile:  "local",
+						RemoteClientProfile: "remote",
+						BlockPoolIdMapping:  []csiv1.BlockPoolIdPair{{"1", "2"}},
+					}},
+				},
+			})
+		}
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(testScheme).
+			WithObjects(objects...).
+			WithStatusSubresource(&csiv1.ClientProfileMapping{}).
+			Build()
+
+		recorder = events.NewFakeRecorder(10)
+		reconciler = &ClientProfileMappingReconciler{
+			Client:   fakeClient,
+			Scheme:   testScheme,
+			Recorder: recorder,
+		}
+	})
+
+	It("should be owned by the mappings of the namespace and skip unchanged writes", func() {
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		configMap := getConfigMap()
+		Expect(configMap.OwnerReferences).To(HaveLen(2))
+		Expect(recorder.Events).To(HaveLen(2))
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getConfigMap().ResourceVersion).To(Equal(configMap.ResourceVersion))
+		Expect(recorder.Events).To(HaveLen(2), "unchanged mappings should not be published again")
+	})
+
+	It("should drop the data of deleted mappings", func() {
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		first := &csiv1.ClientProfileMapping{}
+		first.Name = "first"
+		first.Namespace = "default"
+		Expect(fakeClient.Delete(ctx, first)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		configMap := getConfigMap()
+		Expect(configMap.OwnerReferences).To(ConsistOf(HaveField("Name", "second")))
+		Expect(configMap.Data).To(HaveKey(utils.CsiConfigMapMappingKey))
+
+		second := &csiv1.ClientProfileMapping{}
+		second.Name = "second"
+		second.Namespace = "default"
+		Expect(fakeClient.Delete(ctx, second)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		configMap = getConfigMap()
+		Expect(configMap.OwnerReferences).To(BeEmpty())
+		Expect(configMap.Data).NotTo(HaveKey(utils.CsiConfigMapMappingKey))
+	})
+})
+
+var _ = Describe("ClientProfileMapping Controller migration", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *ClientProfileMappingReconciler
+	)
+
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: utils.CsiConfigVolume.Name, Namespace: "default"},
+	}
+
+	getMapping := func() *csiv1.ClientProfileMapping {
+		mapping := &csiv1.ClientProfileMapping{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "mapping", Namespace: "default"}, mapping)).To(Succeed())
+		return mapping
+	}
+
+	setup := func(mappings []csiv1.MappingsSpec, objects ...client.Object) {
+		ctx = context.Background()
+
+		testScheme := runtime.NewScheme()
+		Expect(csiv1.AddToScheme(testScheme)).To(Succeed())
+		Expect(scheme.AddToScheme(testScheme)).To(Succeed())
+
+		for _, name := range []string{"local-a", "local-b", "local-c", "local-d", "remote-a", "remote-b", "remote-c"} {
+			objects = append(objects, &csiv1.ClientProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			})
+		}
+		objects = append(objects, &csiv1.ClientProfileMapping{
+			ObjectMeta: metav1.ObjectMeta{Name: "mapping", Namespace: "default", UID: "mapping"},
+			Spec: csiv1.ClientProfileMappingSpec{
+				Mappings: mappings,
+				Migration: &csiv1.MappingMigrationSpec{
+					BlockPoolNames: []csiv1.BlockPoolNamesSpec{{
+						ClientProfile: "local-a",
+						Names:         map[string]string{"1": "rbd", "3": "replicapool"},
+					}},
+				},
+			},
+		})
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(testScheme).
+			WithObjects(objects...).
+			WithStatusSubresource(&csiv1.ClientProfileMapping{}).
+			Build()
+
+		reconciler = &ClientProfileMappingReconciler{
+			Client: fakeClient,
+			Scheme: testScheme,
+		}
+	}
+
+	It("should generate a ClientProfileReplication and mark the mapping superseded once published", func() {
+		setup([]csiv1.MappingsSpec{{
+			LocalClientProfile:  "local-a",
+			RemoteClientProfile: "remote-a",
+			BlockPoolIdMapping:  []csiv1.BlockPoolIdPair{{"1", "2"}, {"3", "4"}},
+		}})
+
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		cpr := &csiv1.ClientProfileReplication{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "local-a-remote-a", Namespace: "default"}, cpr)).To(Succeed())
+		Expect(cpr.Labels).To(HaveKeyWithValue(migratedFromMappingLabel, "true"))
+		Expect(cpr.Spec).To(Equal(csiv1.ClientProfileReplicationSpec{
+			LocalClientProfile:  "local-a",
+			RemoteClientProfile: "remote-a",
+			RBD: &csiv1.RBDReplicationSpec{
+				PoolMapping: []csiv1.PoolMappingSpec{
+					{Name: "rbd", RemoteID: "2"},
+					{Name: "replicapool", RemoteID: "4"},
+				},
+			},
+		}))
+
+		mapping := getMapping()
+		Expect(mapping.Status.Migration.ClientProfileReplications).To(Equal([]string{cpr.Name}))
+		Expect(meta.FindStatusCondition(mapping.Status.Conditions, csiv1.ClientProfileMappingSupersededCondition)).
+			To(HaveField("Reason", csiv1.ClientProfileMappingVerificationPendingReason))
+
+		// Publish the replication destination the way the ClientProfile controller does
+		configMap := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, request.NamespacedName, configMap)).To(Succeed())
+		records, err := json.Marshal([]*csiClusterInfoRecord{{
+			ClusterId: "local-a",
+			ReplicationDestinations: []*replicationDestinationInfo{{
+				RemoteClusterID: "remote-a",
+				Rbd: &remoteRbdDetails{
+					RemotePoolMapping: map[string]remotePoolDetails{
+						"rbd":         {PoolID: "2"},
+						"replicapool": {PoolID: "4"},
+					},
+				},
+			}},
+		}})
+		Expect(err).NotTo(HaveOccurred())
+		configMap.Data[utils.CsiConfigMapConfigKey] = string(records)
+		Expect(fakeClient.Update(ctx, configMap)).To(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(meta.IsStatusConditionTrue(getMapping().Status.Conditions, csiv1.ClientProfileMappingSupersededCondition)).
+			To(BeTrue())
+	})
+
+	It("should report the mappings that cannot be migrated", func() {
+		setup(
+			[]csiv1.MappingsSpec{
+				{LocalClientProfile: "local-b", RemoteClientProfile: "remote-b"},
+				{LocalClientProfile: "local-b", RemoteClientProfile: "remote-c"},
+				{
+					LocalClientProfile:  "local-c",
+					RemoteClientProfile: "remote-a",
+					BlockPoolIdMapping:  []csiv1.BlockPoolIdPair{{"7", "8"}},
+				},
+				{LocalClientProfile: "local-d", RemoteClientProfile: "remote-a"},
+				{LocalClientProfile: "local-a", RemoteClientProfile: "missing"},
+			},
+			&csiv1.ClientProfileReplication{
+				ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"},
+				Spec: csiv1.ClientProfileReplicationSpec{
+					LocalClientProfile:  "local-d",
+					RemoteClientProfile: "remote-a",
+					RBD: &csiv1.RBDReplicationSpec{
+						PoolMapping: []csiv1.PoolMappingSpec{{Name: "rbd", RemoteID: "9"}},
+					},
+				},
+			},
+		)
+
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Generating a ClientProfileReplication per remote ClientProfile")
+		cprList := &csiv1.ClientProfileReplicationList{}
+		Expect(fakeClient.List(ctx, cprList)).To(Succeed())
+		Expect(cprList.Items).To(ConsistOf(
+			HaveField("Name", "existing"),
+			HaveField("Name", "local-b-remote-b"),
+			HaveField("Name", "local-b-remote-c"),
+		))
+
+		mapping := getMapping()
+		Expect(mapping.Status.Migration.ClientProfileReplications).To(Equal([]string{"local-b-remote-b", "local-b-remote-c"}))
+		Expect(mapping.Status.Migration.UnsupportedMappings).To(HaveExactElements(
+			And(HaveField("Index", 2), HaveField("Reason", csiv1.ClientProfileMappingUnknownBlockPoolNameReason)),
+			And(HaveField("Index", 3), HaveField("Reason", csiv1.ClientProfileMappingConflictingReplicationReason)),
+			And(HaveField("Index", 4), HaveField("Reason", csiv1.ClientProfileMappingInvalidMappingReason)),
+		))
+		Expect(meta.FindStatusCondition(mapping.Status.Conditions, csiv1.ClientProfileMappingSupersededCondition)).To(And(
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", csiv1.ClientProfileMappingMigrationIncompleteReason),
+		))
+	})
+
+	It("should not adopt a ClientProfileReplication with the generated name", func() {
+		userSpec := csiv1.ClientProfileReplicationSpec{
+			LocalClientProfile:  "local-c",
+			RemoteClientProfile: "remote-c",
+		}
+		setup(
+			[]csiv1.MappingsSpec{{LocalClientProfile: "local-b", RemoteClientProfile: "remote-b"}},
+			&csiv1.ClientProfileReplication{
+				ObjectMeta: metav1.ObjectMeta{Name: "local-b-remote-b", Namespace: "default"},
+				Spec:       userSpec,
+			},
+		)
+
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		cpr := &csiv1.ClientProfileReplication{}
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Name: "local-b-remote-b", Namespace: "default"}, cpr)).
+			To(Succeed())
+		Expect(cpr.Labels).NotTo(HaveKey(migratedFromMappingLabel))
+		Expect(cpr.Spec).To(Equal(userSpec))
+
+		mapping := getMapping()
+		Expect(mapping.Status.Migration.ClientProfileReplications).To(BeEmpty())
+		Expect(mapping.Status.Migration.UnsupportedMappings).To(HaveExactElements(
+			And(HaveField("Index", 0), HaveField("Reason", csiv1.ClientProfileMappingReplicationNameConflictReason)),
+		))
+		Expect(meta.FindStatusCondition(mapping.Status.Conditions, csiv1.ClientProfileMappingSupersededCondition)).To(And(
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", csiv1.ClientProfileMappingMigrationIncompleteReason),
+		))
+	})
+
+	It("should delete the generated ClientProfileReplications once they are not migrated anymore", func() {
+		setup(
+			[]csiv1.MappingsSpec{
+				{LocalClientProfile: "local-b", RemoteClientProfile: "remote-b"},
+				{LocalClientProfile: "local-c", RemoteClientProfile: "remote-c"},
+			},
+			&csiv1.ClientProfileReplication{
+				ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"},
+				Spec: csiv1.ClientProfileReplicationSpec{
+					LocalClientProfile:  "local-d",
+					RemoteClientProfile: "remote-a",
+				},
+			},
+		)
+		cprNames := func() []string {
+			cprList := &csiv1.ClientProfileReplicationList{}
+			Expect(fakeClient.List(ctx, cprList)).To(Succeed())
+			names := []string{}
+			for _, cpr := range cprList.Items {
+				names = append(names, cpr.Name)
+			}
+			return names
+		}
+
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cprNames()).To(ConsistOf("existing", "local-b-remote-b", "local-c-remote-c"))
+
+		By("Deleting the ClientProfileReplication of a removed mapping")
+		mapping := getMapping()
+		mapping.Spec.Mappings = mapping.Spec.Mappings[:1]
+		Expect(fakeClient.Update(ctx, mapping)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cprNames()).To(ConsistOf("existing", "local-b-remote-b"))
+
+		By("Deleting all the generated ClientProfileReplications when the migration is turned off")
+		mapping = getMapping()
+		mapping.Spec.Migration = nil
+		Expect(fakeClient.Update(ctx, mapping)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cprNames()).To(ConsistOf("existing"))
+
+		By("Deleting all the generated ClientProfileReplications when the mapping is deleted")
+		mapping = getMapping()
+		mapping.Spec.Migration = &csiv1.MappingMigrationSpec{}
+		Expect(fakeClient.Update(ctx, mapping)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cprNames()).To(ConsistOf("existing", "local-b-remote-b"))
+		Expect(fakeClient.Delete(ctx, getMapping())).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cprNames()).To(ConsistOf("existing"))
+	})
+})
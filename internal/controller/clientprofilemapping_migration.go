@@ -0,0 +1,395 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	csiv1 "github.com/ceph/ceph-csi-operator/api/v1"
+	"github.com/ceph/ceph-csi-operator/internal/utils"
+)
+
+// Label set on the ClientProfileReplications generated from ClientProfileMappings
+const migratedFromMappingLabel = "csi.ceph.io/migrated-from-clientprofilemapping"
+
+// mappingRef references a mapping of a ClientProfileMapping by index
+type mappingRef struct {
+	item  *csiv1.ClientProfileMapping
+	index int
+}
+
+// replicationPair is the destination of a ClientProfileReplication, a local ClientProfile is
+// replicated to a remote ClientProfile
+type replicationPair struct {
+	local  string
+	remote string
+}
+
+// migrationResult is the outcome of the migration of a ClientProfileMapping
+type migrationResult struct {
+	status csiv1.MappingMigrationStatus
+	// Whether the replication destinations of all the migrated mappings are published
+	verified bool
+}
+
+// reconcileMigration generates a ClientProfileReplication per local and remote ClientProfile pair
+// mapped by the ClientProfileMappings that opted in to the migration, and verifies the replication
+// destinations published to the Ceph CSI config match the mappings. The ClientProfileReplication of
+// a pair replicates all the valid mappings of the namespace for that pair, so that it matches the
+// published cluster mappings. Generated ClientProfileReplications that no longer replicate migrated
+// mappings are deleted.
+func (r *ClientProfileMappingReconcile) reconcileMigration() error {
+	r.migrationResults = map[types.UID]*migrationResult{}
+
+	// The mappings of the namespace by ClientProfile pair, in the order they are validated
+	pairs := []replicationPair{}
+	mappingsByPair := map[replicationPair][]mappingRef{}
+	// The names of the block pools by local ClientProfile and pool ID, from the oldest mapping naming them
+	blockPoolNames := map[string]map[string]string{}
+	// The outcome of the mappings of the migrated ClientProfileMappings that cannot be migrated
+	unsupported := map[mappingRef]csiv1.MappingErrorStatus{}
+
+	for i := range r.clientProfileMappingList.Items {
+		item := &r.clientProfileMappingList.Items[i]
+		if item.Spec.Migration != nil {
+			r.migrationResults[item.UID] = &migrationResult{verified: true}
+			for _, poolNames := range item.Spec.Migration.BlockPoolNames {
+				if blockPoolNames[poolNames.ClientProfile] == nil {
+					blockPoolNames[poolNames.ClientProfile] = map[string]string{}
+				}
+				for poolID, poolName := range poolNames.Names {
+					if _, ok := blockPoolNames[poolNames.ClientProfile][poolID]; !ok {
+						blockPoolNames[poolNames.ClientProfile][poolID] = poolName
+					}
+				}
+			}
+		}
+
+		invalid := map[int]bool{}
+		for _, mappingError := range r.mappingErrors[item.UID] {
+			invalid[mappingError.Index] = true
+			if item.Spec.Migration != nil {
+				unsupported[mappingRef{item, mappingError.Index}] = csiv1.MappingErrorStatus{
+					Reason:  csiv1.ClientProfileMappingInvalidMappingReason,
+					Message: mappingError.Message,
+				}
+			}
+		}
+		for j := range item.Spec.Mappings {
+			if invalid[j] {
+				continue
+			}
+			pair := replicationPair{
+				local:  item.Spec.Mappings[j].LocalClientProfile,
+				remote: item.Spec.Mappings[j].RemoteClientProfile,
+			}
+			if _, ok := mappingsByPair[pair]; !ok {
+				pairs = append(pairs, pair)
+			}
+			mappingsByPair[pair] = append(mappingsByPair[pair], mappingRef{item, j})
+		}
+	}
+	if len(r.migrationResults) == 0 {
+		return r.deleteStaleMigratedReplications(nil)
+	}
+
+	cprList := csiv1.ClientProfileReplicationList{}
+	if err := r.List(r.ctx, &cprList, client.InNamespace(r.req.Namespace)); err != nil {
+		r.log.Error(err, "Failed listing ClientProfileReplication CRs in namespace", "namespace", r.req.Namespace)
+		return err
+	}
+	publishedRecords, err := r.loadCsiClusterInfoRecords()
+	if err != nil {
+		return err
+	}
+
+	// The names of the ClientProfileReplications generated for the migrated mappings
+	generated := []string{}
+	for _, pair := range pairs {
+		mappings := mappingsByPair[pair]
+		if !slices.ContainsFunc(mappings, func(ref mappingRef) bool { return ref.item.Spec.Migration != nil }) {
+			continue
+		}
+		localProfile, remoteProfile := pair.local, pair.remote
+		log := r.log.WithValues("localClientProfile", localProfile, "remoteClientProfile", remoteProfile)
+
+		rejected := false
+		reject := func(reason string, message string, refs ...mappingRef) {
+			rejected = true
+			for _, ref := range refs {
+				if _, ok := unsupported[ref]; !ok && ref.item.Spec.Migration != nil {
+					unsupported[ref] = csiv1.MappingErrorStatus{Reason: reason, Message: message}
+				}
+			}
+		}
+
+		// ClientProfileReplication pool mappings are keyed by pool name
+		poolMapping := map[string]string{}
+		poolIDsByName := map[string]string{}
+		for _, ref := range mappings {
+			for _, pair := range ref.item.Spec.Mappings[ref.index].BlockPoolIdMapping {
+				poolName, ok := blockPoolNames[localProfile][pair[0]]
+				if !ok {
+					reject(
+						csiv1.ClientProfileMappingUnknownBlockPoolNameReason,
+						fmt.Sprintf(
+							"block pool ID %s of ClientProfile %s is not named in .spec.migration.blockPoolNames",
+							pair[0],
+							localProfile,
+						),
+						ref,
+					)
+					continue
+				}
+				if poolID, ok := poolIDsByName[poolName]; ok && poolID != pair[0] {
+					reject(
+						csiv1.ClientProfileMappingDuplicateBlockPoolNameReason,
+						fmt.Sprintf(
+							"block pool IDs %s and %s of ClientProfile %s are both named %s",
+							poolID,
+							pair[0],
+							localProfile,
+							poolName,
+						),
+						ref,
+					)
+					continue
+				}
+				poolIDsByName[poolName] = pair[0]
+				poolMapping[poolName] = pair[1]
+			}
+		}
+		if rejected {
+			// A partial ClientProfileReplication would not match the published cluster mappings
+			reject(
+				csiv1.ClientProfileMappingMigrationIncompleteReason,
+				fmt.Sprintf("other mappings of ClientProfile %s to %s cannot be migrated", localProfile, remoteProfile),
+				mappings...,
+			)
+			continue
+		}
+
+		desiredSpec := csiv1.ClientProfileReplicationSpec{
+			LocalClientProfile:  localProfile,
+			RemoteClientProfile: remoteProfile,
+		}
+		if len(poolMapping) > 0 {
+			desiredSpec.RBD = &csiv1.RBDReplicationSpec{}
+			for _, poolName := range slices.Sorted(maps.Keys(poolMapping)) {
+				desiredSpec.RBD.PoolMapping = append(desiredSpec.RBD.PoolMapping, csiv1.PoolMappingSpec{
+					Name:     poolName,
+					RemoteID: poolMapping[poolName],
+				})
+			}
+		}
+
+		// Reuse an equivalent ClientProfileReplication, the local ClientProfile can only be replicated
+		// to the remote ClientProfile by a single ClientProfileReplication
+		cprName := fmt.Sprintf("%s-%s", localProfile, remoteProfile)
+		replicatedBy := ""
+		conflicting := []string{}
+		for i := range cprList.Items {
+			cpr := &cprList.Items[i]
+			if cpr.Spec.LocalClientProfile != localProfile || cpr.Spec.RemoteClientProfile != remoteProfile ||
+				(cpr.Name == cprName && cpr.Labels[migratedFromMappingLabel] == "true") {
+				continue
+			}
+			if equivalentReplicationSpecs(&cpr.Spec, &desiredSpec) {
+				replicatedBy = cpr.Name
+				break
+			}
+			conflicting = append(conflicting, cpr.Name)
+		}
+		if replicatedBy == "" && len(conflicting) > 0 {
+			reject(
+				csiv1.ClientProfileMappingConflictingReplicationReason,
+				fmt.Sprintf(
+					"ClientProfileReplications %s replicate ClientProfile %s to %s with different mappings",
+					strings.Join(conflicting, ", "),
+					localProfile,
+					remoteProfile,
+				),
+				mappings...,
+			)
+			continue
+		}
+
+		if replicatedBy == "" {
+			replicatedBy = cprName
+			cpr := &csiv1.ClientProfileReplication{}
+			cpr.Name = cprName
+			cpr.Namespace = r.req.Namespace
+			nameConflict := false
+			opResult, err := ctrlutil.CreateOrUpdate(r.ctx, r.Client, cpr, func() error {
+				// Never adopt a ClientProfileReplication created by someone else
+				if cpr.ResourceVersion != "" && cpr.Labels[migratedFromMappingLabel] != "true" {
+					nameConflict = true
+					return fmt.Errorf("ClientProfileReplication %s exists and was not generated by the migration", cpr.Name)
+				}
+				if cpr.Labels == nil {
+					cpr.Labels = map[string]string{}
+				}
+				cpr.Labels[migratedFromMappingLabel] = "true"
+				cpr.Spec = desiredSpec
+				return nil
+			})
+			if nameConflict {
+				reject(csiv1.ClientProfileMappingReplicationNameConflictReason, err.Error(), mappings...)
+				continue
+			}
+			if err != nil {
+				log.Error(err, "Failed to reconcile the ClientProfileReplication of the mappings", "name", cprName)
+				return err
+			}
+			log.Info("ClientProfileReplication of the mappings reconciled", "name", cprName, "operation", opResult)
+			generated = append(generated, cprName)
+		}
+
+		// The migration is complete once the ClientProfile controller published the replication destination
+		// of the ClientProfileReplication
+		expected := &replicationDestinationInfo{RemoteClusterID: remoteProfile}
+		if len(poolMapping) > 0 {
+			expected.Rbd = &remoteRbdDetails{RemotePoolMapping: map[string]remotePoolDetails{}}
+			for poolName, remoteID := range poolMapping {
+				expected.Rbd.RemotePoolMapping[poolName] = remotePoolDetails{PoolID: remoteID}
+			}
+		}
+		recordIndex := slices.IndexFunc(publishedRecords, func(record *csiClusterInfoRecord) bool {
+			return record.ClusterId == localProfile
+		})
+		verified := recordIndex > -1 && slices.ContainsFunc(
+			publishedRecords[recordIndex].ReplicationDestinations,
+			func(published *replicationDestinationInfo) bool { return reflect.DeepEqual(published, expected) },
+		)
+		if !verified {
+			log.Info("Replication destination of the mappings is not published yet", "clientProfileReplication", replicatedBy)
+		}
+
+		for _, ref := range mappings {
+			if result := r.migrationResults[ref.item.UID]; result != nil {
+				if !slices.Contains(result.status.ClientProfileReplications, replicatedBy) {
+					result.status.ClientProfileReplications = append(result.status.ClientProfileReplications, replicatedBy)
+				}
+				result.verified = result.verified && verified
+			}
+		}
+	}
+
+	if err := r.deleteStaleMigratedReplications(generated); err != nil {
+		return err
+	}
+
+	for i := range r.clientProfileMappingList.Items {
+		item := &r.clientProfileMappingList.Items[i]
+		result := r.migrationResults[item.UID]
+		if result == nil {
+			continue
+		}
+		slices.Sort(result.status.ClientProfileReplications)
+		for j := range item.Spec.Mappings {
+			if mappingError, ok := unsupported[mappingRef{item, j}]; ok {
+				mappingError.Index = j
+				mappingError.LocalClientProfile = item.Spec.Mappings[j].LocalClientProfile
+				mappingError.RemoteClientProfile = item.Spec.Mappings[j].RemoteClientProfile
+				result.status.UnsupportedMappings = append(result.status.UnsupportedMappings, mappingError)
+			}
+		}
+	}
+
+	return nil
+}
+
+// deleteStaleMigratedReplications deletes the ClientProfileReplications previously generated by the migration
+// that are not generated anymore, when the migration is turned off, the ClientProfileMappings are deleted
+// or their mappings are replicated by other ClientProfileReplications
+func (r *ClientProfileMappingReconcile) deleteStaleMigratedReplications(generated []string) error {
+	cprList := csiv1.ClientProfileReplicationList{}
+	if err := r.List(
+		r.ctx,
+		&cprList,
+		client.InNamespace(r.req.Namespace),
+		client.MatchingLabels{migratedFromMappingLabel: "true"},
+	); err != nil {
+		r.log.Error(err, "Failed listing migrated ClientProfileReplication CRs in namespace", "namespace", r.req.Namespace)
+		return err
+	}
+	for i := range cprList.Items {
+		cpr := &cprList.Items[i]
+		if slices.Contains(generated, cpr.Name) {
+			continue
+		}
+		if err := r.Delete(r.ctx, cpr); client.IgnoreNotFound(err) != nil {
+			r.log.Error(err, "Failed to delete stale migrated ClientProfileReplication", "name", cpr.Name)
+			return err
+		}
+		r.log.Info("Deleted stale migrated ClientProfileReplication", "name", cpr.Name)
+	}
+	return nil
+}
+
+// loadCsiClusterInfoRecords loads the cluster info records published to the Ceph CSI config
+func (r *ClientProfileMappingReconcile) loadCsiClusterInfoRecords() ([]*csiClusterInfoRecord, error) {
+	csiConfigMap := corev1.ConfigMap{}
+	csiConfigMap.Name = utils.CsiConfigVolume.Name
+	csiConfigMap.Namespace = r.req.Namespace
+	if err := r.Get(r.ctx, client.ObjectKeyFromObject(&csiConfigMap), &csiConfigMap); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		r.log.Error(err, "Failed loading Ceph CSI config map")
+		return nil, err
+	}
+
+	clusterInfoList := []*csiClusterInfoRecord{}
+	if configsAsJson := csiConfigMap.Data[utils.CsiConfigMapConfigKey]; configsAsJson != "" {
+		if err := json.Unmarshal([]byte(configsAsJson), &clusterInfoList); err != nil {
+			r.log.Error(err, "Failed to parse cluster info list under \"config.json\" key")
+			return nil, err
+		}
+	}
+	return clusterInfoList, nil
+}
+
+// equivalentReplicationSpecs returns true when both specs replicate to the same destination. Mappings only
+// map block pools, so a spec with CephFS mappings is never equivalent to a migrated one.
+func equivalentReplicationSpecs(a, b *csiv1.ClientProfileReplicationSpec) bool {
+	poolMapping := func(spec *csiv1.ClientProfileReplicationSpec) map[string]string {
+		mapping := map[string]string{}
+		if spec.RBD != nil {
+			for _, pool := range spec.RBD.PoolMapping {
+				mapping[pool.Name] = pool.RemoteID
+			}
+		}
+		return mapping
+	}
+	return a.LocalClientProfile == b.LocalClientProfile &&
+		a.RemoteClientProfile == b.RemoteClientProfile &&
+		maps.Equal(poolMapping(a), poolMapping(b)) &&
+		equality.Semantic.DeepEqual(a.CephFS, b.CephFS)
+}
@@ -19,17 +19,26 @@ package controller
 import (
 	"context"
 	"fmt"
-	"sort"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	csiv1 "github.com/ceph/ceph-csi-operator/api/v1"
@@ -42,13 +51,18 @@ import (
 // +kubebuilder:rbac:groups=csi.ceph.io,resources=clientprofiles,verbs=get;list;watch
 
 const (
-	clientProfileIndexKey = "index:spec.localClientProfile"
+	clientProfileIndexKey       = "index:spec.localClientProfile"
+	remoteClientProfileIndexKey = "index:spec.remoteClientProfile"
+
+	// Event reason reported when a ClientProfileReplication is rejected
+	replicationRejectedReason = "Rejected"
 )
 
 // ClientProfileReplicationReconciler reconciles a ClientProfileReplication object
 type ClientProfileReplicationReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder events.EventRecorder
 }
 
 // A local reconcile object tied to a single reconcile iteration
@@ -77,32 +91,60 @@ func (r *ClientProfileReplicationReconciler) SetupWithManager(mgr ctrl.Manager)
 	); err != nil {
 		return err
 	}
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&csiv1.ClientProfileReplication{},
+		remoteClientProfileIndexKey,
+		func(obj client.Object) []string {
+			cpr := obj.(*csiv1.ClientProfileReplication)
+			if cpr.Spec.RemoteClientProfile != "" {
+				return []string{cpr.Spec.RemoteClientProfile}
+			}
+			return nil
+		},
+	); err != nil {
+		return err
+	}
+
+	// A spec change of a CR re-evaluates the CRs it may conflict with or form a cycle with, through the
+	// ClientProfiles of both its previous and its current spec
+	enqueueConnectedReplications := func(ctx context.Context, q workqueue.TypedRateLimitingInterface[reconcile.Request], objs ...client.Object) {
+		for _, obj := range objs {
+			cpr := obj.(*csiv1.ClientProfileReplication)
+			for _, request := range r.connectedReplicationRequests(
+				ctx,
+				cpr.Namespace,
+				cpr.Spec.LocalClientProfile,
+				cpr.Spec.RemoteClientProfile,
+			) {
+				q.Add(request)
+			}
+		}
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&csiv1.ClientProfileReplication{}).
-		// Watch ClientProfile CRs to trigger reconciliation when they are created/deleted
+		Watches(
+			&csiv1.ClientProfileReplication{},
+			handler.Funcs{
+				CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+					enqueueConnectedReplications(ctx, q, e.Object)
+				},
+				UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+					enqueueConnectedReplications(ctx, q, e.ObjectOld, e.ObjectNew)
+				},
+				DeleteFunc: func(ctx context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+					enqueueConnectedReplications(ctx, q, e.Object)
+				},
+			},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		// Watch ClientProfile CRs to trigger reconciliation when they are created/deleted, for the CRs
+		// referencing them as local or remote ClientProfile and the CRs connected to them
 		Watches(
 			&csiv1.ClientProfile{},
 			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
-				clientProfile := obj.(*csiv1.ClientProfile)
-
-				cprList := &csiv1.ClientProfileReplicationList{}
-				if err := r.List(ctx, cprList,
-					client.InNamespace(clientProfile.Namespace),
-					client.MatchingFields{clientProfileIndexKey: clientProfile.Name}); err != nil {
-					return []reconcile.Request{}
-				}
-
-				requests := make([]reconcile.Request, len(cprList.Items))
-				for i, item := range cprList.Items {
-					requests[i] = reconcile.Request{
-						NamespacedName: types.NamespacedName{
-							Name:      item.Name,
-							Namespace: item.Namespace,
-						},
-					}
-				}
-				return requests
+				return r.connectedReplicationRequests(ctx, obj.GetNamespace(), obj.GetName())
 			}),
 			builder.WithPredicates(
 				utils.EventTypePredicate(true, false, true, false),
@@ -111,10 +153,57 @@ func (r *ClientProfileReplicationReconciler) SetupWithManager(mgr ctrl.Manager)
 		Complete(r)
 }
 
+// connectedReplicationRequests returns the requests of the ClientProfileReplications referencing the
+// given ClientProfiles, and of the ClientProfileReplications connected to them through other
+// ClientProfiles. Conflicts and cycles are only found between connected ClientProfileReplications.
+func (r *ClientProfileReplicationReconciler) connectedReplicationRequests(
+	ctx context.Context,
+	namespace string,
+	clientProfiles ...string,
+) []reconcile.Request {
+	requests := []reconcile.Request{}
+	visitedProfiles := map[string]bool{}
+	visitedReplications := map[string]bool{}
+	for len(clientProfiles) > 0 {
+		clientProfile := clientProfiles[0]
+		clientProfiles = clientProfiles[1:]
+		if visitedProfiles[clientProfile] {
+			continue
+		}
+		visitedProfiles[clientProfile] = true
+
+		for _, indexKey := range []string{clientProfileIndexKey, remoteClientProfileIndexKey} {
+			cprList := &csiv1.ClientProfileReplicationList{}
+			if err := r.List(ctx, cprList,
+				client.InNamespace(namespace),
+				client.MatchingFields{indexKey: clientProfile}); err != nil {
+				ctrllog.FromContext(ctx).Error(err, "failed to list ClientProfileReplication CRs", "clientProfile", clientProfile)
+				continue
+			}
+			for i := range cprList.Items {
+				item := &cprList.Items[i]
+				if visitedReplications[item.Name] {
+					continue
+				}
+				visitedReplications[item.Name] = true
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      item.Name,
+						Namespace: item.Namespace,
+					},
+				})
+				clientProfiles = append(clientProfiles, item.Spec.LocalClientProfile, item.Spec.RemoteClientProfile)
+			}
+		}
+	}
+	return requests
+}
+
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *ClientProfileReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := ctrllog.FromContext(ctx)
+	ctx, span := startReconcileSpan(ctx, "ClientProfileReplication", req)
+	log := utils.LoggerWithTraceID(ctx, ctrllog.FromContext(ctx))
 	log.Info("Starting reconcile iteration for ClientProfileReplication", "req", req)
 
 	reconcileHandler := ClientProfileReplicationReconcile{}
@@ -130,6 +219,7 @@ func (r *ClientProfileReplicationReconciler) Reconcile(ctx context.Context, req
 	} else {
 		log.Info("ClientProfileReplication reconciliation completed successfully")
 	}
+	utils.EndSpan(span, err)
 
 	return ctrl.Result{}, err
 }
@@ -146,7 +236,14 @@ func (r *ClientProfileReplicationReconcile) reconcile() error {
 		return err
 	}
 
+	previousPhase := r.clientProfileReplication.Status.Phase
+	r.initConditions()
 	reconcileErr := r.reconcilePhases()
+	r.clientProfileReplication.Status.ObservedGeneration = r.clientProfileReplication.Generation
+	if status := &r.clientProfileReplication.Status; status.Phase == csiv1.ClientProfileReplicationPhaseRejected &&
+		previousPhase != csiv1.ClientProfileReplicationPhaseRejected {
+		r.recordEvent(corev1.EventTypeWarning, replicationRejectedReason, "Validate", "%s", status.Message)
+	}
 
 	statusErr := r.Status().Update(r.ctx, &r.clientProfileReplication)
 	if statusErr != nil {
@@ -160,6 +257,13 @@ func (r *ClientProfileReplicationReconcile) reconcile() error {
 	return nil
 }
 
+// recordEvent records an event on the client profile replication, if an event recorder is available
+func (r *ClientProfileReplicationReconcile) recordEvent(eventType, reason, action, note string, args ...any) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(&r.clientProfileReplication, nil, eventType, reason, action, note, args...)
+	}
+}
+
 func (r *ClientProfileReplicationReconcile) reconcilePhases() error {
 	r.clientProfileReplication.Status.Phase = csiv1.ClientProfileReplicationPhasePending
 	// Validate that the referenced ClientProfile exists
@@ -170,58 +274,302 @@ func (r *ClientProfileReplicationReconcile) reconcilePhases() error {
 		if errors.IsNotFound(err) {
 			// ClientProfile not found - reject this CR
 			r.log.Info("referenced ClientProfile not found, rejecting", "clientProfile", clientProfile.Name)
-			r.clientProfileReplication.Status.Phase = csiv1.ClientProfileReplicationPhaseRejected
-			r.clientProfileReplication.Status.Message = fmt.Sprintf("rejected: ClientProfile '%s' not found", clientProfile.Name)
+			r.setCondition(
+				csiv1.ClientProfileReplicationClientProfileResolvedCondition,
+				metav1.ConditionFalse,
+				csiv1.ClientProfileReplicationClientProfileNotFoundReason,
+				fmt.Sprintf("ClientProfile %s not found", clientProfile.Name),
+			)
+			r.reject(
+				csiv1.ClientProfileReplicationClientProfileNotFoundReason,
+				fmt.Sprintf("ClientProfile '%s' not found", clientProfile.Name),
+			)
 			return nil
 		}
 		r.log.Error(err, "failed to get ClientProfile")
+		r.setLookupFailedCondition(err)
 		return err
 	}
 
-	// Look up all CRs referencing the same localClientProfile
+	// Validate that the remote ClientProfile exists, unless it is declared external
+	spec := &r.clientProfileReplication.Spec
+	resolvedMessage := fmt.Sprintf("ClientProfiles %s and %s found", spec.LocalClientProfile, spec.RemoteClientProfile)
+	if spec.ExternalRemoteClientProfile {
+		resolvedMessage = fmt.Sprintf(
+			"ClientProfile %s found, remote ClientProfile %s is external",
+			spec.LocalClientProfile,
+			spec.RemoteClientProfile,
+		)
+	} else {
+		remoteClientProfile := &csiv1.ClientProfile{}
+		remoteClientProfile.Name = spec.RemoteClientProfile
+		remoteClientProfile.Namespace = r.clientProfileReplication.Namespace
+		if err := r.Get(r.ctx, client.ObjectKeyFromObject(remoteClientProfile), remoteClientProfile); err != nil {
+			if errors.IsNotFound(err) {
+				r.log.Info("referenced remote ClientProfile not found, rejecting", "clientProfile", remoteClientProfile.Name)
+				r.setCondition(
+					csiv1.ClientProfileReplicationClientProfileResolvedCondition,
+					metav1.ConditionFalse,
+					csiv1.ClientProfileReplicationRemoteClientProfileNotFoundReason,
+					fmt.Sprintf("remote ClientProfile %s not found", remoteClientProfile.Name),
+				)
+				r.reject(
+					csiv1.ClientProfileReplicationRemoteClientProfileNotFoundReason,
+					fmt.Sprintf("remote ClientProfile '%s' not found", remoteClientProfile.Name),
+				)
+				return nil
+			}
+			r.log.Error(err, "failed to get remote ClientProfile")
+			r.setLookupFailedCondition(err)
+			return err
+		}
+	}
+	r.setCondition(
+		csiv1.ClientProfileReplicationClientProfileResolvedCondition,
+		metav1.ConditionTrue,
+		csiv1.ClientProfileReplicationClientProfileFoundReason,
+		resolvedMessage,
+	)
+
+	if reason, message := validateReplicationSpec(spec); reason != "" {
+		r.log.Info("invalid ClientProfileReplication spec, rejecting", "reason", reason)
+		r.reject(reason, message)
+		return nil
+	}
+
+	// Look up the ClientProfiles and the CRs of the namespace. The outcome of the conflict and cycle
+	// detection only depends on their specs, not on the status of the other CRs, so that all the CRs
+	// agree on it whatever the order they are reconciled in
+	clientProfileList := &csiv1.ClientProfileList{}
+	if err := r.List(r.ctx, clientProfileList, client.InNamespace(r.clientProfileReplication.Namespace)); err != nil {
+		r.log.Error(err, "failed to list ClientProfile CRs in namespace")
+		r.setLookupFailedCondition(err)
+		return err
+	}
+	clientProfiles := map[string]bool{}
+	for i := range clientProfileList.Items {
+		clientProfiles[clientProfileList.Items[i].Name] = true
+	}
 	cprList := &csiv1.ClientProfileReplicationList{}
-	if err := r.List(
-		r.ctx,
-		cprList,
-		client.InNamespace(r.clientProfileReplication.Namespace),
-		client.MatchingFields{clientProfileIndexKey: clientProfile.Name},
-	); err != nil {
-		r.log.Error(err, "failed to list ClientProfileReplication CRs by localClientProfile")
+	if err := r.List(r.ctx, cprList, client.InNamespace(r.clientProfileReplication.Namespace)); err != nil {
+		r.log.Error(err, "failed to list ClientProfileReplication CRs in namespace")
+		r.setLookupFailedCondition(err)
 		return err
 	}
+	// The list may not contain this CR yet when the cache is stale
+	if !slices.ContainsFunc(cprList.Items, func(item csiv1.ClientProfileReplication) bool {
+		return item.Name == r.clientProfileReplication.Name
+	}) {
+		cprList.Items = append(cprList.Items, r.clientProfileReplication)
+	}
+	slices.SortFunc(cprList.Items, func(a, b csiv1.ClientProfileReplication) int {
+		if replicationCreatedBefore(&a, &b) {
+			return -1
+		}
+		return 1
+	})
+
+	// Evaluate the valid CRs oldest first: the oldest CR wins per destination, a local profile can be
+	// replicated to several remote profiles but only through a single CR per remote profile, and the
+	// newest CR of a replication cycle is rejected
+	winners := map[replicationPair]string{}
+	destinations := map[string][]string{}
+	for i := range cprList.Items {
+		item := &cprList.Items[i]
+		self := item.Name == r.clientProfileReplication.Name
+		if !self && !isValidReplication(&item.Spec, clientProfiles) {
+			continue
+		}
+
+		pair := replicationPair{local: item.Spec.LocalClientProfile, remote: item.Spec.RemoteClientProfile}
+		winner, conflicting := winners[pair]
+		var cycle []string
+		if !conflicting {
+			cycle = replicationCycle(destinations, pair.local, pair.remote)
+		}
+		if !self {
+			if !conflicting && cycle == nil {
+				winners[pair] = item.Name
+				destinations[pair.local] = append(destinations[pair.local], pair.remote)
+			}
+			continue
+		}
+
+		if conflicting {
+			r.log.Info("more than one clientProfileReplication exist for the destination, marking as Rejected", "existing", winner)
+			r.reject(
+				csiv1.ClientProfileReplicationConflictReason,
+				fmt.Sprintf(
+					"another ClientProfileReplication '%s' is already active for localClientProfile '%s' and remoteClientProfile '%s'",
+					winner,
+					spec.LocalClientProfile,
+					spec.RemoteClientProfile,
+				),
+			)
+			return nil
+		}
+		if cycle != nil {
+			r.log.Info("clientProfileReplication closes a replication cycle, marking as Rejected", "cycle", cycle)
+			r.reject(
+				csiv1.ClientProfileReplicationCycleReason,
+				fmt.Sprintf("replication cycle through ClientProfiles %s", strings.Join(cycle, " -> ")),
+			)
+			return nil
+		}
+		break
+	}
 
-	// Step 3: Conflict detection - oldest CR wins
-	// Sort by creation timestamp (oldest first)
-	sort.Slice(cprList.Items, func(i, j int) bool {
-		cprI := cprList.Items[i]
-		cprJ := cprList.Items[j]
+	r.log.Info("this CR is the winner, marking as Ready")
+	r.clientProfileReplication.Status.Phase = csiv1.ClientProfileReplicationPhaseReady
+	r.clientProfileReplication.Status.Message = "accepted"
+	r.setCondition(
+		csiv1.ClientProfileReplicationReadyCondition,
+		metav1.ConditionTrue,
+		csiv1.ClientProfileReplicationAcceptedReason,
+		fmt.Sprintf(
+			"Active replication destination of ClientProfile %s to %s",
+			spec.LocalClientProfile,
+			spec.RemoteClientProfile,
+		),
+	)
 
-		if !cprI.CreationTimestamp.Equal(&cprJ.CreationTimestamp) {
-			return cprI.CreationTimestamp.Before(&cprJ.CreationTimestamp)
+	return nil
+}
+
+// isValidReplication returns true when a ClientProfileReplication references existing ClientProfiles
+// and its mappings are valid, i.e. it is only subject to the conflict and cycle detection
+func isValidReplication(spec *csiv1.ClientProfileReplicationSpec, clientProfiles map[string]bool) bool {
+	if !clientProfiles[spec.LocalClientProfile] ||
+		(!spec.ExternalRemoteClientProfile && !clientProfiles[spec.RemoteClientProfile]) {
+		return false
+	}
+	reason, _ := validateReplicationSpec(spec)
+	return reason == ""
+}
+
+// validateReplicationSpec validates the mappings of a ClientProfileReplication spec on their own,
+// returning the reason and message of the first failure
+func validateReplicationSpec(spec *csiv1.ClientProfileReplicationSpec) (string, string) {
+	if spec.LocalClientProfile == spec.RemoteClientProfile {
+		return csiv1.ClientProfileReplicationCycleReason,
+			fmt.Sprintf("ClientProfile '%s' is replicated to itself", spec.LocalClientProfile)
+	}
+	if spec.RBD != nil {
+		poolNames := map[string]bool{}
+		for _, poolMapping := range spec.RBD.PoolMapping {
+			if poolNames[poolMapping.Name] {
+				return csiv1.ClientProfileReplicationDuplicatePoolNameReason,
+					fmt.Sprintf("pool '%s' is mapped more than once", poolMapping.Name)
+			}
+			poolNames[poolMapping.Name] = true
+			if !isCephID(poolMapping.RemoteID) {
+				return csiv1.ClientProfileReplicationInvalidRemoteIDReason,
+					fmt.Sprintf("remote ID '%s' of pool '%s' is not a non-negative integer", poolMapping.RemoteID, poolMapping.Name)
+			}
+		}
+	}
+	if spec.CephFS != nil {
+		for _, fsMapping := range spec.CephFS.FilesystemMapping {
+			if !isCephID(fsMapping.RemoteID) {
+				return csiv1.ClientProfileReplicationInvalidRemoteIDReason,
+					fmt.Sprintf("remote ID '%s' of filesystem '%s' is not a non-negative integer", fsMapping.RemoteID, fsMapping.Name)
+			}
 		}
+	}
+	return "", ""
+}
 
-		// If timestamps are identical, oldest/first is determined by resource name
-		return cprI.Name < cprJ.Name
-	})
+// isCephID returns true when the ID is a well-formed Ceph pool or filesystem ID
+func isCephID(id string) bool {
+	_, err := strconv.ParseUint(id, 10, 64)
+	return err == nil
+}
 
-	// The oldest CR is the winner
-	winner := &cprList.Items[0]
+// replicationCycle returns the ClientProfiles of the cycle through more than two ClientProfiles that
+// replicating local to remote would close, if any. Replicating two ClientProfiles to each other is
+// the failover and failback configuration and is not a cycle.
+func replicationCycle(destinations map[string][]string, local, remote string) []string {
+	visited := map[string]bool{}
+	var visit func(profile string, path []string) []string
+	visit = func(profile string, path []string) []string {
+		if profile == local {
+			return append(path, profile)
+		}
+		if visited[profile] {
+			return nil
+		}
+		visited[profile] = true
+		path = append(path, profile)
+		for _, next := range destinations[profile] {
+			if profile == remote && next == local {
+				continue
+			}
+			if cycle := visit(next, path); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return visit(remote, []string{local})
+}
 
-	// Step 4: Update status based on whether this CR is the winner
-	if r.clientProfileReplication.Name == winner.Name && r.clientProfileReplication.Namespace == winner.Namespace {
-		r.log.Info("this CR is the winner, marking as Ready")
-		r.clientProfileReplication.Status.Phase = csiv1.ClientProfileReplicationPhaseReady
-		r.clientProfileReplication.Status.Message = "accepted"
-	} else {
-		// This CR is not the winner - mark as Rejected
-		r.log.Info("more than one clientProfileReplication exist, marking as Rejected", "existing", winner.Name)
-		r.clientProfileReplication.Status.Phase = csiv1.ClientProfileReplicationPhaseRejected
-		r.clientProfileReplication.Status.Message = fmt.Sprintf(
-			"rejected: another ClientProfileReplication '%s' is already active for localClientProfile '%s'",
-			winner.Name,
-			clientProfile.Name,
-		)
+// replicationCreatedBefore orders ClientProfileReplications oldest first, by creation timestamp and
+// then by name when the timestamps are identical
+func replicationCreatedBefore(a, b *csiv1.ClientProfileReplication) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
 	}
+	return a.Name < b.Name
+}
 
-	return nil
+// reject marks the ClientProfileReplication as Rejected for the provided reason
+func (r *ClientProfileReplicationReconcile) reject(reason string, message string) {
+	r.clientProfileReplication.Status.Phase = csiv1.ClientProfileReplicationPhaseRejected
+	r.clientProfileReplication.Status.Message = "rejected: " + message
+	r.setCondition(
+		csiv1.ClientProfileReplicationReadyCondition,
+		metav1.ConditionFalse,
+		reason,
+		r.clientProfileReplication.Status.Message,
+	)
+}
+
+// initConditions adds the conditions that were never evaluated as Unknown, so that all the
+// conditions are reported from the first reconcile on
+func (r *ClientProfileReplicationReconcile) initConditions() {
+	for _, conditionType := range []string{
+		csiv1.ClientProfileReplicationClientProfileResolvedCondition,
+		csiv1.ClientProfileReplicationReadyCondition,
+	} {
+		if meta.FindStatusCondition(r.clientProfileReplication.Status.Conditions, conditionType) == nil {
+			r.setCondition(conditionType, metav1.ConditionUnknown, csiv1.ClientProfileReplicationReconcilingReason, "")
+		}
+	}
+}
+
+// setLookupFailedCondition reports a failure to load the resources the ClientProfileReplication is
+// validated against
+func (r *ClientProfileReplicationReconcile) setLookupFailedCondition(err error) {
+	r.setCondition(
+		csiv1.ClientProfileReplicationReadyCondition,
+		metav1.ConditionUnknown,
+		csiv1.ClientProfileReplicationLookupFailedReason,
+		err.Error(),
+	)
+}
+
+// setCondition sets a condition of the ClientProfileReplication, observed on its current generation
+func (r *ClientProfileReplicationReconcile) setCondition(
+	conditionType string,
+	status metav1.ConditionStatus,
+	reason string,
+	message string,
+) {
+	meta.SetStatusCondition(&r.clientProfileReplication.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: r.clientProfileReplication.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
 }
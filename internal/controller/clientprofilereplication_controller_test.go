@@ -18,15 +18,19 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -41,6 +45,7 @@ var _ = Describe("ClientProfileReplication Controller with Fake Client", func()
 		reconciler         *ClientProfileReplicationReconciler
 		testScheme         *runtime.Scheme
 		testClientProfile  *csiv1.ClientProfile
+		testRemoteProfile  *csiv1.ClientProfile
 		testCephConnection *csiv1.CephConnection
 	)
 
@@ -75,10 +80,17 @@ var _ = Describe("ClientProfileReplication Controller with Fake Client", func()
 			},
 		}
 
+		testRemoteProfile = &csiv1.ClientProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "remote-profile",
+				Namespace: "default",
+			},
+		}
+
 		// Create fake client with field index
 		fakeClient = fake.NewClientBuilder().
 			WithScheme(testScheme).
-			WithObjects(testCephConnection, testClientProfile).
+			WithObjects(testCephConnection, testClientProfile, testRemoteProfile).
 			WithStatusSubresource(&csiv1.ClientProfileReplication{}).
 			WithIndex(&csiv1.ClientProfileReplication{}, clientProfileIndexKey, func(obj client.Object) []string {
 				cpr := obj.(*csiv1.ClientProfileReplication)
@@ -87,6 +99,13 @@ var _ = Describe("ClientProfileReplication Controller with Fake Client", func()
 				}
 				return nil
 			}).
+			WithIndex(&csiv1.ClientProfileReplication{}, remoteClientProfileIndexKey, func(obj client.Object) []string {
+				cpr := obj.(*csiv1.ClientProfileReplication)
+				if cpr.Spec.RemoteClientProfile != "" {
+					return []string{cpr.Spec.RemoteClientProfile}
+				}
+				return nil
+			}).
 			Build()
 
 		reconciler = &ClientProfileReplicationReconciler{
@@ -126,6 +145,10 @@ var _ = Describe("ClientProfileReplication Controller with Fake Client", func()
 			}, updated)).To(Succeed())
 			Expect(updated.Status.Phase).To(Equal(csiv1.ClientProfileReplicationPhaseRejected))
 			Expect(updated.Status.Message).To(ContainSubstring("ClientProfile 'nonexistent-profile' not found"))
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, csiv1.ClientProfileReplicationClientProfileResolvedCondition)).
+				To(HaveField("Reason", csiv1.ClientProfileReplicationClientProfileNotFoundReason))
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, csiv1.ClientProfileReplicationReadyCondition)).
+				To(HaveField("Status", metav1.ConditionFalse))
 		})
 	})
 
@@ -165,6 +188,11 @@ var _ = Describe("ClientProfileReplication Controller with Fake Client", func()
 			}, updated)).To(Succeed())
 			Expect(updated.Status.Phase).To(Equal(csiv1.ClientProfileReplicationPhaseReady))
 			Expect(updated.Status.Message).To(Equal("accepted"))
+			Expect(updated.Status.ObservedGeneration).To(Equal(updated.Generation))
+			Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, csiv1.ClientProfileReplicationClientProfileResolvedCondition)).
+				To(BeTrue())
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, csiv1.ClientProfileReplicationReadyCondition)).
+				To(And(HaveField("Status", metav1.ConditionTrue), HaveField("Reason", csiv1.ClientProfileReplicationAcceptedReason)))
 		})
 	})
 
@@ -182,7 +210,7 @@ var _ = Describe("ClientProfileReplication Controller with Fake Client", func()
 				},
 				Spec: csiv1.ClientProfileReplicationSpec{
 					LocalClientProfile:  testClientProfile.Name,
-					RemoteClientProfile: "remote-profile-1",
+					RemoteClientProfile: testRemoteProfile.Name,
 				},
 			}
 
@@ -194,14 +222,14 @@ var _ = Describe("ClientProfileReplication Controller with Fake Client", func()
 				},
 				Spec: csiv1.ClientProfileReplicationSpec{
 					LocalClientProfile:  testClientProfile.Name,
-					RemoteClientProfile: "remote-profile-2",
+					RemoteClientProfile: testRemoteProfile.Name,
 				},
 			}
 
 			// Create a new fake client with both objects pre-populated
 			testFakeClient := fake.NewClientBuilder().
 				WithScheme(testScheme).
-				WithObjects(testCephConnection, testClientProfile, cpr1, cpr2).
+				WithObjects(testCephConnection, testClientProfile, testRemoteProfile, cpr1, cpr2).
 				WithStatusSubresource(&csiv1.ClientProfileReplication{}).
 				WithIndex(&csiv1.ClientProfileReplication{}, clientProfileIndexKey, func(obj client.Object) []string {
 					cpr := obj.(*csiv1.ClientProfileReplication)
@@ -212,9 +240,11 @@ var _ = Describe("ClientProfileReplication Controller with Fake Client", func()
 				}).
 				Build()
 
+			recorder := events.NewFakeRecorder(10)
 			testReconciler := &ClientProfileReplicationReconciler{
-				Client: testFakeClient,
-				Scheme: testScheme,
+				Client:   testFakeClient,
+				Scheme:   testScheme,
+				Recorder: recorder,
 			}
 
 			// Reconcile both
@@ -238,6 +268,316 @@ var _ = Describe("ClientProfileReplication Controller with Fake Client", func()
 			Expect(testFakeClient.Get(ctx, types.NamespacedName{Name: cpr2.Name, Namespace: cpr2.Namespace}, updated2)).To(Succeed())
 			Expect(updated2.Status.Phase).To(Equal(csiv1.ClientProfileReplicationPhaseRejected))
 			Expect(updated2.Status.Message).To(ContainSubstring(cpr1.Name))
+			Expect(meta.FindStatusCondition(updated2.Status.Conditions, csiv1.ClientProfileReplicationReadyCondition)).
+				To(HaveField("Reason", csiv1.ClientProfileReplicationConflictReason))
+
+			By("Recording an event only when the CR becomes rejected")
+			Expect(recorder.Events).To(Receive(And(
+				HavePrefix(corev1.EventTypeWarning+" "+replicationRejectedReason),
+				ContainSubstring(cpr1.Name),
+			)))
+			_, err = testReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: cpr2.Name, Namespace: cpr2.Namespace},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive())
+		})
+	})
+
+	Context("When ClientProfileReplication CRs replicate to different remote profiles", func() {
+		It("should mark all of them as Ready", func() {
+			cprs := []*csiv1.ClientProfileReplication{}
+			for i, remote := range []string{"metro-profile", "async-profile"} {
+				cpr := &csiv1.ClientProfileReplication{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "test-cpr-" + remote,
+						Namespace:         "default",
+						CreationTimestamp: metav1.NewTime(time.Now().Add(time.Duration(i) * time.Minute)),
+					},
+					Spec: csiv1.ClientProfileReplicationSpec{
+						LocalClientProfile:          testClientProfile.Name,
+						RemoteClientProfile:         remote,
+						ExternalRemoteClientProfile: true,
+					},
+				}
+				Expect(fakeClient.Create(ctx, cpr)).To(Succeed())
+				cprs = append(cprs, cpr)
+			}
+
+			for _, cpr := range cprs {
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: cpr.Name, Namespace: cpr.Namespace},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				updated := &csiv1.ClientProfileReplication{}
+				Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cpr), updated)).To(Succeed())
+				Expect(updated.Status.Phase).To(Equal(csiv1.ClientProfileReplicationPhaseReady))
+				Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, csiv1.ClientProfileReplicationReadyCondition)).
+					To(BeTrue())
+			}
+		})
+	})
+
+	Context("When a CephFS ClientProfileReplication is newer than an RBD one", func() {
+		It("should reject the CephFS ClientProfileReplication", func() {
+			rbdCpr := &csiv1.ClientProfileReplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-cpr-rbd",
+					Namespace:         "default",
+					CreationTimestamp: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+				},
+				Spec: csiv1.ClientProfileReplicationSpec{
+					LocalClientProfile:  testClientProfile.Name,
+					RemoteClientProfile: "remote-profile",
+					RBD: &csiv1.RBDReplicationSpec{
+						PoolMapping: []csiv1.PoolMappingSpec{{Name: "rbd", RemoteID: "5"}},
+					},
+				},
+			}
+			cephFsCpr := &csiv1.ClientProfileReplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-cpr-cephfs",
+					Namespace:         "default",
+					CreationTimestamp: metav1.Now(),
+				},
+				Spec: csiv1.ClientProfileReplicationSpec{
+					LocalClientProfile:  testClientProfile.Name,
+					RemoteClientProfile: "remote-profile",
+					CephFS: &csiv1.CephFSReplicationSpec{
+						FilesystemMapping: []csiv1.FilesystemMappingSpec{{Name: "myfs", RemoteID: "2"}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, rbdCpr)).To(Succeed())
+			Expect(fakeClient.Create(ctx, cephFsCpr)).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: cephFsCpr.Name, Namespace: cephFsCpr.Namespace},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &csiv1.ClientProfileReplication{}
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cephFsCpr), updated)).To(Succeed())
+			Expect(updated.Status.Phase).To(Equal(csiv1.ClientProfileReplicationPhaseRejected))
+			Expect(updated.Status.Message).To(ContainSubstring(rbdCpr.Name))
+		})
+	})
+
+	Context("When the ClientProfileReplication is invalid", func() {
+		DescribeTable("should reject it with a specific reason",
+			func(spec csiv1.ClientProfileReplicationSpec, reason string, message string) {
+				cpr := &csiv1.ClientProfileReplication{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cpr-invalid", Namespace: "default"},
+					Spec:       spec,
+				}
+				cpr.Spec.LocalClientProfile = testClientProfile.Name
+				Expect(fakeClient.Create(ctx, cpr)).To(Succeed())
+
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: cpr.Name, Namespace: cpr.Namespace},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				updated := &csiv1.ClientProfileReplication{}
+				Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cpr), updated)).To(Succeed())
+				Expect(updated.Status.Phase).To(Equal(csiv1.ClientProfileReplicationPhaseRejected))
+				Expect(updated.Status.Message).To(ContainSubstring(message))
+				Expect(meta.FindStatusCondition(updated.Status.Conditions, csiv1.ClientProfileReplicationReadyCondition)).
+					To(And(HaveField("Status", metav1.ConditionFalse), HaveField("Reason", reason)))
+			},
+			Entry("remote ClientProfile not found",
+				csiv1.ClientProfileReplicationSpec{RemoteClientProfile: "missing-profile"},
+				csiv1.ClientProfileReplicationRemoteClientProfileNotFoundReason,
+				"remote ClientProfile 'missing-profile' not found",
+			),
+			Entry("replicated to itself",
+				csiv1.ClientProfileReplicationSpec{RemoteClientProfile: "test-client-profile"},
+				csiv1.ClientProfileReplicationCycleReason,
+				"replicated to itself",
+			),
+			Entry("duplicate pool name",
+				csiv1.ClientProfileReplicationSpec{
+					RemoteClientProfile: "remote-profile",
+					RBD: &csiv1.RBDReplicationSpec{
+						PoolMapping: []csiv1.PoolMappingSpec{{Name: "rbd", RemoteID: "5"}, {Name: "rbd", RemoteID: "6"}},
+					},
+				},
+				csiv1.ClientProfileReplicationDuplicatePoolNameReason,
+				"pool 'rbd' is mapped more than once",
+			),
+			Entry("invalid pool remote ID",
+				csiv1.ClientProfileReplicationSpec{
+					RemoteClientProfile: "remote-profile",
+					RBD: &csiv1.RBDReplicationSpec{
+						PoolMapping: []csiv1.PoolMappingSpec{{Name: "rbd", RemoteID: "-5"}},
+					},
+				},
+				csiv1.ClientProfileReplicationInvalidRemoteIDReason,
+				"remote ID '-5' of pool 'rbd'",
+			),
+			Entry("invalid filesystem remote ID",
+				csiv1.ClientProfileReplicationSpec{
+					RemoteClientProfile: "remote-profile",
+					CephFS: &csiv1.CephFSReplicationSpec{
+						FilesystemMapping: []csiv1.FilesystemMappingSpec{{Name: "myfs", RemoteID: "fs"}},
+					},
+				},
+				csiv1.ClientProfileReplicationInvalidRemoteIDReason,
+				"remote ID 'fs' of filesystem 'myfs'",
+			),
+		)
+
+		It("should let a newer valid ClientProfileReplication win over an older invalid one", func() {
+			invalid := &csiv1.ClientProfileReplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-cpr-invalid",
+					Namespace:         "default",
+					CreationTimestamp: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+				},
+				Spec: csiv1.ClientProfileReplicationSpec{
+					LocalClientProfile:  testClientProfile.Name,
+					RemoteClientProfile: testRemoteProfile.Name,
+					RBD: &csiv1.RBDReplicationSpec{
+						PoolMapping: []csiv1.PoolMappingSpec{{Name: "rbd", RemoteID: "five"}},
+					},
+				},
+			}
+			valid := &csiv1.ClientProfileReplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-cpr-valid",
+					Namespace:         "default",
+					CreationTimestamp: metav1.Now(),
+				},
+				Spec: csiv1.ClientProfileReplicationSpec{
+					LocalClientProfile:  testClientProfile.Name,
+					RemoteClientProfile: testRemoteProfile.Name,
+				},
+			}
+			Expect(fakeClient.Create(ctx, invalid)).To(Succeed())
+			Expect(fakeClient.Create(ctx, valid)).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: valid.Name, Namespace: valid.Namespace},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &csiv1.ClientProfileReplication{}
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(valid), updated)).To(Succeed())
+			Expect(updated.Status.Phase).To(Equal(csiv1.ClientProfileReplicationPhaseReady))
+		})
+
+		It("should let a newer ClientProfileReplication win over an older one with a missing remote ClientProfile", func() {
+			missingRemote := &csiv1.ClientProfileReplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-cpr-missing-remote",
+					Namespace:         "default",
+					CreationTimestamp: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+				},
+				Spec: csiv1.ClientProfileReplicationSpec{
+					LocalClientProfile:  testClientProfile.Name,
+					RemoteClientProfile: "external-profile",
+				},
+			}
+			external := &csiv1.ClientProfileReplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-cpr-external",
+					Namespace:         "default",
+					CreationTimestamp: metav1.Now(),
+				},
+				Spec: csiv1.ClientProfileReplicationSpec{
+					LocalClientProfile:          testClientProfile.Name,
+					RemoteClientProfile:         "external-profile",
+					ExternalRemoteClientProfile: true,
+				},
+			}
+			Expect(fakeClient.Create(ctx, missingRemote)).To(Succeed())
+			Expect(fakeClient.Create(ctx, external)).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: external.Name, Namespace: external.Namespace},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &csiv1.ClientProfileReplication{}
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(external), updated)).To(Succeed())
+			Expect(updated.Status.Phase).To(Equal(csiv1.ClientProfileReplicationPhaseReady))
+		})
+	})
+
+	Context("When ClientProfileReplications form a cycle", func() {
+		It("should reject the newest ClientProfileReplication of the cycle", func() {
+			for _, name := range []string{"profile-b", "profile-c"} {
+				Expect(fakeClient.Create(ctx, &csiv1.ClientProfile{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				})).To(Succeed())
+			}
+			cprs := []*csiv1.ClientProfileReplication{}
+			for i, pair := range [][2]string{
+				{testClientProfile.Name, "profile-b"},
+				{"profile-b", testClientProfile.Name},
+				{"profile-b", "profile-c"},
+				{"profile-c", testClientProfile.Name},
+			} {
+				cpr := &csiv1.ClientProfileReplication{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              fmt.Sprintf("test-cpr-%s-to-%s", pair[0], pair[1]),
+						Namespace:         "default",
+						CreationTimestamp: metav1.NewTime(time.Now().Add(time.Duration(i-10) * time.Minute)),
+					},
+					Spec: csiv1.ClientProfileReplicationSpec{
+						LocalClientProfile:  pair[0],
+						RemoteClientProfile: pair[1],
+					},
+				}
+				Expect(fakeClient.Create(ctx, cpr)).To(Succeed())
+				cprs = append(cprs, cpr)
+			}
+
+			reconcileAll := func() []string {
+				phases := make([]string, len(cprs))
+				// The newest CR is reconciled first, while the older CRs are still pending
+				for i := len(cprs) - 1; i >= 0; i-- {
+					_, err := reconciler.Reconcile(ctx, reconcile.Request{
+						NamespacedName: types.NamespacedName{Name: cprs[i].Name, Namespace: cprs[i].Namespace},
+					})
+					if errors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(cprs[i]), &csiv1.ClientProfileReplication{})) {
+						continue
+					}
+					Expect(err).NotTo(HaveOccurred())
+
+					updated := &csiv1.ClientProfileReplication{}
+					Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cprs[i]), updated)).To(Succeed())
+					phases[i] = updated.Status.Phase
+					if updated.Status.Phase == csiv1.ClientProfileReplicationPhaseRejected {
+						Expect(meta.FindStatusCondition(updated.Status.Conditions, csiv1.ClientProfileReplicationReadyCondition)).
+							To(HaveField("Reason", csiv1.ClientProfileReplicationCycleReason))
+						Expect(updated.Status.Message).To(ContainSubstring(
+							"profile-c -> test-client-profile -> profile-b -> profile-c",
+						))
+					}
+				}
+				return phases
+			}
+
+			By("Accepting the failover and failback pair and rejecting the replication closing the longer cycle")
+			Expect(reconcileAll()).To(Equal([]string{
+				csiv1.ClientProfileReplicationPhaseReady,
+				csiv1.ClientProfileReplicationPhaseReady,
+				csiv1.ClientProfileReplicationPhaseReady,
+				csiv1.ClientProfileReplicationPhaseRejected,
+			}))
+
+			By("Re-evaluating the rejected replication when a replication of the cycle is deleted")
+			requests := reconciler.connectedReplicationRequests(ctx, "default", "profile-b", "profile-c")
+			Expect(requests).To(HaveLen(len(cprs)))
+			Expect(fakeClient.Delete(ctx, cprs[2])).To(Succeed())
+			Expect(reconcileAll()).To(Equal([]string{
+				csiv1.ClientProfileReplicationPhaseReady,
+				csiv1.ClientProfileReplicationPhaseReady,
+				"",
+				csiv1.ClientProfileReplicationPhaseReady,
+			}))
 		})
 	})
 })
@@ -0,0 +1,322 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	csiv1 "github.com/ceph/ceph-csi-operator/api/v1"
+	"github.com/ceph/ceph-csi-operator/internal/utils"
+)
+
+// +kubebuilder:rbac:groups=csi.ceph.io,resources=clientprofilereplicationpairs,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=csi.ceph.io,resources=clientprofilereplicationpairs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=csi.ceph.io,resources=clientprofilereplicationpairs/finalizers,verbs=update
+
+const (
+	// Event reason reported when a ClientProfileReplication of a pair is created or updated
+	replicationPairSyncedReason = "ReplicationSynced"
+)
+
+// ClientProfileReplicationPairReconciler reconciles a ClientProfileReplicationPair object
+type ClientProfileReplicationPairReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder events.EventRecorder
+}
+
+// A local reconcile object tied to a single reconcile iteration
+type ClientProfileReplicationPairReconcile struct {
+	ClientProfileReplicationPairReconciler
+
+	ctx                          context.Context
+	log                          logr.Logger
+	clientProfileReplicationPair csiv1.ClientProfileReplicationPair
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClientProfileReplicationPairReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&csiv1.ClientProfileReplicationPair{}).
+		// Owned ClientProfileReplications trigger a reconcile to revert their changes and to report
+		// their phase
+		Owns(&csiv1.ClientProfileReplication{}).
+		Complete(r)
+}
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *ClientProfileReplicationPairReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := startReconcileSpan(ctx, "ClientProfileReplicationPair", req)
+	log := utils.LoggerWithTraceID(ctx, ctrllog.FromContext(ctx))
+	log.Info("Starting reconcile iteration for ClientProfileReplicationPair", "req", req)
+
+	reconcileHandler := ClientProfileReplicationPairReconcile{}
+	reconcileHandler.ClientProfileReplicationPairReconciler = *r
+	reconcileHandler.ctx = ctx
+	reconcileHandler.log = log
+	reconcileHandler.clientProfileReplicationPair.Name = req.Name
+	reconcileHandler.clientProfileReplicationPair.Namespace = req.Namespace
+
+	err := reconcileHandler.reconcile()
+	if err != nil {
+		log.Error(err, "ClientProfileReplicationPair reconciliation failed")
+	} else {
+		log.Info("ClientProfileReplicationPair reconciliation completed successfully")
+	}
+	utils.EndSpan(span, err)
+
+	return ctrl.Result{}, err
+}
+
+func (r *ClientProfileReplicationPairReconcile) reconcile() error {
+	pair := &r.clientProfileReplicationPair
+	if err := r.Get(r.ctx, client.ObjectKeyFromObject(pair), pair); err != nil {
+		if k8serrors.IsNotFound(err) {
+			r.log.Info("ClientProfileReplicationPair not found, ignoring")
+			return nil
+		}
+		r.log.Error(err, "failed to get ClientProfileReplicationPair")
+		return err
+	}
+	if !pair.DeletionTimestamp.IsZero() {
+		// The generated ClientProfileReplications are garbage collected with their owner
+		return nil
+	}
+
+	r.initConditions()
+	reconcileErr := r.reconcileReplications()
+	pair.Status.ObservedGeneration = pair.Generation
+
+	statusErr := r.Status().Update(r.ctx, pair)
+	if statusErr != nil {
+		r.log.Error(statusErr, "Failed to update ClientProfileReplicationPair status.")
+	}
+	if reconcileErr != nil {
+		return reconcileErr
+	} else if statusErr != nil {
+		return statusErr
+	}
+	return nil
+}
+
+// reconcileReplications generates the ClientProfileReplications of both directions of the pair, removes
+// the ones generated for a previous spec and reports their phase
+func (r *ClientProfileReplicationPairReconcile) reconcileReplications() error {
+	pair := &r.clientProfileReplicationPair
+	desired := []*csiv1.ClientProfileReplication{
+		composePairReplication(pair, false),
+		composePairReplication(pair, true),
+	}
+	pair.Status.PrimaryToSecondary = desired[0].Name
+	pair.Status.SecondaryToPrimary = desired[1].Name
+
+	// Delete the ClientProfileReplications generated for other ClientProfiles
+	cprList := csiv1.ClientProfileReplicationList{}
+	if err := r.List(r.ctx, &cprList, client.InNamespace(pair.Namespace)); err != nil {
+		r.log.Error(err, "Failed listing ClientProfileReplication CRs in namespace", "namespace", pair.Namespace)
+		r.setReadyCondition(metav1.ConditionFalse, csiv1.ClientProfileReplicationPairReconcileFailedReason, err.Error())
+		return err
+	}
+	for i := range cprList.Items {
+		cpr := &cprList.Items[i]
+		if !metav1.IsControlledBy(cpr, pair) ||
+			slices.ContainsFunc(desired, func(d *csiv1.ClientProfileReplication) bool { return d.Name == cpr.Name }) {
+			continue
+		}
+		if err := r.Delete(r.ctx, cpr); client.IgnoreNotFound(err) != nil {
+			r.log.Error(err, "Failed to delete stale ClientProfileReplication", "name", cpr.Name)
+			r.setReadyCondition(metav1.ConditionFalse, csiv1.ClientProfileReplicationPairReconcileFailedReason, err.Error())
+			return err
+		}
+		r.log.Info("Deleted stale ClientProfileReplication", "name", cpr.Name)
+	}
+
+	for _, desiredCpr := range desired {
+		cpr := &csiv1.ClientProfileReplication{}
+		cpr.Name = desiredCpr.Name
+		cpr.Namespace = desiredCpr.Namespace
+		nameConflict := false
+		opResult, err := ctrlutil.CreateOrUpdate(r.ctx, r.Client, cpr, func() error {
+			// Never adopt a ClientProfileReplication created by someone else
+			if cpr.ResourceVersion != "" && !metav1.IsControlledBy(cpr, pair) {
+				nameConflict = true
+				return fmt.Errorf("ClientProfileReplication %s exists and is not owned by the pair", cpr.Name)
+			}
+			if err := ctrlutil.SetControllerReference(pair, cpr, r.Scheme); err != nil {
+				return err
+			}
+			cpr.Spec = desiredCpr.Spec
+			return nil
+		})
+		if err != nil {
+			r.log.Error(err, "Failed to reconcile ClientProfileReplication of the pair", "name", cpr.Name)
+			r.setReadyCondition(
+				metav1.ConditionFalse,
+				utils.If(
+					nameConflict,
+					csiv1.ClientProfileReplicationPairNameConflictReason,
+					csiv1.ClientProfileReplicationPairReconcileFailedReason,
+				),
+				err.Error(),
+			)
+			return err
+		}
+		if opResult != ctrlutil.OperationResultNone {
+			r.recordEvent(
+				corev1.EventTypeNormal,
+				replicationPairSyncedReason,
+				"Update",
+				"ClientProfileReplication %s %s",
+				cpr.Name,
+				opResult,
+			)
+		}
+		desiredCpr.Status = cpr.Status
+		desiredCpr.Generation = cpr.Generation
+	}
+
+	// The pair is Ready once the ClientProfileReplication controller accepted both directions
+	for _, cpr := range desired {
+		if cpr.Status.Phase == csiv1.ClientProfileReplicationPhaseRejected {
+			r.setReadyCondition(
+				metav1.ConditionFalse,
+				csiv1.ClientProfileReplicationPairReplicationRejectedReason,
+				fmt.Sprintf("ClientProfileReplication %s is rejected: %s", cpr.Name, cpr.Status.Message),
+			)
+			return nil
+		}
+	}
+	for _, cpr := range desired {
+		if cpr.Status.Phase != csiv1.ClientProfileReplicationPhaseReady || cpr.Status.ObservedGeneration != cpr.Generation {
+			r.setReadyCondition(
+				metav1.ConditionUnknown,
+				csiv1.ClientProfileReplicationPairReplicationsPendingReason,
+				fmt.Sprintf("ClientProfileReplication %s is not validated yet", cpr.Name),
+			)
+			return nil
+		}
+	}
+	r.setReadyCondition(
+		metav1.ConditionTrue,
+		csiv1.ClientProfileReplicationPairReplicationsReadyReason,
+		fmt.Sprintf("ClientProfileReplications %s and %s are Ready", desired[0].Name, desired[1].Name),
+	)
+	return nil
+}
+
+// composePairReplication composes the ClientProfileReplication of a direction of the pair, the mappings
+// of the secondary to primary direction are the inverted mappings of the primary to secondary one
+func composePairReplication(
+	pair *csiv1.ClientProfileReplicationPair,
+	secondaryToPrimary bool,
+) *csiv1.ClientProfileReplication {
+	localProfile := utils.If(secondaryToPrimary, pair.Spec.SecondaryClientProfile, pair.Spec.PrimaryClientProfile)
+	remoteProfile := utils.If(secondaryToPrimary, pair.Spec.PrimaryClientProfile, pair.Spec.SecondaryClientProfile)
+
+	cpr := &csiv1.ClientProfileReplication{}
+	cpr.Name = fmt.Sprintf("%s-%s-to-%s", pair.Name, localProfile, remoteProfile)
+	cpr.Namespace = pair.Namespace
+	cpr.Spec.LocalClientProfile = localProfile
+	cpr.Spec.RemoteClientProfile = remoteProfile
+
+	if rbd := pair.Spec.RBD; rbd != nil && len(rbd.Pools) > 0 {
+		cpr.Spec.RBD = &csiv1.RBDReplicationSpec{}
+		for _, pool := range rbd.Pools {
+			cpr.Spec.RBD.PoolMapping = append(cpr.Spec.RBD.PoolMapping, csiv1.PoolMappingSpec{
+				Name:     pool.Name,
+				RemoteID: utils.If(secondaryToPrimary, pool.PrimaryID, pool.SecondaryID),
+			})
+		}
+	}
+
+	if cephFs := pair.Spec.CephFS; cephFs != nil && (len(cephFs.Filesystems) > 0 || len(cephFs.SubvolumeGroups) > 0) {
+		cpr.Spec.CephFS = &csiv1.CephFSReplicationSpec{}
+		for _, fs := range cephFs.Filesystems {
+			fsMapping := csiv1.FilesystemMappingSpec{
+				Name:       fs.Name,
+				RemoteName: fs.SecondaryName,
+				RemoteID:   fs.SecondaryID,
+			}
+			if secondaryToPrimary {
+				fsMapping = csiv1.FilesystemMappingSpec{
+					Name:       utils.If(fs.SecondaryName != "", fs.SecondaryName, fs.Name),
+					RemoteName: fs.Name,
+					RemoteID:   fs.PrimaryID,
+				}
+			}
+			cpr.Spec.CephFS.FilesystemMapping = append(cpr.Spec.CephFS.FilesystemMapping, fsMapping)
+		}
+		for _, group := range cephFs.SubvolumeGroups {
+			cpr.Spec.CephFS.SubvolumeGroupMapping = append(
+				cpr.Spec.CephFS.SubvolumeGroupMapping,
+				csiv1.SubvolumeGroupMappingSpec{
+					Name:       utils.If(secondaryToPrimary, group.SecondaryName, group.Name),
+					RemoteName: utils.If(secondaryToPrimary, group.Name, group.SecondaryName),
+				},
+			)
+		}
+	}
+
+	return cpr
+}
+
+// recordEvent records an event on the client profile replication pair, if an event recorder is available
+func (r *ClientProfileReplicationPairReconcile) recordEvent(eventType, reason, action, note string, args ...any) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(&r.clientProfileReplicationPair, nil, eventType, reason, action, note, args...)
+	}
+}
+
+// initConditions adds the conditions that were never evaluated as Unknown, so that all the
+// conditions are reported from the first reconcile on
+func (r *ClientProfileReplicationPairReconcile) initConditions() {
+	conditions := r.clientProfileReplicationPair.Status.Conditions
+	if meta.FindStatusCondition(conditions, csiv1.ClientProfileReplicationPairReadyCondition) == nil {
+		r.setReadyCondition(metav1.ConditionUnknown, csiv1.ClientProfileReplicationPairReconcilingReason, "")
+	}
+}
+
+// setReadyCondition sets the Ready condition of the ClientProfileReplicationPair, observed on its
+// current generation
+func (r *ClientProfileReplicationPairReconcile) setReadyCondition(
+	status metav1.ConditionStatus,
+	reason string,
+	message string,
+) {
+	meta.SetStatusCondition(&r.clientProfileReplicationPair.Status.Conditions, metav1.Condition{
+		Type:               csiv1.ClientProfileReplicationPairReadyCondition,
+		Status:             status,
+		ObservedGeneration: r.clientProfileReplicationPair.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	csiv1 "github.com/ceph/ceph-csi-operator/api/v1"
+)
+
+var _ = Describe("ClientProfileReplicationPair Controller with Fake Client", func() {
+	var (
+		ctx                   context.Context
+		fakeClient            client.Client
+		reconciler            *ClientProfileReplicationPairReconciler
+		replicationReconciler *ClientProfileReplicationReconciler
+		recorder              *events.FakeRecorder
+		testScheme            *runtime.Scheme
+		testPair              *csiv1.ClientProfileReplicationPair
+		request               reconcile.Request
+	)
+
+	getPair := func() *csiv1.ClientProfileReplicationPair {
+		pair := &csiv1.ClientProfileReplicationPair{}
+		Expect(fakeClient.Get(ctx, request.NamespacedName, pair)).To(Succeed())
+		return pair
+	}
+
+	getReplication := func(name string) *csiv1.ClientProfileReplication {
+		cpr := &csiv1.ClientProfileReplication{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, cpr)).To(Succeed())
+		return cpr
+	}
+
+	setup := func(objects ...client.Object) {
+		for _, name := range []string{"primary", "secondary"} {
+			objects = append(objects, &csiv1.ClientProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			})
+		}
+		objects = append(objects, testPair)
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(testScheme).
+			WithObjects(objects...).
+			WithStatusSubresource(&csiv1.ClientProfileReplicationPair{}, &csiv1.ClientProfileReplication{}).
+			WithIndex(&csiv1.ClientProfileReplication{}, clientProfileIndexKey, func(obj client.Object) []string {
+				cpr := obj.(*csiv1.ClientProfileReplication)
+				if cpr.Spec.LocalClientProfile != "" {
+					return []string{cpr.Spec.LocalClientProfile}
+				}
+				return nil
+			}).
+			Build()
+
+		recorder = events.NewFakeRecorder(10)
+		reconciler = &ClientProfileReplicationPairReconciler{
+			Client:   fakeClient,
+			Scheme:   testScheme,
+			Recorder: recorder,
+		}
+		replicationReconciler = &ClientProfileReplicationReconciler{
+			Client: fakeClient,
+			Scheme: testScheme,
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		testScheme = runtime.NewScheme()
+		Expect(csiv1.AddToScheme(testScheme)).To(Succeed())
+		Expect(scheme.AddToScheme(testScheme)).To(Succeed())
+
+		testPair = &csiv1.ClientProfileReplicationPair{
+			ObjectMeta: metav1.ObjectMeta{Name: "dr", Namespace: "default", UID: "dr"},
+			Spec: csiv1.ClientProfileReplicationPairSpec{
+				PrimaryClientProfile:   "primary",
+				SecondaryClientProfile: "secondary",
+				RBD: &csiv1.RBDReplicationPairSpec{
+					Pools: []csiv1.ReplicationPairPoolSpec{
+						{Name: "rbd", PrimaryID: "1", SecondaryID: "5"},
+						{Name: "replicapool", PrimaryID: "2", SecondaryID: "6"},
+					},
+				},
+				CephFS: &csiv1.CephFSReplicationPairSpec{
+					Filesystems: []csiv1.ReplicationPairFilesystemSpec{
+						{Name: "myfs", SecondaryName: "drfs", PrimaryID: "1", SecondaryID: "3"},
+					},
+					SubvolumeGroups: []csiv1.ReplicationPairSubvolumeGroupSpec{
+						{Name: "csi", SecondaryName: "csi-dr"},
+					},
+				},
+			},
+		}
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "dr", Namespace: "default"}}
+	})
+
+	It("should generate both directions with inverted mappings and become Ready once they are accepted", func() {
+		setup()
+
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		pair := getPair()
+		Expect(pair.Status.PrimaryToSecondary).To(Equal("dr-primary-to-secondary"))
+		Expect(pair.Status.SecondaryToPrimary).To(Equal("dr-secondary-to-primary"))
+		Expect(meta.FindStatusCondition(pair.Status.Conditions, csiv1.ClientProfileReplicationPairReadyCondition)).
+			To(HaveField("Reason", csiv1.ClientProfileReplicationPairReplicationsPendingReason))
+		Expect(recorder.Events).To(Receive(HavePrefix(corev1.EventTypeNormal + " " + replicationPairSyncedReason)))
+
+		forward := getReplication(pair.Status.PrimaryToSecondary)
+		Expect(metav1.IsControlledBy(forward, pair)).To(BeTrue())
+		Expect(forward.Spec).To(Equal(csiv1.ClientProfileReplicationSpec{
+			LocalClientProfile:  "primary",
+			RemoteClientProfile: "secondary",
+			RBD: &csiv1.RBDReplicationSpec{
+				PoolMapping: []csiv1.PoolMappingSpec{
+					{Name: "rbd", RemoteID: "5"},
+					{Name: "replicapool", RemoteID: "6"},
+				},
+			},
+			CephFS: &csiv1.CephFSReplicationSpec{
+				FilesystemMapping: []csiv1.FilesystemMappingSpec{
+					{Name: "myfs", RemoteName: "drfs", RemoteID: "3"},
+				},
+				SubvolumeGroupMapping: []csiv1.SubvolumeGroupMappingSpec{
+					{Name: "csi", RemoteName: "csi-dr"},
+				},
+			},
+		}))
+
+		reverse := getReplication(pair.Status.SecondaryToPrimary)
+		Expect(metav1.IsControlledBy(reverse, pair)).To(BeTrue())
+		Expect(reverse.Spec).To(Equal(csiv1.ClientProfileReplicationSpec{
+			LocalClientProfile:  "secondary",
+			RemoteClientProfile: "primary",
+			RBD: &csiv1.RBDReplicationSpec{
+				PoolMapping: []csiv1.PoolMappingSpec{
+					{Name: "rbd", RemoteID: "1"},
+					{Name: "replicapool", RemoteID: "2"},
+				},
+			},
+			CephFS: &csiv1.CephFSReplicationSpec{
+				FilesystemMapping: []csiv1.FilesystemMappingSpec{
+					{Name: "drfs", RemoteName: "myfs", RemoteID: "1"},
+				},
+				SubvolumeGroupMapping: []csiv1.SubvolumeGroupMappingSpec{
+					{Name: "csi-dr", RemoteName: "csi"},
+				},
+			},
+		}))
+
+		for _, name := range []string{forward.Name, reverse.Name} {
+			_, err := replicationReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: name, Namespace: "default"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(meta.IsStatusConditionTrue(getPair().Status.Conditions, csiv1.ClientProfileReplicationPairReadyCondition)).
+			To(BeTrue())
+	})
+
+	It("should revert changes to the generated ClientProfileReplications", func() {
+		setup()
+
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		reverse := getReplication("dr-secondary-to-primary")
+		reverse.Spec.RBD.PoolMapping[0].RemoteID = "9"
+		Expect(fakeClient.Update(ctx, reverse)).To(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getReplication(reverse.Name).Spec.RBD.PoolMapping[0].RemoteID).To(Equal("1"))
+	})
+
+	It("should delete the ClientProfileReplications generated for previous ClientProfiles", func() {
+		setup(&csiv1.ClientProfile{ObjectMeta: metav1.ObjectMeta{Name: "tertiary", Namespace: "default"}})
+
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		pair := getPair()
+		pair.Spec.SecondaryClientProfile = "tertiary"
+		Expect(fakeClient.Update(ctx, pair)).To(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		cprList := &csiv1.ClientProfileReplicationList{}
+		Expect(fakeClient.List(ctx, cprList)).To(Succeed())
+		Expect(cprList.Items).To(ConsistOf(
+			HaveField("Name", "dr-primary-to-tertiary"),
+			HaveField("Name", "dr-tertiary-to-primary"),
+		))
+	})
+
+	It("should not adopt a ClientProfileReplication it does not own", func() {
+		existing := &csiv1.ClientProfileReplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "dr-primary-to-secondary", Namespace: "default"},
+			Spec: csiv1.ClientProfileReplicationSpec{
+				LocalClientProfile:  "primary",
+				RemoteClientProfile: "secondary",
+			},
+		}
+		setup(existing)
+
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).To(HaveOccurred())
+
+		Expect(getReplication(existing.Name).Spec.RBD).To(BeNil())
+		Expect(meta.FindStatusCondition(getPair().Status.Conditions, csiv1.ClientProfileReplicationPairReadyCondition)).To(And(
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", csiv1.ClientProfileReplicationPairNameConflictReason),
+		))
+	})
+})
@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	csiv1 "github.com/ceph/ceph-csi-operator/api/v1"
+	"github.com/ceph/ceph-csi-operator/internal/utils"
+)
+
+// csiAddonsConfig holds the effective CSI-Addons deployment settings of a driver
+type csiAddonsConfig struct {
+	// Whether any of the CSI-Addons sidecars is deployed
+	enabled bool
+	// Whether the controller plugin and node plugin sidecars are deployed
+	controller bool
+	node       bool
+	// Whether the node plugin sidecar passes the staging paths of volumes to the node plugin, for the
+	// node side space reclamation and key rotation operations
+	stagingPath bool
+	// Whether the node plugin sidecar reports the condition of mounted volumes
+	volumeCondition bool
+
+	controllerPort      corev1.ContainerPort
+	nodePort            corev1.ContainerPort
+	controllerResources *corev1.ResourceRequirements
+	nodeResources       *corev1.ResourceRequirements
+	tls                 *csiv1.CsiAddonsTLSSpec
+	// The secret holding the serving certificate, either user provided or issued for the driver
+	tlsSecretName string
+}
+
+// csiAddonsConfig resolves the CSI-Addons settings of the driver from the csiAddons block, falling
+// back to the deprecated deployCsiAddons flag when the block is not set
+func (r *driverReconcile) csiAddonsConfig() csiAddonsConfig {
+	spec := r.driver.Spec.CsiAddons
+	enabled := ptr.Deref(r.driver.Spec.DeployCsiAddons, false)
+	if spec != nil {
+		enabled = ptr.Deref(spec.Enabled, true)
+	}
+	if !enabled || r.isNfsDriver() {
+		return csiAddonsConfig{}
+	}
+
+	spec = cmp.Or(spec, &csiv1.CsiAddonsSpec{})
+	features := cmp.Or(spec.Features, &csiv1.CsiAddonsFeaturesSpec{})
+	controllerSidecar := cmp.Or(spec.Controller, &csiv1.CsiAddonsSidecarSpec{})
+	nodeSidecar := cmp.Or(spec.Node, &csiv1.CsiAddonsSidecarSpec{})
+
+	volumeHealth := r.driver.Spec.VolumeHealth
+	volumeCondition := volumeHealth != nil && ptr.Deref(volumeHealth.NodeReporting, true)
+	if volumeHealth == nil {
+		// Honor the deprecated annotation until it is removed, invalid values are rejected on validation
+		volumeCondition, _ = strconv.ParseBool(r.driver.GetAnnotations()[driverCSIAddonsFeatureVolumeCondition])
+	}
+	volumeCondition = ptr.Deref(features.VolumeCondition, volumeCondition)
+	reclaimSpace := ptr.Deref(features.ReclaimSpace, true)
+	keyRotation := ptr.Deref(features.KeyRotation, true)
+
+	config := csiAddonsConfig{
+		// Space reclamation is served by both plugins, sparsify by the controller plugin
+		// and fstrim by the node plugin
+		controller: reclaimSpace ||
+			ptr.Deref(features.NetworkFence, true) ||
+			ptr.Deref(features.VolumeReplication, true) ||
+			ptr.Deref(features.VolumeGroupReplication, true),
+		node:                reclaimSpace || keyRotation || volumeCondition,
+		stagingPath:         reclaimSpace || keyRotation,
+		volumeCondition:     volumeCondition,
+		controllerPort:      r.controllerPluginCsiAddonsContainerPort(),
+		nodePort:            utils.NodePluginCsiAddonsContainerPort,
+		controllerResources: controllerSidecar.Resources,
+		nodeResources:       nodeSidecar.Resources,
+		tls:                 spec.TLS,
+	}
+	config.enabled = config.controller || config.node
+	if controllerSidecar.Port != nil {
+		config.controllerPort = corev1.ContainerPort{ContainerPort: *controllerSidecar.Port}
+	}
+	if nodeSidecar.Port != nil {
+		config.nodePort = corev1.ContainerPort{ContainerPort: *nodeSidecar.Port}
+	}
+	if spec.TLS != nil {
+		config.tlsSecretName = cmp.Or(spec.TLS.SecretName, r.certificateSecretName(csiAddonsCertificateComponent))
+	}
+	return config
+}
+
+// tlsContainerArgs returns the TLS related arguments of the CSI-Addons sidecars
+func (c csiAddonsConfig) tlsContainerArgs() []string {
+	if c.tls == nil {
+		return nil
+	}
+	return utils.DeleteZeroValues([]string{
+		utils.CsiAddonsTlsCertFileContainerArg,
+		utils.CsiAddonsTlsKeyFileContainerArg,
+		utils.If(c.tls.MinVersion != "", utils.TlsMinVersionContainerArg(string(c.tls.MinVersion)), ""),
+	})
+}
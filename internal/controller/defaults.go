@@ -18,6 +18,7 @@ package controller
 
 import (
 	"os"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -36,6 +37,9 @@ var imageDefaults = map[string]string{
 	"snapshot-metadata": "registry.k8s.io/sig-storage/csi-snapshot-metadata:v1.0.0",
 	"plugin":            "quay.io/cephcsi/cephcsi:v3.17.0",
 	"addons":            "quay.io/csiaddons/k8s-sidecar:v0.14.0",
+	"health-monitor":    "registry.k8s.io/sig-storage/csi-external-health-monitor-controller:v0.15.0",
+	"livenessprobe":     "registry.k8s.io/sig-storage/livenessprobe:v2.18.0",
+	"kube-rbac-proxy":   "quay.io/brancz/kube-rbac-proxy:v0.19.1",
 }
 
 const (
@@ -46,6 +50,9 @@ const (
 	defaultControllerPluginReplicas int32 = 2
 )
 
+// Interval between volume health checks of the external health monitor controller
+const defaultVolumeHealthMonitorInterval = time.Minute
+
 var defaultLeaderElection = csiv1.LeaderElectionSpec{
 	LeaseDuration: 137,
 	RenewDeadline: 107,
@@ -19,6 +19,8 @@ package controller
 import (
 	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -28,16 +30,28 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -59,11 +73,22 @@ import (
 //+kubebuilder:rbac:groups=csi.ceph.io,resources=operatorconfigs,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update
 //+kubebuilder:rbac:groups=storage.k8s.io,resources=csidrivers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=csi.ceph.io,resources=clientprofiles,verbs=get;list;watch
+//+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingadmissionpolicies;validatingadmissionpolicybindings,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=nodes,verbs=list;watch
 //+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=get
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;prometheusrules,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=cbt.storage.k8s.io,resources=snapshotmetadataservices,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots;volumesnapshotcontents,verbs=get;list
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;create;update;patch;delete
 
 type DriverType string
 
@@ -77,8 +102,26 @@ const (
 const (
 	// Annotation name for ownerref information
 	ownerRefAnnotationKey = "csi.ceph.io/ownerref"
-	// Annotation to enable CSI-Addons volume condition reporter
+	// Annotation to enable CSI-Addons volume condition reporter, deprecated in favor of volumeHealth
 	driverCSIAddonsFeatureVolumeCondition = "addons.csi.ceph.io/volume-condition"
+	// Label identifying the node pool of a node plugin daemonset
+	nodePoolLabelKey = "csi.ceph.io/node-pool"
+	// Label marking the CSI-Addons daemonset of a node pool
+	csiAddonsNodePoolLabelKey = "csi.ceph.io/csi-addons-node-pool"
+	// Upper limit on the number of node selector terms generated to keep node pools mutually exclusive
+	maxNodePoolSelectorTerms = 64
+	// CSIStorageCapacity objects are owned by the controller plugin deployment (Pod -> ReplicaSet -> Deployment)
+	capacityOwnerrefLevel = 2
+	// Event reason reported when the CSIDriver is recreated to update immutable fields
+	csiDriverRecreatedReason = "CSIDriverRecreated"
+	// Event reason reported when a pod template update rolls out the pods of a daemonset
+	daemonSetRolledReason = "DaemonSetRolled"
+	// Event reason reported when an image set config map cannot be loaded
+	invalidImageSetReason = "InvalidImageSet"
+	// Event reason reported when a resource modified by someone else is reverted to its desired state
+	driftCorrectedReason = "DriftCorrected"
+	// Event reason reported when a driver relies on a deprecated annotation
+	deprecatedAnnotationReason = "DeprecatedAnnotation"
 
 	logRotateCmd = `while true; do logrotate --verbose /logrotate-config/csi; sleep 15m; done`
 )
@@ -95,7 +138,12 @@ var nameRegExp, _ = regexp.Compile(fmt.Sprintf(
 // DriverReconciler reconciles a Driver object
 type DriverReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder events.EventRecorder
+
+	// The API server version, used for platform detection. Discovered when the
+	// controller is set up if not provided
+	ServerVersion *version.Info
 }
 
 // A local reconcile object tied to a single reconcile iteration
@@ -107,10 +155,59 @@ type driverReconcile struct {
 	driver     csiv1.Driver
 	driverType DriverType
 	images     map[string]string
+	nodes      []corev1.Node
+	platform   csiv1.PlatformType
+
+	// The SCC driver pods are required to run with, set only on OpenShift
+	// when the operator SCC is installed
+	requiredSCC string
+	// Whether the platform runs without its cluster DNS
+	clusterDNSDisabled bool
+
+	// Guards the status details collected concurrently by sub reconcilers
+	statusLock               sync.Mutex
+	containerArgs            []csiv1.ContainerArgsStatus
+	kubeletDirPathMismatches []csiv1.KubeletDirPathMismatchStatus
+	// Whether a plugin workload failed to reconcile before or after its patches were applied
+	pluginWorkloadFailed bool
+
+	// The serving certificates of the driver components, nil when they could not be reconciled
+	certificateStatuses []csiv1.CertificateStatus
+	// Hashes of the serving certificates, keyed by component
+	certificateHashes map[string]string
+
+	// Delay before the driver has to be reconciled again, e.g. to renew certificates
+	requeueAfter time.Duration
+}
+
+// workloadPatchError is returned when a user defined patch cannot be applied on top
+// of a generated workload
+type workloadPatchError struct {
+	workload string
+	index    int
+	err      error
+}
+
+func (e *workloadPatchError) Error() string {
+	return fmt.Sprintf("failed to apply patch #%d on %s: %v", e.index, e.workload, e.err)
+}
+
+func (e *workloadPatchError) Unwrap() error {
+	return e.err
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *DriverReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.ServerVersion == nil {
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+		if err != nil {
+			return err
+		}
+		if r.ServerVersion, err = discoveryClient.ServerVersion(); err != nil {
+			return err
+		}
+	}
+
 	// Define conditions for an OperatorConfig change that the require queuing of reconciliation
 	// Filter update events based on metadata.generation changes, will filter events
 	// for non-spec changes on most resource types.
@@ -154,6 +251,23 @@ func (r *DriverReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		},
 	)
 
+	// Enqueue a reconcile request for the drivers of the namespace of the object, used to trigger a
+	// reconcile of the drivers whose inline volumes are restricted by a changed client profile
+	enqueueNamespaceDrivers := handler.EnqueueRequestsFromMapFunc(
+		func(ctx context.Context, obj client.Object) []reconcile.Request {
+			driverList := csiv1.DriverList{}
+			if err := r.List(ctx, &driverList, client.InNamespace(obj.GetNamespace())); err != nil {
+				return []reconcile.Request{}
+			}
+
+			requests := make([]reconcile.Request, len(driverList.Items))
+			for i := range driverList.Items {
+				requests[i].NamespacedName = client.ObjectKeyFromObject(&driverList.Items[i])
+			}
+			return requests
+		},
+	)
+
 	// Enqueue a reconcile request based on an annotation marking a soft ownership
 	enqueueFromOwnerRefAnnotation := handler.EnqueueRequestsFromMapFunc(
 		func(_ context.Context, obj client.Object) []reconcile.Request {
@@ -191,6 +305,12 @@ func (r *DriverReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&networkingv1.NetworkPolicy{},
 			builder.WithPredicates(genChangedPredicate),
 		).
+		Owns(
+			&corev1.Secret{},
+			builder.WithPredicates(
+				utils.EventTypePredicate(false, false, true, false),
+			),
+		).
 		Owns(
 			&corev1.ConfigMap{},
 			builder.MatchEveryOwner,
@@ -204,10 +324,36 @@ func (r *DriverReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			enqueueAllDrivers,
 			builder.WithPredicates(driverDefaultsPredicate),
 		).
+		Watches(
+			&corev1.Node{},
+			enqueueAllDrivers,
+			builder.WithPredicates(nodeChangedPredicate),
+		).
+		Watches(
+			&csiv1.ClientProfile{},
+			enqueueNamespaceDrivers,
+			builder.WithPredicates(genChangedPredicate),
+		).
 		Watches(
 			&storagev1.CSIDriver{},
 			enqueueFromOwnerRefAnnotation,
 		).
+		Watches(
+			&admissionregistrationv1.ValidatingAdmissionPolicy{},
+			enqueueFromOwnerRefAnnotation,
+		).
+		Watches(
+			&admissionregistrationv1.ValidatingAdmissionPolicyBinding{},
+			enqueueFromOwnerRefAnnotation,
+		).
+		Watches(
+			&rbacv1.ClusterRole{},
+			enqueueFromOwnerRefAnnotation,
+		).
+		Watches(
+			&rbacv1.ClusterRoleBinding{},
+			enqueueFromOwnerRefAnnotation,
+		).
 		Complete(r)
 }
 
@@ -217,7 +363,8 @@ func (r *DriverReconciler) SetupWithManager(mgr ctrl.Manager) error {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.3/pkg/reconcile
 func (r *DriverReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := ctrllog.FromContext(ctx)
+	ctx, span := startReconcileSpan(ctx, "Driver", req)
+	log := utils.LoggerWithTraceID(ctx, ctrllog.FromContext(ctx))
 	log.Info("Starting reconcile iteration for Ceph CSI driver", "req", req)
 
 	reconcileHandler := driverReconcile{}
@@ -233,25 +380,56 @@ func (r *DriverReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	} else {
 		log.Info("CSI Driver reconciliation completed successfully")
 	}
-	return ctrl.Result{}, err
+	utils.EndSpan(span, err)
+	return ctrl.Result{RequeueAfter: reconcileHandler.requeueAfter}, err
 }
 
 func (r *driverReconcile) reconcile() error {
+	// The driver is being deleted, only its cluster scoped resources require a clean up. This is
+	// handled ahead of loading the desired state, which would keep an invalid driver from being deleted
+	driverKey := client.ObjectKeyFromObject(&r.driver)
+	if err := r.Get(r.ctx, driverKey, &r.driver); err != nil {
+		r.log.Error(err, "Unable to load driver.csi.ceph.io", "name", driverKey)
+		return err
+	}
+	if r.driver.DeletionTimestamp != nil {
+		forgetAppliedStates(driverKey)
+		return r.deleteSnapshotMetadataClusterResources(r.ctx)
+	}
+
 	// Load the driver desired state based on driver resource, operator config resource and default values.
 	if err := r.LoadAndValidateDesiredState(); err != nil {
 		return err
 	}
 
+	// Certificates are reconciled ahead of the workloads mounting them. Workloads are still
+	// reconciled on failure, their pods wait for the missing secrets.
+	var certificatesErr error
+	if certificatesErr = observeReconcileStep(r.ctx, "certificates", r.reconcileCertificates)(); certificatesErr != nil {
+		r.log.Error(certificatesErr, "Failed to reconcile driver certificates")
+	}
+
+	// The snapshot-metadata service advertises the CA of the certificates reconciled above
+	clusterResourcesErr := observeReconcileStep(
+		r.ctx,
+		"snapshot_metadata_cluster_resources",
+		r.reconcileSnapshotMetadataClusterResources,
+	)()
+
 	reconcilers := []func() error{
-		r.reconcileCsiConfigMap,
-		r.reconcileLogRotateConfigMap,
-		r.reconcileK8sCsiDriver,
-		r.reconcileControllerPluginDeployment,
-		r.reconcileControllerPluginNetworkPolicy,
-		r.reconcileNodePluginDaemonSet,
-		r.reconcileLivenessService,
-		r.reconcileNodePluginDaemonSetForCsiAddons,
-		r.reconcileNodePluginCsiAddonsNetworkPolicy,
+		observeReconcileStep(r.ctx, "csi_config_map", r.reconcileCsiConfigMap),
+		observeReconcileStep(r.ctx, "log_rotate_config_map", r.reconcileLogRotateConfigMap),
+		observeReconcileStep(r.ctx, "csi_driver", r.reconcileK8sCsiDriver),
+		observeReconcileStep(r.ctx, "inline_volumes_policy", r.reconcileInlineVolumesPolicy),
+		observeReconcileStep(r.ctx, "controller_plugin_deployment", r.reconcileControllerPluginDeployment),
+		observeReconcileStep(r.ctx, "controller_plugin_network_policy", r.reconcileControllerPluginNetworkPolicy),
+		observeReconcileStep(r.ctx, "node_plugin_daemonsets", r.reconcileNodePluginDaemonSets),
+		observeReconcileStep(r.ctx, "metrics_services", r.reconcileMetricsServices),
+		observeReconcileStep(r.ctx, "service_monitor", r.reconcileServiceMonitor),
+		observeReconcileStep(r.ctx, "prometheus_rule", r.reconcilePrometheusRule),
+		observeReconcileStep(r.ctx, "snapshot_metadata_service", r.reconcileSnapshotMetadataService),
+		observeReconcileStep(r.ctx, "csi_addons_node_plugin_daemonset", r.reconcileNodePluginDaemonSetsForCsiAddons),
+		observeReconcileStep(r.ctx, "csi_addons_network_policy", r.reconcileNodePluginCsiAddonsNetworkPolicy),
 	}
 
 	// Concurrently reconcile different aspects of the clusters actual state to meet
@@ -261,6 +439,20 @@ func (r *driverReconcile) reconcile() error {
 	// Check if any reconcilatin error where raised during the concurrent execution
 	// of the reconciliation steps.
 	errList := utils.ChannelToSlice(errChan)
+	if certificatesErr != nil {
+		errList = append(errList, certificatesErr)
+	}
+	if clusterResourcesErr != nil {
+		errList = append(errList, clusterResourcesErr)
+	}
+
+	// Report the outcome of the reconciliation steps on the driver status
+	if err := observeReconcileStep(r.ctx, "status", func(ctx context.Context) error {
+		return r.reconcileStatus(ctx, errList)
+	})(); err != nil {
+		errList = append(errList, err)
+	}
+
 	if err := errors.Join(errList...); err != nil {
 		return err
 	}
@@ -345,6 +537,7 @@ func (r *driverReconcile) LoadAndValidateDesiredState() error {
 					"name",
 					client.ObjectKeyFromObject(&imageSetCM),
 				)
+				r.recordInvalidImageSet(&imageSetCM, err)
 				return err
 			}
 			maps.Copy(r.images, imageSetCM.Data)
@@ -364,15 +557,128 @@ func (r *driverReconcile) LoadAndValidateDesiredState() error {
 				"name",
 				client.ObjectKeyFromObject(&imageSetCM),
 			)
+			r.recordInvalidImageSet(&imageSetCM, err)
 			return err
 		}
 		maps.Copy(r.images, imageSetCM.Data)
 	}
 
+	// The driver spec was merged with the operator config defaults, which are not covered by the
+	// driver CRD validation rules
+	if err := r.validateCsiDriverSpec(); err != nil {
+		r.log.Error(err, "Invalid driver spec")
+		return err
+	}
+
+	return r.detectPlatform()
+}
+
+// validateCsiDriverSpec validates the CSIDriver related fields of the driver spec against the driver type
+func (r *driverReconcile) validateCsiDriverSpec() error {
+	spec := &r.driver.Spec
+	rbdOrCephFs := r.isRbdDriver() || r.isCephFsDriver()
+	ephemeral := slices.Contains(spec.VolumeLifecycleModes, storagev1.VolumeLifecycleEphemeral)
+	csiAddons := r.csiAddonsConfig()
+
+	if ptr.Deref(spec.StorageCapacity, false) && !rbdOrCephFs {
+		return fmt.Errorf("storageCapacity is not supported by %s drivers", r.driverType)
+	}
+	if ephemeral && !rbdOrCephFs {
+		return fmt.Errorf("the Ephemeral volume lifecycle mode is not supported by %s drivers", r.driverType)
+	}
+	if ephemeral && !ptr.Deref(spec.PodInfoOnMount, true) {
+		return fmt.Errorf("the Ephemeral volume lifecycle mode requires podInfoOnMount")
+	}
+	for i := range spec.TokenRequests {
+		if slices.ContainsFunc(spec.TokenRequests[:i], func(tr storagev1.TokenRequest) bool {
+			return tr.Audience == spec.TokenRequests[i].Audience
+		}) {
+			return fmt.Errorf("duplicate token request audience %q", spec.TokenRequests[i].Audience)
+		}
+	}
+	if period := spec.NodeAllocatableUpdatePeriodSeconds; period != nil && *period < 10 {
+		return fmt.Errorf("nodeAllocatableUpdatePeriodSeconds must be at least 10, got %d", *period)
+	}
+	if volumeHealth := spec.VolumeHealth; volumeHealth != nil {
+		if !rbdOrCephFs {
+			return fmt.Errorf("volumeHealth is not supported by %s drivers", r.driverType)
+		}
+		if ptr.Deref(volumeHealth.NodeReporting, false) && !csiAddons.volumeCondition {
+			return fmt.Errorf("volumeHealth.nodeReporting requires the CSI-Addons volumeCondition feature")
+		}
+	}
+	if feature, ok := r.driver.GetAnnotations()[driverCSIAddonsFeatureVolumeCondition]; ok {
+		if _, err := strconv.ParseBool(feature); err != nil {
+			return fmt.Errorf("invalid %s annotation value %q: %w", driverCSIAddonsFeatureVolumeCondition, feature, err)
+		}
+		r.log.Info(
+			"Driver uses a deprecated annotation, set volumeHealth instead",
+			"annotation", driverCSIAddonsFeatureVolumeCondition,
+		)
+		r.recordEvent(
+			corev1.EventTypeWarning,
+			deprecatedAnnotationReason,
+			"Validate",
+			"The %s annotation is deprecated and ignored when volumeHealth is set, set volumeHealth instead",
+			driverCSIAddonsFeatureVolumeCondition,
+		)
+	}
+	if spec.Monitoring != nil && spec.Liveness == nil {
+		return fmt.Errorf("monitoring requires liveness to be set")
+	}
+	if spec.Liveness != nil && int(r.livenessHealthPort()) == spec.Liveness.MetricsPort {
+		return fmt.Errorf("liveness metricsPort and healthPort cannot both be %d", spec.Liveness.MetricsPort)
+	}
+	if spec.SnapshotMetadata != nil && !r.isRbdDriver() {
+		return fmt.Errorf("snapshotMetadata is not supported by %s drivers", r.driverType)
+	}
+	if duration, renewBefore := r.certificateDurations(); renewBefore >= duration {
+		return fmt.Errorf("certificates renewBefore (%s) must be shorter than the duration (%s)", renewBefore, duration)
+	}
+	if certificates := spec.Certificates; certificates != nil &&
+		certificates.Issuer == csiv1.CertManagerCertificateIssuer && certificates.CertManagerIssuerRef == nil {
+		return fmt.Errorf("certificates certManagerIssuerRef is required with the CertManager issuer")
+	}
+	if csiAddons.controller && csiAddons.node &&
+		csiAddons.controllerPort.ContainerPort == csiAddons.nodePort.ContainerPort {
+		return fmt.Errorf("the controller and node CSI-Addons sidecars cannot share port %d", csiAddons.nodePort.ContainerPort)
+	}
+	return nil
+}
+
+// detectPlatform detects the platform the driver is deployed on, and the platform
+// specific settings driver pods require
+func (r *driverReconcile) detectPlatform() error {
+	nodeList := &corev1.NodeList{}
+	if err := r.List(r.ctx, nodeList); err != nil {
+		r.log.Error(err, "Failed to list nodes for platform detection")
+		return err
+	}
+	r.nodes = nodeList.Items
+	r.platform = detectClusterPlatform(r.ServerVersion, r.nodes)
+	r.clusterDNSDisabled = clusterDNSDisabled(r.nodes, r.platform)
+	r.log.V(1).Info("Detected platform", "platform", r.platform, "clusterDNSDisabled", r.clusterDNSDisabled)
+
+	if r.platform == csiv1.OpenShiftPlatformType {
+		// Pin driver pods to the operator SCC, only if it was installed. Otherwise
+		// leave it to the platform to pick an SCC
+		scc := &unstructured.Unstructured{}
+		scc.SetGroupVersionKind(openShiftSCCGroupVersionKind)
+		scc.SetName(openShiftSCCName)
+		err := r.Get(r.ctx, client.ObjectKeyFromObject(scc), scc)
+		switch {
+		case err == nil:
+			r.requiredSCC = openShiftSCCName
+		case !k8serrors.IsNotFound(err) && !meta.IsNoMatchError(err):
+			r.log.Error(err, "Failed to query the existence of the SCC", "name", openShiftSCCName)
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (r *driverReconcile) reconcileLogRotateConfigMap() error {
+func (r *driverReconcile) reconcileLogRotateConfigMap(ctx context.Context) error {
 	logRotateConfigmap := &corev1.ConfigMap{}
 	logRotateConfigmap.Name = utils.LogRotateConfigMapName(r.driver.Name)
 	logRotateConfigmap.Namespace = r.driver.Namespace
@@ -382,7 +688,7 @@ func (r *driverReconcile) reconcileLogRotateConfigMap() error {
 
 	logRotationSpec := cmp.Or(r.driver.Spec.Log, &csiv1.LogSpec{}).Rotation
 	if logRotationSpec != nil {
-		opResult, err := ctrlutil.CreateOrUpdate(r.ctx, r.Client, logRotateConfigmap, func() error {
+		opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, logRotateConfigmap, func() error {
 			if _, err := utils.ToggleOwnerReference(true, logRotateConfigmap, &r.driver, r.Scheme); err != nil {
 				log.Error(err, "Failed adding an owner reference on the LogRotate config map")
 				return err
@@ -417,11 +723,11 @@ func (r *driverReconcile) reconcileLogRotateConfigMap() error {
 			return nil
 		})
 
-		logCreateOrUpdateResult(log, "LogRotateConfigMap", logRotateConfigmap, opResult, err)
+		r.logCreateOrUpdateResult(log, "LogRotateConfigMap", logRotateConfigmap, opResult, err)
 		return err
 	} else {
 		// Remove the logrotate configmap if logrotate setting is removed from the driver's spec
-		if err := r.Delete(r.ctx, logRotateConfigmap); client.IgnoreNotFound(err) != nil {
+		if err := r.Delete(ctx, logRotateConfigmap); client.IgnoreNotFound(err) != nil {
 			log.Error(err, "Unable to delete LogRotate configmap")
 			return err
 		}
@@ -429,7 +735,7 @@ func (r *driverReconcile) reconcileLogRotateConfigMap() error {
 	}
 }
 
-func (r *driverReconcile) reconcileCsiConfigMap() error {
+func (r *driverReconcile) reconcileCsiConfigMap(ctx context.Context) error {
 	csiConfigMap := &corev1.ConfigMap{}
 	csiConfigMap.Name = utils.CsiConfigVolume.Name
 	csiConfigMap.Namespace = r.driver.Namespace
@@ -437,7 +743,7 @@ func (r *driverReconcile) reconcileCsiConfigMap() error {
 	log := r.log.WithValues("csiConfigMap", csiConfigMap.Name)
 	log.Info("Reconciling CSI Config Map")
 
-	opResult, err := ctrlutil.CreateOrUpdate(r.ctx, r.Client, csiConfigMap, func() error {
+	opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, csiConfigMap, func() error {
 		if _, err := utils.ToggleOwnerReference(true, csiConfigMap, &r.driver, r.Scheme); err != nil {
 			log.Error(err, "Failed adding an owner referce on Ceph CSI config map")
 			return err
@@ -445,18 +751,21 @@ func (r *driverReconcile) reconcileCsiConfigMap() error {
 		return nil
 	})
 
-	logCreateOrUpdateResult(log, "CSI Config Map", csiConfigMap, opResult, err)
+	r.logCreateOrUpdateResult(log, "CSI Config Map", csiConfigMap, opResult, err)
 	return err
 }
 
-func (r *driverReconcile) reconcileK8sCsiDriver() error {
+func (r *driverReconcile) reconcileK8sCsiDriver(ctx context.Context) error {
 	csiDriver := &storagev1.CSIDriver{}
 	csiDriver.Name = r.driver.Name
 
 	log := r.log.WithValues("driverName", csiDriver.Name)
 	log.Info("Reconciling CSI Driver")
 
-	opResult, err := ctrlutil.CreateOrUpdate(r.ctx, r.Client, csiDriver, func() error {
+	currentSpec := storagev1.CSIDriverSpec{}
+	opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, csiDriver, func() error {
+		csiDriver.Spec.DeepCopyInto(&currentSpec)
+
 		ownerObjKey := client.ObjectKeyFromObject(&r.driver)
 		if bytes, err := json.Marshal(ownerObjKey); err == nil {
 			if utils.AddAnnotation(csiDriver, ownerRefAnnotationKey, string(bytes)) {
@@ -472,7 +781,10 @@ func (r *driverReconcile) reconcileK8sCsiDriver() error {
 			return err
 		}
 
-		csiDriver.Spec.PodInfoOnMount = ptr.To(true)
+		csiDriver.Spec.PodInfoOnMount = cmp.Or(
+			r.driver.Spec.PodInfoOnMount,
+			ptr.To(true),
+		)
 		csiDriver.Spec.AttachRequired = cmp.Or(
 			r.driver.Spec.AttachRequired,
 			ptr.To(true),
@@ -483,18 +795,31 @@ func (r *driverReconcile) reconcileK8sCsiDriver() error {
 				storagev1.FileFSGroupPolicy,
 			),
 		)
-		csiDriver.Spec.SELinuxMount = ptr.To(true)
+		csiDriver.Spec.SELinuxMount = cmp.Or(
+			r.driver.Spec.SeLinuxMount,
+			ptr.To(true),
+		)
+		csiDriver.Spec.TokenRequests = r.driver.Spec.TokenRequests
+		csiDriver.Spec.RequiresRepublish = ptr.To(ptr.Deref(r.driver.Spec.RequiresRepublish, false))
+		csiDriver.Spec.StorageCapacity = ptr.To(ptr.Deref(r.driver.Spec.StorageCapacity, false))
+		csiDriver.Spec.VolumeLifecycleModes = utils.If(
+			len(r.driver.Spec.VolumeLifecycleModes) > 0,
+			r.driver.Spec.VolumeLifecycleModes,
+			[]storagev1.VolumeLifecycleMode{storagev1.VolumeLifecyclePersistent},
+		)
+		csiDriver.Spec.NodeAllocatableUpdatePeriodSeconds = r.driver.Spec.NodeAllocatableUpdatePeriodSeconds
 
 		return nil
 	})
 
 	// We are expecting an Invalid operation error, on an existing CSIDriver, in the rear case
-	// where the new desired state require reconfiguration of an immutable field.
-	// For CSIDriver, ".spec.attachRequired" is an invalid field
-	if csiDriver.UID != "" && k8serrors.IsInvalid(err) {
-		r.log.Info("CSIDriver exists but cannot be updated, trying recreate instead")
+	// where the new desired state require reconfiguration of an immutable field. The set of
+	// immutable fields differs between Kubernetes versions, so any changed field is a candidate.
+	changedFields := csiDriverChangedFields(&currentSpec, &csiDriver.Spec)
+	if csiDriver.UID != "" && k8serrors.IsInvalid(err) && len(changedFields) > 0 {
+		r.log.Info("CSIDriver exists but cannot be updated, trying recreate instead", "changedFields", changedFields)
 
-		if err = r.Delete(r.ctx, csiDriver); err != nil {
+		if err = r.Delete(ctx, csiDriver); err != nil {
 			r.log.Error(err, "Failed deleting existing CSIDriver")
 			return err
 		}
@@ -507,21 +832,29 @@ func (r *driverReconcile) reconcileK8sCsiDriver() error {
 			},
 			Spec: csiDriver.Spec,
 		}
-		if err := r.Create(r.ctx, csiDriver); err != nil {
+		if err := r.Create(ctx, csiDriver); err != nil {
 			r.log.Error(err, "Failed recreating CSIDriver")
 			return err
 		}
 
 		log.Info("CSIDriver recreated successfully")
+		r.recordEvent(
+			corev1.EventTypeNormal,
+			csiDriverRecreatedReason,
+			"Recreate",
+			"CSIDriver %s recreated to update immutable fields: %s",
+			csiDriver.Name,
+			strings.Join(changedFields, ", "),
+		)
 		return nil
 
 	} else {
-		logCreateOrUpdateResult(log, "CSIDriver", csiDriver, opResult, err)
+		r.logCreateOrUpdateResult(log, "CSIDriver", csiDriver, opResult, err)
 		return err
 	}
 }
 
-func (r *driverReconcile) reconcileControllerPluginDeployment() error {
+func (r *driverReconcile) reconcileControllerPluginDeployment(ctx context.Context) error {
 	deploy := &appsv1.Deployment{}
 	deploy.Name = r.generateName("ctrlplugin")
 	deploy.Namespace = r.driver.Namespace
@@ -529,13 +862,13 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 	log := r.log.WithValues("deploymentName", deploy.Name)
 	log.Info("Reconciling controller plugin deployment")
 
-	opResult, err := ctrlutil.CreateOrUpdate(r.ctx, r.Client, deploy, func() error {
+	opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, deploy, func() error {
 		if err := ctrlutil.SetControllerReference(&r.driver, deploy, r.Scheme); err != nil {
 			log.Error(err, "Failed setting an owner reference on deployment")
 			return err
 		}
 
-		appName := deploy.Name
+		appName := appLabelValue(deploy.Name)
 		appSelector := metav1.LabelSelector{
 			MatchLabels: map[string]string{"app": appName},
 		}
@@ -553,10 +886,22 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 		snPolicy := cmp.Or(r.driver.Spec.SnapshotPolicy, csiv1.VolumeSnapshotSnapshotPolicy)
 		logRotationSpec := cmp.Or(r.driver.Spec.Log, &csiv1.LogSpec{}).Rotation
 		logRotationEnabled := logRotationSpec != nil
+		csiAddons := r.csiAddonsConfig()
+		metricsTLS := r.metricsTLSEnabled()
+		sidecarMetricsPorts := r.controllerPluginSidecarMetricsPorts()
+		// The snapshot-metadata certificate used to be provided through a volume named tls-key
+		legacyTlsMountIndex := r.snapshotMetadataLegacyTlsVolumeIndex()
+		snapshotMetadata := cmp.Or(r.driver.Spec.SnapshotMetadata, &csiv1.SnapshotMetadataSpec{})
+		withSnapshotMetadata := r.isRbdDriver() && (r.driver.Spec.SnapshotMetadata != nil || legacyTlsMountIndex != -1)
+		// OpenShift with SELinux restrictions requires the pod to be privileged to write to hostPath
+		privileged := cmp.Or(
+			pluginSpec.Privileged,
+			utils.If(r.platform == csiv1.OpenShiftPlatformType, ptr.To(true), nil),
+		)
 		logRotateSecurityContext := utils.If(
-			pluginSpec.Privileged != nil && logRotationEnabled,
+			privileged != nil && logRotationEnabled,
 			&corev1.SecurityContext{
-				Privileged: pluginSpec.Privileged,
+				Privileged: privileged,
 				Capabilities: &corev1.Capabilities{
 					Drop: []corev1.Capability{"All"},
 				},
@@ -575,7 +920,23 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 		nodePluginSpec := cmp.Or(r.driver.Spec.NodePlugin, &csiv1.NodePluginSpec{})
 		topology := r.isRbdDriver() && nodePluginSpec.Topology != nil
 
-		replicas := r.getControllerPluginReplicas(log, pluginSpec.Replicas)
+		replicas := r.getControllerPluginReplicas(ctx, log, pluginSpec.Replicas)
+		storageCapacity := ptr.Deref(r.driver.Spec.StorageCapacity, false)
+
+		builtinVolumeMounts := []corev1.VolumeMount{utils.SocketDirVolumeMount}
+		if logRotationEnabled {
+			builtinVolumeMounts = append(builtinVolumeMounts, utils.LogsDirVolumeMount)
+		}
+		initContainers, err := generateExtraContainers(pluginSpec.InitContainers, builtinVolumeMounts)
+		if err != nil {
+			log.Error(err, "Failed to generate user defined init containers")
+			return err
+		}
+		extraContainers, err := generateExtraContainers(pluginSpec.ExtraContainers, builtinVolumeMounts)
+		if err != nil {
+			log.Error(err, "Failed to generate user defined containers")
+			return err
+		}
 
 		deploy.Spec = appsv1.DeploymentSpec{
 			Replicas: replicas,
@@ -589,20 +950,22 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 						podLabels["app"] = appName
 						return podLabels
 					}),
-					Annotations: maps.Clone(pluginSpec.Annotations),
+					Annotations: r.generatePodAnnotations(
+						pluginSpec.Annotations,
+						csiAddonsCertificateComponent,
+						snapshotMetadataCertificateComponent,
+						metricsCertificateComponent,
+					),
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: serviceAccountName,
 					PriorityClassName:  ptr.Deref(pluginSpec.PrioritylClassName, ""),
 					HostNetwork:        ptr.Deref(pluginSpec.HostNetwork, false),
-					DNSPolicy: utils.Call(func() corev1.DNSPolicy {
-						if ptr.Deref(pluginSpec.HostNetwork, false) {
-							return corev1.DNSClusterFirstWithHostNet
-						}
-						return corev1.DNSClusterFirst
-					}),
-					Affinity:    getControllerPluginPodAffinity(pluginSpec, &appSelector),
-					Tolerations: pluginSpec.Tolerations,
+					// Resolve e.g. the FQDN of Rook orchestrated mons through the cluster DNS
+					DNSPolicy:      r.podDNSPolicy(ptr.Deref(pluginSpec.HostNetwork, false)),
+					Affinity:       getControllerPluginPodAffinity(pluginSpec, &appSelector),
+					Tolerations:    pluginSpec.Tolerations,
+					InitContainers: initContainers,
 					Containers: utils.Call(func() []corev1.Container {
 						containers := []corev1.Container{
 							// Plugin Container
@@ -611,8 +974,8 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 								Image:           r.images["plugin"],
 								ImagePullPolicy: imagePullPolicy,
 								SecurityContext: logRotateSecurityContext,
-								Args: utils.DeleteZeroValues(
-									append(
+								Args: utils.MergeContainerArgs(
+									utils.DeleteZeroValues(
 										[]string{
 											utils.TypeContainerArg(string(r.driverType)),
 											utils.LogVerbosityContainerArg(logVerbosity),
@@ -624,11 +987,7 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 											utils.SetFencingContainerArg(ptr.Deref(r.driver.Spec.EnableFencing, false)),
 											utils.ClusterNameContainerArg(ptr.Deref(r.driver.Spec.ClusterName, "")),
 											utils.If(forceKernelClient, utils.ForceCephKernelClientContainerArg, ""),
-											utils.If(
-												ptr.Deref(r.driver.Spec.DeployCsiAddons, false),
-												utils.CsiAddonsEndpointContainerArg,
-												"",
-											),
+											utils.If(csiAddons.controller, utils.CsiAddonsEndpointContainerArg, ""),
 											utils.If(logRotationEnabled, utils.LogToStdErrContainerArg, ""),
 											utils.If(logRotationEnabled, utils.AlsoLogToStdErrContainerArg, ""),
 											utils.If(
@@ -637,8 +996,8 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 												"",
 											),
 										},
-										utils.GetExtraArgsForContainer(fmt.Sprintf("csi-%splugin", r.driverType), pluginSpec.ContainerExtraArgs)...,
 									),
+									utils.GetExtraArgsForContainer(fmt.Sprintf("csi-%splugin", r.driverType), pluginSpec.ContainerExtraArgs),
 								),
 								Env: []corev1.EnvVar{
 									utils.PodIpEnvVar,
@@ -678,6 +1037,7 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 
 									return mounts
 								}),
+								LivenessProbe: r.pluginLivenessProbe(),
 								Resources: ptr.Deref(
 									pluginSpec.Resources.Plugin,
 									corev1.ResourceRequirements{},
@@ -688,8 +1048,8 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 								Name:            "csi-provisioner",
 								ImagePullPolicy: imagePullPolicy,
 								Image:           r.images["provisioner"],
-								Args: utils.DeleteZeroValues(
-									append(
+								Args: utils.MergeContainerArgs(
+									utils.DeleteZeroValues(
 										append(
 											slices.Clone(leaderElectionSettingsArg),
 											utils.LeaderElectionContainerArg,
@@ -697,14 +1057,28 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 											utils.CsiAddressContainerArg,
 											utils.TimeoutContainerArg(grpcTimeout),
 											utils.RetryIntervalStartContainerArg,
+											utils.HttpEndpointContainerArg(sidecarMetricsPorts["csi-provisioner"]),
 											utils.DefaultFsTypeContainerArg,
 											utils.PreventVolumeModeConversionContainerArg,
 											utils.If(r.isRbdOrNvemofDriver(), utils.DefaultFsTypeContainerArg, ""),
 											utils.TopologyContainerArg(topology),
 											utils.If(!r.isNfsDriver(), utils.ExtraCreateMetadataContainerArg, ""),
+											utils.If(storageCapacity, utils.EnableCapacityContainerArg, ""),
+											utils.If(
+												storageCapacity,
+												utils.CapacityOwnerrefLevelContainerArg(capacityOwnerrefLevel),
+												"",
+											),
 										),
-										utils.GetExtraArgsForContainer("csi-provisioner", pluginSpec.ContainerExtraArgs)...,
 									),
+									utils.GetExtraArgsForContainer("csi-provisioner", pluginSpec.ContainerExtraArgs),
+								),
+								// The provisioner uses the pod name and namespace to find the owner of the
+								// CSIStorageCapacity objects it publishes
+								Env: utils.If(
+									storageCapacity,
+									[]corev1.EnvVar{utils.PodNameEnvVar, utils.NamespaceEnvVar},
+									nil,
 								),
 								VolumeMounts: []corev1.VolumeMount{
 									utils.SocketDirVolumeMount,
@@ -719,8 +1093,8 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 								Name:            "csi-resizer",
 								ImagePullPolicy: imagePullPolicy,
 								Image:           r.images["resizer"],
-								Args: utils.DeleteZeroValues(
-									append(
+								Args: utils.MergeContainerArgs(
+									utils.DeleteZeroValues(
 										append(
 											slices.Clone(leaderElectionSettingsArg),
 											utils.LeaderElectionContainerArg,
@@ -728,10 +1102,11 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 											utils.CsiAddressContainerArg,
 											utils.TimeoutContainerArg(grpcTimeout),
 											utils.HandleVolumeInuseErrorContainerArg,
+											utils.HttpEndpointContainerArg(sidecarMetricsPorts["csi-resizer"]),
 											utils.RecoverVolumeExpansionFailureContainerArg,
 										),
-										utils.GetExtraArgsForContainer("csi-resizer", pluginSpec.ContainerExtraArgs)...,
 									),
+									utils.GetExtraArgsForContainer("csi-resizer", pluginSpec.ContainerExtraArgs),
 								),
 								VolumeMounts: []corev1.VolumeMount{
 									utils.SocketDirVolumeMount,
@@ -746,8 +1121,8 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 								Name:            "csi-attacher",
 								ImagePullPolicy: imagePullPolicy,
 								Image:           r.images["attacher"],
-								Args: utils.DeleteZeroValues(
-									append(
+								Args: utils.MergeContainerArgs(
+									utils.DeleteZeroValues(
 										append(
 											slices.Clone(leaderElectionSettingsArg),
 											utils.LeaderElectionContainerArg,
@@ -755,9 +1130,10 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 											utils.CsiAddressContainerArg,
 											utils.TimeoutContainerArg(grpcTimeout),
 											utils.If(r.isRbdOrNvemofDriver(), utils.DefaultFsTypeContainerArg, ""),
+											utils.HttpEndpointContainerArg(sidecarMetricsPorts["csi-attacher"]),
 										),
-										utils.GetExtraArgsForContainer("csi-attacher", pluginSpec.ContainerExtraArgs)...,
 									),
+									utils.GetExtraArgsForContainer("csi-attacher", pluginSpec.ContainerExtraArgs),
 								),
 								VolumeMounts: []corev1.VolumeMount{
 									utils.SocketDirVolumeMount,
@@ -774,8 +1150,8 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 								Name:            "csi-snapshotter",
 								ImagePullPolicy: imagePullPolicy,
 								Image:           r.images["snapshotter"],
-								Args: utils.DeleteZeroValues(
-									append(
+								Args: utils.MergeContainerArgs(
+									utils.DeleteZeroValues(
 										append(
 											slices.Clone(leaderElectionSettingsArg),
 											utils.LeaderElectionContainerArg,
@@ -783,14 +1159,15 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 											utils.CsiAddressContainerArg,
 											utils.TimeoutContainerArg(grpcTimeout),
 											utils.If(!r.isNfsDriver(), utils.ExtraCreateMetadataContainerArg, ""),
+											utils.HttpEndpointContainerArg(sidecarMetricsPorts["csi-snapshotter"]),
 											utils.If(
 												snPolicy == csiv1.VolumeGroupSnapshotPolicy,
 												utils.EnableVolumeGroupSnapshotsContainerArg,
 												"",
 											),
 										),
-										utils.GetExtraArgsForContainer("csi-snapshotter", pluginSpec.ContainerExtraArgs)...,
 									),
+									utils.GetExtraArgsForContainer("csi-snapshotter", pluginSpec.ContainerExtraArgs),
 								),
 								VolumeMounts: []corev1.VolumeMount{
 									utils.SocketDirVolumeMount,
@@ -816,8 +1193,8 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 										Drop: []corev1.Capability{"All"},
 									},
 								},
-								Args: utils.DeleteZeroValues(
-									append(
+								Args: utils.MergeContainerArgs(
+									utils.DeleteZeroValues(
 										append(
 											slices.Clone(leaderElectionSettingsArg),
 											utils.LeaderElectionContainerArg,
@@ -827,8 +1204,8 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 											utils.ExtraCreateMetadataContainerArg,
 											utils.EnableVolumeGroupSnapshotsContainerArg,
 										),
-										utils.GetExtraArgsForContainer("ex-csi-snapshotter", pluginSpec.ContainerExtraArgs)...,
 									),
+									utils.GetExtraArgsForContainer("ex-csi-snapshotter", pluginSpec.ContainerExtraArgs),
 								),
 								VolumeMounts: []corev1.VolumeMount{
 									utils.SocketDirVolumeMount,
@@ -840,28 +1217,31 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 							})
 						}
 						// Addons Sidecar Container
-						if !r.isNfsDriver() && ptr.Deref(r.driver.Spec.DeployCsiAddons, false) {
-							port := r.controllerPluginCsiAddonsContainerPort()
+						if csiAddons.controller {
+							port := csiAddons.controllerPort
 							containers = append(containers, corev1.Container{
 								Name:            "csi-addons",
 								Image:           r.images["addons"],
 								ImagePullPolicy: imagePullPolicy,
 								SecurityContext: logRotateSecurityContext,
-								Args: utils.DeleteZeroValues(
-									append(
-										append(
-											slices.Clone(leaderElectionSettingsArg),
-											utils.LogVerbosityContainerArg(logVerbosity),
-											utils.CsiAddonsNodeIdContainerArg,
-											utils.PodContainerArg,
-											utils.PodUidContainerArg,
-											utils.CsiAddonsAddressContainerArg,
-											utils.ContainerPortArg(port),
-											utils.NamespaceContainerArg,
-											utils.If(logRotationEnabled, utils.LogFileContainerArg("csi-addons"), ""),
+								Args: utils.MergeContainerArgs(
+									utils.DeleteZeroValues(
+										slices.Concat(
+											leaderElectionSettingsArg,
+											[]string{
+												utils.LogVerbosityContainerArg(logVerbosity),
+												utils.CsiAddonsNodeIdContainerArg,
+												utils.PodContainerArg,
+												utils.PodUidContainerArg,
+												utils.CsiAddonsAddressContainerArg,
+												utils.ContainerPortArg(port),
+												utils.NamespaceContainerArg,
+												utils.If(logRotationEnabled, utils.LogFileContainerArg("csi-addons"), ""),
+											},
+											csiAddons.tlsContainerArgs(),
 										),
-										utils.GetExtraArgsForContainer("csi-addons", pluginSpec.ContainerExtraArgs)...,
 									),
+									utils.GetExtraArgsForContainer("csi-addons", pluginSpec.ContainerExtraArgs),
 								),
 								Ports: []corev1.ContainerPort{
 									port,
@@ -879,10 +1259,13 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 									if logRotationEnabled {
 										mounts = append(mounts, utils.LogsDirVolumeMount)
 									}
+									if csiAddons.tls != nil {
+										mounts = append(mounts, utils.CsiAddonsTlsVolumeMount)
+									}
 									return mounts
 								}),
 								Resources: ptr.Deref(
-									pluginSpec.Resources.Addons,
+									cmp.Or(csiAddons.controllerResources, pluginSpec.Resources.Addons),
 									corev1.ResourceRequirements{},
 								),
 							})
@@ -893,8 +1276,8 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 								Name:            "csi-omap-generator",
 								Image:           r.images["plugin"],
 								ImagePullPolicy: imagePullPolicy,
-								Args: utils.DeleteZeroValues(
-									append(
+								Args: utils.MergeContainerArgs(
+									utils.DeleteZeroValues(
 										[]string{
 											utils.LogVerbosityContainerArg(logVerbosity),
 											utils.TypeContainerArg("controller"),
@@ -902,8 +1285,8 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 											utils.DriverNameContainerArg(r.driver.Name),
 											utils.ClusterNameContainerArg(ptr.Deref(r.driver.Spec.ClusterName, "")),
 										},
-										utils.GetExtraArgsForContainer("csi-omap-generator", pluginSpec.ContainerExtraArgs)...,
 									),
+									utils.GetExtraArgsForContainer("csi-omap-generator", pluginSpec.ContainerExtraArgs),
 								),
 								Env: []corev1.EnvVar{
 									utils.DriverNamespaceEnvVar,
@@ -918,14 +1301,46 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 								),
 							})
 						}
+						// Volume Health Monitor Sidecar Container
+						if volumeHealth := r.driver.Spec.VolumeHealth; volumeHealth != nil {
+							monitorInterval := ptr.Deref(
+								volumeHealth.MonitorInterval,
+								metav1.Duration{Duration: defaultVolumeHealthMonitorInterval},
+							)
+							containers = append(containers, corev1.Container{
+								Name:            "csi-external-health-monitor-controller",
+								Image:           r.images["health-monitor"],
+								ImagePullPolicy: imagePullPolicy,
+								Args: utils.MergeContainerArgs(
+									utils.DeleteZeroValues(
+										append(
+											slices.Clone(leaderElectionSettingsArg),
+											utils.LeaderElectionContainerArg,
+											utils.LogVerbosityContainerArg(logVerbosity),
+											utils.CsiAddressContainerArg,
+											utils.TimeoutContainerArg(grpcTimeout),
+											utils.MonitorIntervalContainerArg(monitorInterval.Duration),
+										),
+									),
+									utils.GetExtraArgsForContainer(
+										"csi-external-health-monitor-controller",
+										pluginSpec.ContainerExtraArgs,
+									),
+								),
+								VolumeMounts: []corev1.VolumeMount{
+									utils.SocketDirVolumeMount,
+								},
+								Resources: ptr.Deref(volumeHealth.Resources, corev1.ResourceRequirements{}),
+							})
+						}
 						// Liveness Sidecar Container
 						if r.driver.Spec.Liveness != nil {
 							containers = append(containers, corev1.Container{
 								Name:            "liveness-prometheus",
 								Image:           r.images["plugin"],
 								ImagePullPolicy: imagePullPolicy,
-								Args: utils.DeleteZeroValues(
-									append(
+								Args: utils.MergeContainerArgs(
+									utils.DeleteZeroValues(
 										[]string{
 											utils.TypeContainerArg("liveness"),
 											utils.EndpointContainerArg,
@@ -934,11 +1349,11 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 											utils.PoolTimeContainerArg,
 											utils.TimeoutContainerArg(3),
 										},
-										utils.GetExtraArgsForContainer("liveness-prometheus", pluginSpec.ContainerExtraArgs)...,
 									),
+									utils.GetExtraArgsForContainer("liveness-prometheus", pluginSpec.ContainerExtraArgs),
 								),
 								Env: []corev1.EnvVar{
-									utils.PodIpEnvVar,
+									utils.If(metricsTLS, utils.LoopbackPodIpEnvVar, utils.PodIpEnvVar),
 								},
 								VolumeMounts: []corev1.VolumeMount{
 									utils.SocketDirVolumeMount,
@@ -948,6 +1363,35 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 									corev1.ResourceRequirements{},
 								),
 							})
+							// Liveness Probe Sidecar Container, serving the health endpoint of the plugin liveness probe
+							containers = append(containers, corev1.Container{
+								Name:            "liveness-probe",
+								Image:           r.images["livenessprobe"],
+								ImagePullPolicy: imagePullPolicy,
+								Args: utils.MergeContainerArgs(
+									[]string{
+										utils.CsiAddressContainerArg,
+										utils.HttpEndpointContainerArg(r.livenessHealthPort()),
+										utils.ProbeTimeoutContainerArg(3),
+									},
+									utils.GetExtraArgsForContainer("liveness-probe", pluginSpec.ContainerExtraArgs),
+								),
+								VolumeMounts: []corev1.VolumeMount{
+									utils.SocketDirVolumeMount,
+								},
+								Resources: ptr.Deref(
+									pluginSpec.Resources.Liveness,
+									corev1.ResourceRequirements{},
+								),
+							})
+							// Metrics Proxy Sidecar Container, serving the liveness metrics over TLS
+							if metricsTLS {
+								containers = append(containers, r.metricsProxyContainer(
+									imagePullPolicy,
+									pluginSpec.ContainerExtraArgs,
+									pluginSpec.Resources.Liveness,
+								))
+							}
 						}
 						// CSI LogRotate Container
 						if logRotationEnabled {
@@ -966,43 +1410,48 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 							})
 						}
 						// CSI snapshot-metadata Sidecar Container
-						if r.isRbdDriver() {
-							tlsMountIndex := slices.IndexFunc(pluginSpec.Volumes, func(vol csiv1.VolumeSpec) bool {
-								return vol.Volume.Name == "tls-key"
-							})
-							if tlsMountIndex != -1 {
-								containers = append(containers, corev1.Container{
-									Name:            "csi-snapshot-metadata",
-									ImagePullPolicy: imagePullPolicy,
-									Image:           r.images["snapshot-metadata"],
-									Args: utils.DeleteZeroValues(
-										append(
-											[]string{
-												utils.LogVerbosityContainerArg(logVerbosity),
-												utils.TimeoutContainerArg(grpcTimeout),
-												utils.SnapshotMetadataGrpcServicePortArg,
-												utils.CsiAddressContainerArg,
-												utils.SnapshotMetadataTlsCertArg,
-												utils.SnapshotMetadataTlsKeyArg,
-												utils.SnapshotMetadataAudienceArg(r.driver.Name),
-											},
-											utils.GetExtraArgsForContainer("csi-snapshot-metadata", pluginSpec.ContainerExtraArgs)...,
-										),
+						if withSnapshotMetadata {
+							containers = append(containers, corev1.Container{
+								Name:            "csi-snapshot-metadata",
+								ImagePullPolicy: imagePullPolicy,
+								Image:           r.images["snapshot-metadata"],
+								Args: utils.MergeContainerArgs(
+									utils.DeleteZeroValues(
+										[]string{
+											utils.LogVerbosityContainerArg(logVerbosity),
+											utils.TimeoutContainerArg(grpcTimeout),
+											utils.SnapshotMetadataGrpcServicePortArg,
+											utils.CsiAddressContainerArg,
+											utils.SnapshotMetadataTlsCertArg,
+											utils.SnapshotMetadataTlsKeyArg,
+											utils.SnapshotMetadataAudienceArg(r.driver.Name),
+										},
 									),
-									Ports: []corev1.ContainerPort{
-										utils.SnapshotMetadataGrpcPort,
-									},
-									VolumeMounts: utils.Call(func() []corev1.VolumeMount {
-										mounts := []corev1.VolumeMount{}
-										mounts = append(mounts, pluginSpec.Volumes[tlsMountIndex].Mount)
-										mounts = append(mounts, utils.SocketDirVolumeMount)
-										return mounts
-									}),
-								})
-							}
+									utils.GetExtraArgsForContainer("csi-snapshot-metadata", pluginSpec.ContainerExtraArgs),
+								),
+								Ports: []corev1.ContainerPort{
+									utils.SnapshotMetadataGrpcPort,
+								},
+								VolumeMounts: utils.Call(func() []corev1.VolumeMount {
+									mounts := []corev1.VolumeMount{}
+									if legacyTlsMountIndex != -1 {
+										mounts = append(mounts, pluginSpec.Volumes[legacyTlsMountIndex].Mount)
+									} else {
+										mounts = append(mounts, utils.SnapshotMetadataTlsVolumeMount)
+									}
+									mounts = append(mounts, utils.SocketDirVolumeMount)
+									return mounts
+								}),
+								Resources: ptr.Deref(
+									snapshotMetadata.Resources,
+									corev1.ResourceRequirements{},
+								),
+							})
 						}
 
-						return containers
+						// Add user defined containers at the end to make sure they
+						// can overwrite built in containers.
+						return mergeExtraContainers(containers, extraContainers)
 					}),
 					Volumes: utils.Call(func() []corev1.Volume {
 						volumes := []corev1.Volume{
@@ -1027,6 +1476,15 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 								utils.LogRotateDirVolumeName(r.driver.Name),
 							)
 						}
+						if csiAddons.controller && csiAddons.tls != nil {
+							volumes = append(volumes, utils.CsiAddonsTlsVolume(csiAddons.tlsSecretName))
+						}
+						if withSnapshotMetadata && legacyTlsMountIndex == -1 {
+							volumes = append(volumes, utils.SnapshotMetadataTlsVolume(r.snapshotMetadataSecretName()))
+						}
+						if metricsTLS {
+							volumes = append(volumes, utils.MetricsTlsVolume(r.certificateSecretName(metricsCertificateComponent)))
+						}
 						// Add user defined volumes at the end to make sure they
 						// can overwrite built in volumes.
 						volumes = utils.MapMergeByKey(volumes,
@@ -1045,21 +1503,27 @@ func (r *driverReconcile) reconcileControllerPluginDeployment() error {
 			},
 		}
 
-		return nil
+		// Apply user defined container overrides and patches on top of the generated deployment
+		applyContainerOverrides(&deploy.Spec.Template.Spec, pluginSpec.ContainerOverrides)
+		return applyWorkloadPatches(deploy, pluginSpec.Patches)
 	})
 
-	logCreateOrUpdateResult(log, "controller plugin deployment", deploy, opResult, err)
+	r.logCreateOrUpdateResult(log, "controller plugin deployment", deploy, opResult, err)
+	if err == nil {
+		r.recordContainerArgs(deploy.Name, &deploy.Spec.Template.Spec)
+	}
+	r.recordPluginWorkloadError(err)
 	return err
 }
 
-func (r *driverReconcile) reconcileControllerPluginNetworkPolicy() error {
+func (r *driverReconcile) reconcileControllerPluginNetworkPolicy(ctx context.Context) error {
 	np := &networkingv1.NetworkPolicy{}
 	np.Name = r.generateName("ctrlplugin")
 	np.Namespace = r.driver.Namespace
 
 	pluginSpec := cmp.Or(r.driver.Spec.ControllerPlugin, &csiv1.ControllerPluginSpec{})
 	if ptr.Deref(pluginSpec.HostNetwork, false) {
-		if err := r.Delete(r.ctx, np); client.IgnoreNotFound(err) != nil {
+		if err := r.Delete(ctx, np); client.IgnoreNotFound(err) != nil {
 			return err
 		}
 		return nil
@@ -1068,8 +1532,8 @@ func (r *driverReconcile) reconcileControllerPluginNetworkPolicy() error {
 	proto := corev1.ProtocolTCP
 	var ingress []networkingv1.NetworkPolicyIngressRule
 
-	if ptr.Deref(r.driver.Spec.DeployCsiAddons, false) {
-		csiAddonsPort := r.controllerPluginCsiAddonsContainerPort()
+	if csiAddons := r.csiAddonsConfig(); csiAddons.controller {
+		csiAddonsPort := csiAddons.controllerPort
 		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
 			From: []networkingv1.NetworkPolicyPeer{{
 				NamespaceSelector: &metav1.LabelSelector{},
@@ -1087,10 +1551,7 @@ func (r *driverReconcile) reconcileControllerPluginNetworkPolicy() error {
 	}
 
 	if r.isRbdDriver() {
-		tlsConfigured := slices.ContainsFunc(pluginSpec.Volumes, func(vol csiv1.VolumeSpec) bool {
-			return vol.Volume.Name == "tls-key"
-		})
-		if tlsConfigured {
+		if r.driver.Spec.SnapshotMetadata != nil || r.snapshotMetadataLegacyTlsVolumeIndex() != -1 {
 			ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
 				Ports: []networkingv1.NetworkPolicyPort{
 					{Port: &intstr.IntOrString{Type: intstr.Int, IntVal: utils.SnapshotMetadataGrpcPort.ContainerPort}, Protocol: &proto},
@@ -1099,16 +1560,36 @@ func (r *driverReconcile) reconcileControllerPluginNetworkPolicy() error {
 		}
 	}
 
+	// Metrics are scraped by Prometheus, which may run in any namespace
+	metricsPorts := []networkingv1.NetworkPolicyPort{}
+	if r.driver.Spec.Liveness != nil {
+		metricsPorts = append(metricsPorts, networkingv1.NetworkPolicyPort{
+			Port:     ptr.To(intstr.FromInt(r.driver.Spec.Liveness.MetricsPort)),
+			Protocol: &proto,
+		})
+	}
+	for _, container := range controllerPluginMetricsSidecars {
+		if port, ok := r.controllerPluginSidecarMetricsPorts()[container]; ok {
+			metricsPorts = append(metricsPorts, networkingv1.NetworkPolicyPort{
+				Port:     ptr.To(intstr.FromInt32(port)),
+				Protocol: &proto,
+			})
+		}
+	}
+	if len(metricsPorts) > 0 {
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{Ports: metricsPorts})
+	}
+
 	log := r.log.WithValues("networkPolicy", np.Name)
 	log.Info("Reconciling controller plugin network policy")
 
-	opResult, err := ctrlutil.CreateOrUpdate(r.ctx, r.Client, np, func() error {
+	opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, np, func() error {
 		if err := ctrlutil.SetControllerReference(&r.driver, np, r.Scheme); err != nil {
 			return err
 		}
 		np.Spec = networkingv1.NetworkPolicySpec{
 			PodSelector: metav1.LabelSelector{
-				MatchLabels: map[string]string{"app": np.Name},
+				MatchLabels: map[string]string{"app": appLabelValue(np.Name)},
 			},
 			Ingress:     ingress,
 			Egress:      []networkingv1.NetworkPolicyEgressRule{{}},
@@ -1117,7 +1598,7 @@ func (r *driverReconcile) reconcileControllerPluginNetworkPolicy() error {
 		return nil
 	})
 
-	logCreateOrUpdateResult(log, "NetworkPolicy", np, opResult, err)
+	r.logCreateOrUpdateResult(log, "NetworkPolicy", np, opResult, err)
 	return err
 }
 
@@ -1132,65 +1613,82 @@ func (r *driverReconcile) controllerPluginCsiAddonsContainerPort() corev1.Contai
 	return port
 }
 
-func (r *driverReconcile) reconcileNodePluginDaemonSetForCsiAddons() error {
-	daemonSet := &appsv1.DaemonSet{}
-	daemonSet.Name = r.generateName("nodeplugin-csi-addons")
-	daemonSet.Namespace = r.driver.Namespace
-
-	log := r.log.WithValues("csiAddonsDaemonSetName", daemonSet.Name)
-
-	withCsiAddonsDaemonSet := ptr.Deref(r.driver.Spec.DeployCsiAddons, false)
-	withCsiAddonsVolumeCondition := false
+// reconcileNodePluginDaemonSetsForCsiAddons reconciles the CSI-Addons daemonsets, one per node plugin
+// daemonset so that the sidecar is scheduled on the same nodes and uses the same kubelet directory
+func (r *driverReconcile) reconcileNodePluginDaemonSetsForCsiAddons(ctx context.Context) error {
+	pools := cmp.Or(r.driver.Spec.NodePlugin, &csiv1.NodePluginSpec{}).NodePools
 
-	// check if the driver wants CSI-Addons features
-	if feature := r.driver.GetAnnotations()[driverCSIAddonsFeatureVolumeCondition]; feature != "" {
-		enabled, err := strconv.ParseBool(feature)
-		if err != nil {
-			r.log.Error(
-				err,
-				"Unable to parse annotation on driver.csi.ceph.io",
-				"name",
-				client.ObjectKeyFromObject(&r.driver),
-				driverCSIAddonsFeatureVolumeCondition,
-				feature,
-			)
-			return err
+	errList := []error{}
+	poolDaemonSetNames := []string{}
+	if r.csiAddonsConfig().node {
+		for i := range pools {
+			poolDaemonSetNames = append(poolDaemonSetNames, r.generateCsiAddonsDaemonSetName(&pools[i]))
+		}
+		// Invalid node pool selectors are reported by the node plugin daemonsets step
+		placements, _ := r.nodePluginPlacements()
+		for _, placement := range placements {
+			errList = append(errList, r.reconcileNodePluginDaemonSetForCsiAddons(
+				ctx,
+				placement.pool,
+				placement.nodeSelector,
+				placement.kubeletDirPath,
+			))
+		}
+	} else {
+		daemonSet := &appsv1.DaemonSet{}
+		daemonSet.Name = r.generateCsiAddonsDaemonSetName(nil)
+		daemonSet.Namespace = r.driver.Namespace
+		if err := r.Delete(ctx, daemonSet); client.IgnoreNotFound(err) != nil {
+			r.log.Error(err, "failed to delete csi addons daemonset", "csiAddonsDaemonSetName", daemonSet.Name)
+			errList = append(errList, err)
 		}
-
-		withCsiAddonsVolumeCondition = enabled
 	}
 
-	if r.isNfsDriver() {
-		withCsiAddonsDaemonSet = false
-	}
+	errList = append(errList, r.pruneNodePoolDaemonSets(ctx, csiAddonsNodePoolLabelKey, poolDaemonSetNames))
+	return errors.Join(errList...)
+}
 
-	if !withCsiAddonsDaemonSet {
-		if err := r.Delete(r.ctx, daemonSet); client.IgnoreNotFound(err) != nil {
-			log.Error(err, "failed to delete csi addons daemonset")
-			return err
-		}
-		return nil
-	}
+// reconcileNodePluginDaemonSetForCsiAddons reconciles the CSI-Addons daemonset of the given node pool,
+// or the default CSI-Addons daemonset if pool is nil
+func (r *driverReconcile) reconcileNodePluginDaemonSetForCsiAddons(
+	ctx context.Context,
+	pool *csiv1.NodePoolSpec,
+	nodeSelector *corev1.NodeSelector,
+	kubeletDirPath string,
+) error {
+	daemonSet := &appsv1.DaemonSet{}
+	daemonSet.Name = r.generateCsiAddonsDaemonSetName(pool)
+	daemonSet.Namespace = r.driver.Namespace
 
+	log := r.log.WithValues("csiAddonsDaemonSetName", daemonSet.Name)
 	log.Info("Reconciling csi addons nodeplugin daemonset")
 
-	opResult, err := ctrlutil.CreateOrUpdate(r.ctx, r.Client, daemonSet, func() error {
+	csiAddons := r.csiAddonsConfig()
+
+	var currentTemplate *corev1.PodTemplateSpec
+	opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, daemonSet, func() error {
+		currentTemplate = daemonSet.Spec.Template.DeepCopy()
 		if err := ctrlutil.SetControllerReference(&r.driver, daemonSet, r.Scheme); err != nil {
 			log.Error(err, "Failed to set owner reference on csi addons nodeplugin daemonset")
 
 			return err
 		}
+		if pool != nil {
+			if daemonSet.Labels == nil {
+				daemonSet.Labels = map[string]string{}
+			}
+			daemonSet.Labels[csiAddonsNodePoolLabelKey] = pool.Name
+		}
 
-		appName := daemonSet.Name
-		pluginSpec := cmp.Or(r.driver.Spec.NodePlugin, &csiv1.NodePluginSpec{})
+		appName := appLabelValue(daemonSet.Name)
+		pluginSpec := mergeNodePoolSpec(cmp.Or(r.driver.Spec.NodePlugin, &csiv1.NodePluginSpec{}), pool)
 		serviceAccountName := cmp.Or(
 			ptr.Deref(pluginSpec.ServiceAccountName, ""),
 			fmt.Sprintf("%s%s-nodeplugin-sa", serviceAccountPrefix, r.driverType),
 		)
 		imagePullPolicy := cmp.Or(pluginSpec.ImagePullPolicy, corev1.PullIfNotPresent)
 		logVerbosity := ptr.Deref(r.driver.Spec.Log, csiv1.LogSpec{}).Verbosity
-		kubeletDirPath := cmp.Or(pluginSpec.KubeletDirPath, defaultKubeletDirPath)
-		port := utils.NodePluginCsiAddonsContainerPort
+		port := csiAddons.nodePort
 
 		logRotationSpec := cmp.Or(r.driver.Spec.Log, &csiv1.LogSpec{}).Rotation
 		logRotationEnabled := logRotationSpec != nil
@@ -1208,16 +1706,16 @@ func (r *driverReconcile) reconcileNodePluginDaemonSetForCsiAddons() error {
 						podLabels["app"] = appName
 						return podLabels
 					}),
-					Annotations: maps.Clone(pluginSpec.Annotations),
+					Annotations: r.generatePodAnnotations(pluginSpec.Annotations, csiAddonsCertificateComponent),
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: serviceAccountName,
 					PriorityClassName:  ptr.Deref(pluginSpec.PrioritylClassName, ""),
 					// to use e.g. Rook orchestrated cluster, and mons' FQDN is
 					// resolved through k8s service, set dns policy to cluster first
-					DNSPolicy:   corev1.DNSClusterFirstWithHostNet,
+					DNSPolicy:   r.podDNSPolicy(false),
 					Tolerations: pluginSpec.Tolerations,
-					Affinity:    pluginSpec.Affinity,
+					Affinity:    mergeNodeAffinity(pluginSpec.Affinity, nodeSelector),
 					Containers: utils.Call(func() []corev1.Container {
 						containers := []corev1.Container{
 							{
@@ -1233,22 +1731,25 @@ func (r *driverReconcile) reconcileNodePluginDaemonSetForCsiAddons() error {
 										Drop: []corev1.Capability{"All"},
 									},
 								},
-								Args: utils.DeleteZeroValues(
-									append(
-										[]string{
-											utils.CsiAddonsNodeIdContainerArg,
-											utils.LogVerbosityContainerArg(logVerbosity),
-											utils.CsiAddonsAddressContainerArg,
-											utils.ContainerPortArg(port),
-											utils.PodContainerArg,
-											utils.NamespaceContainerArg,
-											utils.PodUidContainerArg,
-											utils.StagingPathContainerArg(kubeletDirPath),
-											utils.If(logRotationEnabled, utils.LogFileContainerArg("csi-addons"), ""),
-											utils.If(withCsiAddonsVolumeCondition, utils.CsiAddonsVolumeConditionArg, ""),
-										},
-										utils.GetExtraArgsForContainer("csi-addons", pluginSpec.ContainerExtraArgs)...,
+								Args: utils.MergeContainerArgs(
+									utils.DeleteZeroValues(
+										append(
+											[]string{
+												utils.CsiAddonsNodeIdContainerArg,
+												utils.LogVerbosityContainerArg(logVerbosity),
+												utils.CsiAddonsAddressContainerArg,
+												utils.ContainerPortArg(port),
+												utils.PodContainerArg,
+												utils.NamespaceContainerArg,
+												utils.PodUidContainerArg,
+												utils.If(csiAddons.stagingPath, utils.StagingPathContainerArg(kubeletDirPath), ""),
+												utils.If(logRotationEnabled, utils.LogFileContainerArg("csi-addons"), ""),
+												utils.If(csiAddons.volumeCondition, utils.CsiAddonsVolumeConditionArg, ""),
+											},
+											csiAddons.tlsContainerArgs()...,
+										),
 									),
+									utils.GetExtraArgsForContainer("csi-addons", pluginSpec.ContainerExtraArgs),
 								),
 								Ports: []corev1.ContainerPort{
 									port,
@@ -1266,16 +1767,19 @@ func (r *driverReconcile) reconcileNodePluginDaemonSetForCsiAddons() error {
 									if logRotationEnabled {
 										mounts = append(mounts, utils.LogsDirVolumeMount)
 									}
-									if withCsiAddonsVolumeCondition {
+									if csiAddons.volumeCondition {
 										mounts = append(mounts,
 											utils.PluginMountDirVolumeMount(kubeletDirPath),
 											utils.PodsMountDirVolumeMount(kubeletDirPath),
 										)
 									}
+									if csiAddons.tls != nil {
+										mounts = append(mounts, utils.CsiAddonsTlsVolumeMount)
+									}
 									return mounts
 								}),
 								Resources: ptr.Deref(
-									pluginSpec.Resources.Addons,
+									cmp.Or(csiAddons.nodeResources, pluginSpec.Resources.Addons),
 									corev1.ResourceRequirements{},
 								),
 							},
@@ -1317,27 +1821,37 @@ func (r *driverReconcile) reconcileNodePluginDaemonSetForCsiAddons() error {
 							)
 						}
 
-						if withCsiAddonsVolumeCondition {
+						if csiAddons.volumeCondition {
 							volumes = append(
 								volumes,
 								utils.PluginMountDirVolume(kubeletDirPath),
 								utils.PodsMountDirVolume(kubeletDirPath),
 							)
 						}
+						if csiAddons.tls != nil {
+							volumes = append(volumes, utils.CsiAddonsTlsVolume(csiAddons.tlsSecretName))
+						}
 						return volumes
 					}),
 				},
 			},
 		}
 
+		// Apply user defined container overrides on top of the generated daemonset
+		applyContainerOverrides(&daemonSet.Spec.Template.Spec, pluginSpec.ContainerOverrides)
+
 		return nil
 	})
 
-	logCreateOrUpdateResult(log, "csi addons node plugin daemonset", daemonSet, opResult, err)
+	r.logCreateOrUpdateResult(log, "csi addons node plugin daemonset", daemonSet, opResult, err)
+	r.recordDaemonSetRolled(daemonSet, currentTemplate, opResult)
+	if err == nil {
+		r.recordContainerArgs(daemonSet.Name, &daemonSet.Spec.Template.Spec)
+	}
 	return err
 }
 
-func (r *driverReconcile) reconcileNodePluginCsiAddonsNetworkPolicy() error {
+func (r *driverReconcile) reconcileNodePluginCsiAddonsNetworkPolicy(ctx context.Context) error {
 	if r.isNfsDriver() {
 		return nil
 	}
@@ -1346,8 +1860,9 @@ func (r *driverReconcile) reconcileNodePluginCsiAddonsNetworkPolicy() error {
 	np.Name = r.generateName("nodeplugin-csi-addons")
 	np.Namespace = r.driver.Namespace
 
-	if !ptr.Deref(r.driver.Spec.DeployCsiAddons, false) {
-		if err := r.Delete(r.ctx, np); client.IgnoreNotFound(err) != nil {
+	csiAddons := r.csiAddonsConfig()
+	if !csiAddons.node {
+		if err := r.Delete(ctx, np); client.IgnoreNotFound(err) != nil {
 			return err
 		}
 		return nil
@@ -1357,14 +1872,26 @@ func (r *driverReconcile) reconcileNodePluginCsiAddonsNetworkPolicy() error {
 	log.Info("Reconciling csi-addons nodeplugin network policy")
 
 	proto := corev1.ProtocolTCP
-	opResult, err := ctrlutil.CreateOrUpdate(r.ctx, r.Client, np, func() error {
+	opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, np, func() error {
 		if err := ctrlutil.SetControllerReference(&r.driver, np, r.Scheme); err != nil {
 			return err
 		}
+		// Select the pods of the default and node pool CSI-Addons daemonsets
+		appNames := []string{appLabelValue(r.generateCsiAddonsDaemonSetName(nil))}
+		pools := cmp.Or(r.driver.Spec.NodePlugin, &csiv1.NodePluginSpec{}).NodePools
+		for i := range pools {
+			appNames = append(appNames, appLabelValue(r.generateCsiAddonsDaemonSetName(&pools[i])))
+		}
 		np.Spec = networkingv1.NetworkPolicySpec{
-			PodSelector: metav1.LabelSelector{
-				MatchLabels: map[string]string{"app": np.Name},
-			},
+			PodSelector: utils.If(
+				len(appNames) == 1,
+				metav1.LabelSelector{MatchLabels: map[string]string{"app": appNames[0]}},
+				metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      "app",
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   appNames,
+				}}},
+			),
 			Ingress: []networkingv1.NetworkPolicyIngressRule{{
 				From: []networkingv1.NetworkPolicyPeer{{
 					NamespaceSelector: &metav1.LabelSelector{},
@@ -1376,7 +1903,7 @@ func (r *driverReconcile) reconcileNodePluginCsiAddonsNetworkPolicy() error {
 					},
 				}},
 				Ports: []networkingv1.NetworkPolicyPort{
-					{Port: &intstr.IntOrString{Type: intstr.Int, IntVal: utils.NodePluginCsiAddonsContainerPort.ContainerPort}, Protocol: &proto},
+					{Port: &intstr.IntOrString{Type: intstr.Int, IntVal: csiAddons.nodePort.ContainerPort}, Protocol: &proto},
 				},
 			}},
 			Egress:      []networkingv1.NetworkPolicyEgressRule{{}},
@@ -1385,40 +1912,202 @@ func (r *driverReconcile) reconcileNodePluginCsiAddonsNetworkPolicy() error {
 		return nil
 	})
 
-	logCreateOrUpdateResult(log, "NetworkPolicy", np, opResult, err)
+	r.logCreateOrUpdateResult(log, "NetworkPolicy", np, opResult, err)
 	return err
 }
 
-func (r *driverReconcile) reconcileNodePluginDaemonSet() error {
+// nodePluginPlacement describes where a node plugin daemonset is scheduled
+type nodePluginPlacement struct {
+	// The node pool of the daemonset, nil for the default daemonset
+	pool           *csiv1.NodePoolSpec
+	nodeSelector   *corev1.NodeSelector
+	nodes          []*corev1.Node
+	kubeletDirPath string
+}
+
+// nodePluginPlacements returns the placement of the default node plugin daemonset, scheduled only
+// on nodes that are not part of any pool, and of the node pool daemonsets. Pools whose node selector
+// cannot be generated are skipped and reported in the returned error.
+func (r *driverReconcile) nodePluginPlacements() ([]nodePluginPlacement, error) {
+	pluginSpec := cmp.Or(r.driver.Spec.NodePlugin, &csiv1.NodePluginSpec{})
+	pools := pluginSpec.NodePools
+
+	placements := []nodePluginPlacement{}
+	errList := []error{}
+	for i := -1; i < len(pools); i++ {
+		var pool *csiv1.NodePoolSpec
+		if i >= 0 {
+			pool = &pools[i]
+		}
+		nodeSelector, err := nodePoolNodeSelector(pools, i)
+		if err != nil {
+			errList = append(errList, err)
+			continue
+		}
+
+		// Unless explicitly set, use the kubelet directory detected on the nodes the daemonset
+		// is scheduled on
+		nodes := filterNodesBySelector(r.nodes, nodeSelector)
+		placements = append(placements, nodePluginPlacement{
+			pool:         pool,
+			nodeSelector: nodeSelector,
+			nodes:        nodes,
+			kubeletDirPath: cmp.Or(
+				cmp.Or(pool, &csiv1.NodePoolSpec{}).KubeletDirPath,
+				pluginSpec.KubeletDirPath,
+				detectKubeletDirPath(nodes, r.platform),
+			),
+		})
+	}
+	return placements, errors.Join(errList...)
+}
+
+func (r *driverReconcile) reconcileNodePluginDaemonSets(ctx context.Context) error {
+	pools := cmp.Or(r.driver.Spec.NodePlugin, &csiv1.NodePluginSpec{}).NodePools
+
+	errList := []error{}
+	placements, err := r.nodePluginPlacements()
+	if err != nil {
+		r.log.Error(err, "Failed to generate the node selectors of the node plugin daemonsets")
+		r.recordPluginWorkloadError(err)
+		errList = append(errList, err)
+	}
+
+	mismatches := []csiv1.KubeletDirPathMismatchStatus{}
+	for _, placement := range placements {
+		// Report nodes that are using a different kubelet directory than the daemonset
+		mismatches = append(mismatches, kubeletDirPathMismatches(
+			r.generateNodePluginDaemonSetName(placement.pool),
+			placement.pool,
+			placement.kubeletDirPath,
+			placement.nodes,
+		)...)
+		errList = append(errList, r.reconcileNodePluginDaemonSet(
+			ctx,
+			placement.pool,
+			placement.nodeSelector,
+			placement.kubeletDirPath,
+		))
+	}
+
+	r.statusLock.Lock()
+	r.kubeletDirPathMismatches = mismatches
+	r.statusLock.Unlock()
+
+	// Remove daemonsets of pools that are no longer part of the spec
+	poolDaemonSetNames := make([]string, len(pools))
+	for i := range pools {
+		poolDaemonSetNames[i] = r.generateNodePluginDaemonSetName(&pools[i])
+	}
+	errList = append(errList, r.pruneNodePoolDaemonSets(ctx, nodePoolLabelKey, poolDaemonSetNames))
+
+	return errors.Join(errList...)
+}
+
+// pruneNodePoolDaemonSets removes the node pool daemonsets marked with the given label, other than
+// the daemonsets of the pools in the spec
+func (r *driverReconcile) pruneNodePoolDaemonSets(
+	ctx context.Context,
+	poolLabelKey string,
+	poolDaemonSetNames []string,
+) error {
+	daemonSetList := &appsv1.DaemonSetList{}
+	if err := r.List(
+		ctx,
+		daemonSetList,
+		client.InNamespace(r.driver.Namespace),
+		client.HasLabels{poolLabelKey},
+	); err != nil {
+		r.log.Error(err, "Failed to list node pool daemonsets")
+		return err
+	}
+
+	for i := range daemonSetList.Items {
+		daemonSet := &daemonSetList.Items[i]
+		if !metav1.IsControlledBy(daemonSet, &r.driver) || slices.Contains(poolDaemonSetNames, daemonSet.Name) {
+			continue
+		}
+		r.log.Info("Deleting daemonset of a removed node pool", "daemonSetName", daemonSet.Name)
+		if err := r.Delete(ctx, daemonSet); client.IgnoreNotFound(err) != nil {
+			r.log.Error(err, "Failed to delete node pool daemonset", "daemonSetName", daemonSet.Name)
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileNodePluginDaemonSet reconciles the node plugin daemonset of the given node pool, or the
+// default node plugin daemonset if pool is nil
+func (r *driverReconcile) reconcileNodePluginDaemonSet(
+	ctx context.Context,
+	pool *csiv1.NodePoolSpec,
+	nodeSelector *corev1.NodeSelector,
+	kubeletDirPath string,
+) error {
 	daemonSet := &appsv1.DaemonSet{}
-	daemonSet.Name = r.generateName("nodeplugin")
+	daemonSet.Name = r.generateNodePluginDaemonSetName(pool)
 	daemonSet.Namespace = r.driver.Namespace
 
 	log := r.log.WithValues("daemonSetName", daemonSet.Name)
 	log.Info("Reconciling node plugin deployment")
 
-	opResult, err := ctrlutil.CreateOrUpdate(r.ctx, r.Client, daemonSet, func() error {
+	var currentTemplate *corev1.PodTemplateSpec
+	opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, daemonSet, func() error {
+		currentTemplate = daemonSet.Spec.Template.DeepCopy()
 		if err := ctrlutil.SetControllerReference(&r.driver, daemonSet, r.Scheme); err != nil {
 			log.Error(err, "Failed setting an owner reference on deployment")
 			return err
 		}
+		if pool != nil {
+			if daemonSet.Labels == nil {
+				daemonSet.Labels = map[string]string{}
+			}
+			daemonSet.Labels[nodePoolLabelKey] = pool.Name
+		}
 
-		appName := daemonSet.Name
-		pluginSpec := cmp.Or(r.driver.Spec.NodePlugin, &csiv1.NodePluginSpec{})
+		appName := appLabelValue(daemonSet.Name)
+		pluginSpec := mergeNodePoolSpec(cmp.Or(r.driver.Spec.NodePlugin, &csiv1.NodePluginSpec{}), pool)
 		serviceAccountName := cmp.Or(
 			ptr.Deref(pluginSpec.ServiceAccountName, ""),
 			fmt.Sprintf("%s%s-nodeplugin-sa", serviceAccountPrefix, r.driverType),
 		)
 		imagePullPolicy := cmp.Or(pluginSpec.ImagePullPolicy, corev1.PullIfNotPresent)
 		logVerbosity := ptr.Deref(r.driver.Spec.Log, csiv1.LogSpec{}).Verbosity
-		kubeletDirPath := cmp.Or(pluginSpec.KubeletDirPath, defaultKubeletDirPath)
 		forceKernelClient := r.isCephFsDriver() && r.driver.Spec.CephFsClientType == csiv1.KernelCephFsClient
+		poolSpec := cmp.Or(pool, &csiv1.NodePoolSpec{})
+		kernelMountOptions := utils.If(
+			poolSpec.KernelMountOptions != nil,
+			poolSpec.KernelMountOptions,
+			r.driver.Spec.KernelMountOptions,
+		)
+		fuseMountOptions := utils.If(
+			poolSpec.FuseMountOptions != nil,
+			poolSpec.FuseMountOptions,
+			r.driver.Spec.FuseMountOptions,
+		)
+		csiAddons := r.csiAddonsConfig()
+		metricsTLS := r.metricsTLSEnabled()
 
 		topology := r.isRbdDriver() && pluginSpec.Topology != nil
 		domainLabels := cmp.Or(pluginSpec.Topology, &csiv1.TopologySpec{}).DomainLabels
 		logRotationSpec := cmp.Or(r.driver.Spec.Log, &csiv1.LogSpec{}).Rotation
 		logRotationEnabled := logRotationSpec != nil
 
+		builtinVolumeMounts := []corev1.VolumeMount{utils.PluginDirVolumeMount}
+		if logRotationEnabled {
+			builtinVolumeMounts = append(builtinVolumeMounts, utils.LogsDirVolumeMount)
+		}
+		initContainers, err := generateExtraContainers(pluginSpec.InitContainers, builtinVolumeMounts)
+		if err != nil {
+			log.Error(err, "Failed to generate user defined init containers")
+			return err
+		}
+		extraContainers, err := generateExtraContainers(pluginSpec.ExtraContainers, builtinVolumeMounts)
+		if err != nil {
+			log.Error(err, "Failed to generate user defined containers")
+			return err
+		}
+
 		daemonSet.Spec = appsv1.DaemonSetSpec{
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{"app": appName},
@@ -1431,11 +2120,11 @@ func (r *driverReconcile) reconcileNodePluginDaemonSet() error {
 						maps.Copy(podLabels, pluginSpec.Labels)
 						podLabels["app"] = appName
 						if r.driver.Spec.Liveness != nil {
-							podLabels["contains"] = fmt.Sprintf("%s-metrics", appName)
+							podLabels["contains"] = fmt.Sprintf("%s-metrics", r.generateName("nodeplugin"))
 						}
 						return podLabels
 					}),
-					Annotations: maps.Clone(pluginSpec.Annotations),
+					Annotations: r.generatePodAnnotations(pluginSpec.Annotations, metricsCertificateComponent),
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: serviceAccountName,
@@ -1444,9 +2133,10 @@ func (r *driverReconcile) reconcileNodePluginDaemonSet() error {
 					HostPID:            r.isRbdOrNvemofDriver(),
 					// to use e.g. Rook orchestrated cluster, and mons' FQDN is
 					// resolved through k8s service, set dns policy to cluster first
-					DNSPolicy:   corev1.DNSClusterFirstWithHostNet,
-					Tolerations: pluginSpec.Tolerations,
-					Affinity:    pluginSpec.Affinity,
+					DNSPolicy:      r.podDNSPolicy(true),
+					Tolerations:    pluginSpec.Tolerations,
+					Affinity:       mergeNodeAffinity(pluginSpec.Affinity, nodeSelector),
+					InitContainers: initContainers,
 					Containers: utils.Call(func() []corev1.Container {
 						containers := []corev1.Container{
 							// Node Plugin Container
@@ -1462,8 +2152,8 @@ func (r *driverReconcile) reconcileNodePluginDaemonSet() error {
 									},
 									AllowPrivilegeEscalation: ptr.To(true),
 								},
-								Args: utils.DeleteZeroValues(
-									append(
+								Args: utils.MergeContainerArgs(
+									utils.DeleteZeroValues(
 										[]string{
 											utils.LogVerbosityContainerArg(logVerbosity),
 											utils.TypeContainerArg(string(r.driverType)),
@@ -1474,11 +2164,7 @@ func (r *driverReconcile) reconcileNodePluginDaemonSet() error {
 											utils.EndpointContainerArg,
 											utils.PidlimitContainerArg,
 											utils.If(forceKernelClient, utils.ForceCephKernelClientContainerArg, ""),
-											utils.If(
-												ptr.Deref(r.driver.Spec.DeployCsiAddons, false),
-												utils.CsiAddonsEndpointContainerArg,
-												"",
-											),
+											utils.If(csiAddons.node, utils.CsiAddonsEndpointContainerArg, ""),
 											utils.If(
 												r.isRbdOrNvemofDriver(),
 												utils.StagingPathContainerArg(kubeletDirPath),
@@ -1486,12 +2172,12 @@ func (r *driverReconcile) reconcileNodePluginDaemonSet() error {
 											),
 											utils.If(
 												r.isCephFsDriver(),
-												utils.KernelMountOptionsContainerArg(r.driver.Spec.KernelMountOptions),
+												utils.KernelMountOptionsContainerArg(kernelMountOptions),
 												"",
 											),
 											utils.If(
 												r.isCephFsDriver(),
-												utils.FuseMountOptionsContainerArg(r.driver.Spec.FuseMountOptions),
+												utils.FuseMountOptionsContainerArg(fuseMountOptions),
 												"",
 											),
 											utils.If(
@@ -1507,8 +2193,8 @@ func (r *driverReconcile) reconcileNodePluginDaemonSet() error {
 												"",
 											),
 										},
-										utils.GetExtraArgsForContainer(fmt.Sprintf("csi-%splugin", r.driverType), pluginSpec.ContainerExtraArgs)...,
 									),
+									utils.GetExtraArgsForContainer(fmt.Sprintf("csi-%splugin", r.driverType), pluginSpec.ContainerExtraArgs),
 								),
 								Env: []corev1.EnvVar{
 									utils.PodIpEnvVar,
@@ -1557,6 +2243,7 @@ func (r *driverReconcile) reconcileNodePluginDaemonSet() error {
 
 									return mounts
 								}),
+								LivenessProbe: r.pluginLivenessProbe(),
 								Resources: ptr.Deref(
 									pluginSpec.Resources.Plugin,
 									corev1.ResourceRequirements{},
@@ -1576,15 +2263,15 @@ func (r *driverReconcile) reconcileNodePluginDaemonSet() error {
 										Drop: []corev1.Capability{"All"},
 									},
 								},
-								Args: utils.DeleteZeroValues(
-									append(
+								Args: utils.MergeContainerArgs(
+									utils.DeleteZeroValues(
 										[]string{
 											utils.LogVerbosityContainerArg(logVerbosity),
 											utils.KubeletRegistrationPathContainerArg(kubeletDirPath, r.driver.Name),
 											utils.CsiAddressContainerArg,
 										},
-										utils.GetExtraArgsForContainer("driver-registrar", pluginSpec.ContainerExtraArgs)...,
 									),
+									utils.GetExtraArgsForContainer("driver-registrar", pluginSpec.ContainerExtraArgs),
 								),
 								VolumeMounts: []corev1.VolumeMount{
 									utils.PluginDirVolumeMount,
@@ -1608,8 +2295,8 @@ func (r *driverReconcile) reconcileNodePluginDaemonSet() error {
 										Drop: []corev1.Capability{"All"},
 									},
 								},
-								Args: utils.DeleteZeroValues(
-									append(
+								Args: utils.MergeContainerArgs(
+									utils.DeleteZeroValues(
 										[]string{
 											utils.TypeContainerArg("liveness"),
 											utils.EndpointContainerArg,
@@ -1618,11 +2305,11 @@ func (r *driverReconcile) reconcileNodePluginDaemonSet() error {
 											utils.PoolTimeContainerArg,
 											utils.TimeoutContainerArg(3),
 										},
-										utils.GetExtraArgsForContainer("liveness-prometheus", pluginSpec.ContainerExtraArgs)...,
 									),
+									utils.GetExtraArgsForContainer("liveness-prometheus", pluginSpec.ContainerExtraArgs),
 								),
 								Env: []corev1.EnvVar{
-									utils.PodIpEnvVar,
+									utils.If(metricsTLS, utils.LoopbackPodIpEnvVar, utils.PodIpEnvVar),
 								},
 								VolumeMounts: []corev1.VolumeMount{
 									utils.PluginDirVolumeMount,
@@ -1632,6 +2319,35 @@ func (r *driverReconcile) reconcileNodePluginDaemonSet() error {
 									corev1.ResourceRequirements{},
 								),
 							})
+							// Liveness Probe Sidecar Container, serving the health endpoint of the plugin liveness probe
+							containers = append(containers, corev1.Container{
+								Name:            "liveness-probe",
+								Image:           r.images["livenessprobe"],
+								ImagePullPolicy: imagePullPolicy,
+								Args: utils.MergeContainerArgs(
+									[]string{
+										utils.CsiAddressContainerArg,
+										utils.HttpEndpointContainerArg(r.livenessHealthPort()),
+										utils.ProbeTimeoutContainerArg(3),
+									},
+									utils.GetExtraArgsForContainer("liveness-probe", pluginSpec.ContainerExtraArgs),
+								),
+								VolumeMounts: []corev1.VolumeMount{
+									utils.PluginDirVolumeMount,
+								},
+								Resources: ptr.Deref(
+									pluginSpec.Resources.Liveness,
+									corev1.ResourceRequirements{},
+								),
+							})
+							// Metrics Proxy Sidecar Container, serving the liveness metrics over TLS
+							if metricsTLS {
+								containers = append(containers, r.metricsProxyContainer(
+									imagePullPolicy,
+									pluginSpec.ContainerExtraArgs,
+									pluginSpec.Resources.Liveness,
+								))
+							}
 						}
 						// CSI LogRotate Container
 						if logRotationEnabled {
@@ -1654,7 +2370,9 @@ func (r *driverReconcile) reconcileNodePluginDaemonSet() error {
 								},
 							})
 						}
-						return containers
+						// Add user defined containers at the end to make sure they
+						// can overwrite built in containers.
+						return mergeExtraContainers(containers, extraContainers)
 					}),
 					Volumes: utils.Call(func() []corev1.Volume {
 						volumes := []corev1.Volume{
@@ -1701,6 +2419,9 @@ func (r *driverReconcile) reconcileNodePluginDaemonSet() error {
 								utils.LogRotateDirVolumeName(r.driver.Name),
 							)
 						}
+						if metricsTLS {
+							volumes = append(volumes, utils.MetricsTlsVolume(r.certificateSecretName(metricsCertificateComponent)))
+						}
 						// Add user defined volumes at the end to make sure they
 						// can overwrite built in volumes.
 						volumes = utils.MapMergeByKey(
@@ -1719,52 +2440,132 @@ func (r *driverReconcile) reconcileNodePluginDaemonSet() error {
 			},
 		}
 
-		return nil
+		// Apply user defined container overrides and patches on top of the generated daemonset
+		applyContainerOverrides(&daemonSet.Spec.Template.Spec, pluginSpec.ContainerOverrides)
+		return applyWorkloadPatches(daemonSet, pluginSpec.Patches)
 	})
 
-	logCreateOrUpdateResult(log, "node plugin daemonset", daemonSet, opResult, err)
+	r.logCreateOrUpdateResult(log, "node plugin daemonset", daemonSet, opResult, err)
+	r.recordDaemonSetRolled(daemonSet, currentTemplate, opResult)
+	if err == nil {
+		r.recordContainerArgs(daemonSet.Name, &daemonSet.Spec.Template.Spec)
+	}
+	r.recordPluginWorkloadError(err)
 	return err
 }
 
-func (r *driverReconcile) reconcileLivenessService() error {
-	service := &corev1.Service{}
-	service.Namespace = r.driver.Namespace
-	service.Name = r.generateServiceName("liveness")
+// reconcileStatus updates the driver status based on the outcome of the reconciliation steps
+func (r *driverReconcile) reconcileStatus(ctx context.Context, errList []error) error {
+	status := r.driver.Status.DeepCopy()
+
+	r.updatePatchesAppliedCondition(errList)
+	r.updateContainerArgsStatus(errList)
+	r.driver.Status.Platform = r.platform
+	// Empty lists are omitted from the stored status, keep them nil to compare with it
+	r.driver.Status.KubeletDirPathMismatches = utils.If(
+		len(r.kubeletDirPathMismatches) > 0,
+		r.kubeletDirPathMismatches,
+		nil,
+	)
+	if r.certificateStatuses != nil {
+		r.driver.Status.Certificates = utils.If(len(r.certificateStatuses) > 0, r.certificateStatuses, nil)
+	}
 
-	log := r.log.WithValues("service", service.Name)
-	log.Info("Reconciling liveness service")
+	if reflect.DeepEqual(status, &r.driver.Status) {
+		return nil
+	}
+	if err := r.Status().Update(ctx, &r.driver); err != nil {
+		r.log.Error(err, "Failed to update driver status")
+		return err
+	}
+	return nil
+}
 
-	if r.driver.Spec.Liveness != nil {
-		opResult, err := ctrlutil.CreateOrUpdate(r.ctx, r.Client, service, func() error {
-			if err := ctrlutil.SetControllerReference(&r.driver, service, r.Scheme); err != nil {
-				log.Error(err, "Failed setting an owner reference on service")
-				return err
+func (r *driverReconcile) updatePatchesAppliedCondition(errList []error) {
+	nodePluginSpec := cmp.Or(r.driver.Spec.NodePlugin, &csiv1.NodePluginSpec{})
+	controllerPluginSpec := cmp.Or(r.driver.Spec.ControllerPlugin, &csiv1.ControllerPluginSpec{})
+	if len(nodePluginSpec.Patches) == 0 && len(controllerPluginSpec.Patches) == 0 {
+		meta.RemoveStatusCondition(&r.driver.Status.Conditions, csiv1.DriverPatchesAppliedCondition)
+	} else {
+		patchFailures := []string{}
+		for _, err := range errList {
+			var patchErr *workloadPatchError
+			if errors.As(err, &patchErr) {
+				patchFailures = append(patchFailures, patchErr.Error())
 			}
+		}
+		// Sub reconcilers are running concurrently, sorting the messages to keep the
+		// condition stable between reconcile iterations
+		slices.Sort(patchFailures)
+
+		condition := metav1.Condition{
+			Type:               csiv1.DriverPatchesAppliedCondition,
+			Status:             metav1.ConditionTrue,
+			Reason:             csiv1.DriverPatchesAppliedReason,
+			Message:            "All workload patches were applied successfully",
+			ObservedGeneration: r.driver.Generation,
+		}
+		if len(patchFailures) > 0 {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = csiv1.DriverPatchFailedReason
+			condition.Message = strings.Join(patchFailures, "; ")
+		} else if r.pluginWorkloadFailed {
+			// The patches of a workload that failed to reconcile are not known to be applied
+			condition.Status = metav1.ConditionUnknown
+			condition.Reason = csiv1.DriverWorkloadReconcileFailedReason
+			condition.Message = "Some patched workloads failed to reconcile"
+		}
+		meta.SetStatusCondition(&r.driver.Status.Conditions, condition)
+	}
+}
 
-			service.Spec = corev1.ServiceSpec{
-				Ports: []corev1.ServicePort{
-					{
-						Name:       "csi-http-metrics",
-						Port:       8080,
-						Protocol:   corev1.ProtocolTCP,
-						TargetPort: intstr.FromInt(r.driver.Spec.Liveness.MetricsPort),
-					},
-				},
+func (r *driverReconcile) updateContainerArgsStatus(errList []error) {
+	containerArgs := slices.Clone(r.containerArgs)
+
+	// Retain the last known arguments of workloads that failed to reconcile in this iteration
+	if len(errList) > 0 {
+		for _, entry := range r.driver.Status.ContainerArgs {
+			reported := slices.ContainsFunc(r.containerArgs, func(e csiv1.ContainerArgsStatus) bool {
+				return e.Workload == entry.Workload
+			})
+			if !reported {
+				containerArgs = append(containerArgs, entry)
 			}
+		}
+	}
 
-			return nil
-		})
+	// Sub reconcilers are running concurrently, sorting the entries to keep the
+	// status stable between reconcile iterations
+	slices.SortFunc(containerArgs, func(a, b csiv1.ContainerArgsStatus) int {
+		return cmp.Or(cmp.Compare(a.Workload, b.Workload), cmp.Compare(a.Container, b.Container))
+	})
+	r.driver.Status.ContainerArgs = containerArgs
+}
 
-		logCreateOrUpdateResult(log, "livness service", service, opResult, err)
-		return err
+// recordContainerArgs records the final arguments of the containers of a reconciled workload
+// recordPluginWorkloadError records that a plugin workload failed to reconcile, unless its patches
+// could not be applied, which is reported on its own
+func (r *driverReconcile) recordPluginWorkloadError(err error) {
+	var patchErr *workloadPatchError
+	if err == nil || errors.As(err, &patchErr) {
+		return
+	}
 
-	} else {
-		// Remove the liveness serive if livness setting removed from the driver's spec
-		if err := r.Delete(r.ctx, service); client.IgnoreNotFound(err) != nil {
-			log.Error(err, "Unable to delete liveness service")
-			return err
-		}
-		return nil
+	r.statusLock.Lock()
+	defer r.statusLock.Unlock()
+	r.pluginWorkloadFailed = true
+}
+
+func (r *driverReconcile) recordContainerArgs(workload string, podSpec *corev1.PodSpec) {
+	r.statusLock.Lock()
+	defer r.statusLock.Unlock()
+
+	for _, container := range slices.Concat(podSpec.InitContainers, podSpec.Containers) {
+		r.containerArgs = append(r.containerArgs, csiv1.ContainerArgsStatus{
+			Workload:  workload,
+			Container: container.Name,
+			Args:      container.Args,
+		})
 	}
 }
 
@@ -1792,6 +2593,67 @@ func (r *driverReconcile) generateName(suffix string) string {
 	return fmt.Sprintf("%s-%s", r.driver.Name, suffix)
 }
 
+// pluginLivenessProbe returns the liveness probe of the plugin container, querying the health endpoint
+// of the livenessprobe sidecar, which probes the plugin over its CSI socket, so that kubelet restarts a
+// hung plugin
+func (r *driverReconcile) pluginLivenessProbe() *corev1.Probe {
+	if r.driver.Spec.Liveness == nil {
+		return nil
+	}
+	return utils.LivenessHealthProbe(r.livenessHealthPort())
+}
+
+// metricsProxyContainer returns the kube-rbac-proxy sidecar serving the liveness metrics over TLS on the
+// metrics port of the pod IP, to scrapers authorized to get the metrics path. The liveness sidecar then
+// only listens on the loopback interface
+func (r *driverReconcile) metricsProxyContainer(
+	imagePullPolicy corev1.PullPolicy,
+	containerExtraArgs map[string][]string,
+	resources *corev1.ResourceRequirements,
+) corev1.Container {
+	metricsPort := r.driver.Spec.Liveness.MetricsPort
+	return corev1.Container{
+		Name:            "kube-rbac-proxy",
+		Image:           r.images["kube-rbac-proxy"],
+		ImagePullPolicy: imagePullPolicy,
+		Args: utils.MergeContainerArgs(
+			[]string{
+				utils.MetricsProxySecureListenAddressContainerArg(metricsPort),
+				utils.MetricsProxyUpstreamContainerArg(metricsPort),
+				utils.MetricsProxyAllowPathsContainerArg,
+				utils.MetricsProxyTlsCertFileContainerArg,
+				utils.MetricsProxyTlsKeyFileContainerArg,
+			},
+			utils.GetExtraArgsForContainer("kube-rbac-proxy", containerExtraArgs),
+		),
+		Env: []corev1.EnvVar{
+			utils.PodIpEnvVar,
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			utils.MetricsTlsVolumeMount,
+		},
+		Resources: ptr.Deref(resources, corev1.ResourceRequirements{}),
+	}
+}
+
+// livenessHealthPort returns the port of the health endpoint served by the livenessprobe sidecar
+func (r *driverReconcile) livenessHealthPort() int32 {
+	if port := ptr.Deref(r.driver.Spec.Liveness.HealthPort, 0); port != 0 {
+		return port
+	}
+	// The drivers use different ports, as the plugins may run on the host network
+	switch r.driverType {
+	case CephFsDriverType:
+		return 9818
+	case NfsDriverType:
+		return 9828
+	case NvmeofDriverType:
+		return 9838
+	default:
+		return 9808
+	}
+}
+
 // generateServiceName generates a service name by replacing all special characters with a hyphen
 // as required by Kubernetes for service name, This assumes that the name
 // from the generateName is RFC 1123 compliant.
@@ -1803,7 +2665,112 @@ func (r *driverReconcile) generateServiceName(suffix string) string {
 	return re.ReplaceAllString(name, "-")
 }
 
+// generateNodePluginDaemonSetName returns the name of the node plugin daemonset of the given
+// node pool, or the name of the default node plugin daemonset if pool is nil
+func (r *driverReconcile) generateNodePluginDaemonSetName(pool *csiv1.NodePoolSpec) string {
+	if pool == nil {
+		return r.generateName("nodeplugin")
+	}
+	return r.generateName(fmt.Sprintf("nodeplugin-pool-%s", pool.Name))
+}
+
+func (r *driverReconcile) generateCsiAddonsDaemonSetName(pool *csiv1.NodePoolSpec) string {
+	if pool == nil {
+		return r.generateName("nodeplugin-csi-addons")
+	}
+	return r.generateName(fmt.Sprintf("nodeplugin-pool-%s-csi-addons", pool.Name))
+}
+
+// appLabelValue returns the value of the app label selecting the pods of a workload, which is the
+// workload name, shortened and suffixed with a hash of it when it exceeds the label value limit
+func appLabelValue(workloadName string) string {
+	if len(workloadName) <= validation.LabelValueMaxLength {
+		return workloadName
+	}
+	hash := sha256.Sum256([]byte(workloadName))
+	suffix := hex.EncodeToString(hash[:])[:10]
+	return fmt.Sprintf("%s-%s", workloadName[:validation.LabelValueMaxLength-len(suffix)-1], suffix)
+}
+
+// recordEvent records an event on the driver, if an event recorder is available
+func (r *driverReconcile) recordEvent(eventType, reason, action, note string, args ...any) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(&r.driver, nil, eventType, reason, action, note, args...)
+	}
+}
+
+// recordDaemonSetRolled records an event when an update of a daemonset changed its pod template,
+// which rolls out the daemonset pods
+func (r *driverReconcile) recordDaemonSetRolled(
+	daemonSet *appsv1.DaemonSet,
+	previousTemplate *corev1.PodTemplateSpec,
+	opResult ctrlutil.OperationResult,
+) {
+	if opResult == ctrlutil.OperationResultUpdated &&
+		!equality.Semantic.DeepEqual(previousTemplate, &daemonSet.Spec.Template) {
+		r.recordEvent(
+			corev1.EventTypeNormal,
+			daemonSetRolledReason,
+			"Rollout",
+			"Rolling out daemonset %s with an updated pod template",
+			daemonSet.Name,
+		)
+	}
+}
+
+// recordInvalidImageSet records an event when an image set config map cannot be loaded
+func (r *driverReconcile) recordInvalidImageSet(imageSetCM *corev1.ConfigMap, err error) {
+	r.recordEvent(
+		corev1.EventTypeWarning,
+		invalidImageSetReason,
+		"LoadImageSet",
+		"Failed to load image set config map %s/%s: %v",
+		imageSetCM.Namespace,
+		imageSetCM.Name,
+		err,
+	)
+}
+
+// csiDriverChangedFields returns the names of the CSIDriver spec fields that differ between current and desired
+func csiDriverChangedFields(current, desired *storagev1.CSIDriverSpec) []string {
+	changed := []string{}
+	currentValue := reflect.ValueOf(current).Elem()
+	desiredValue := reflect.ValueOf(desired).Elem()
+	for i := range currentValue.NumField() {
+		if !reflect.DeepEqual(currentValue.Field(i).Interface(), desiredValue.Field(i).Interface()) {
+			jsonName, _, _ := strings.Cut(currentValue.Type().Field(i).Tag.Get("json"), ",")
+			changed = append(changed, jsonName)
+		}
+	}
+	return changed
+}
+
+// generatePodAnnotations returns the annotations of driver pods serving the certificates of the
+// given components
+func (r *driverReconcile) generatePodAnnotations(
+	annotations map[string]string,
+	certificateComponents ...string,
+) map[string]string {
+	podAnnotations := maps.Clone(annotations)
+	if r.requiredSCC != "" {
+		if podAnnotations == nil {
+			podAnnotations = map[string]string{}
+		}
+		if _, exists := podAnnotations[openShiftRequiredSCCAnnotationKey]; !exists {
+			podAnnotations[openShiftRequiredSCCAnnotationKey] = r.requiredSCC
+		}
+	}
+	if hash := r.certificatesHash(certificateComponents...); hash != "" {
+		if podAnnotations == nil {
+			podAnnotations = map[string]string{}
+		}
+		podAnnotations[certificatesHashAnnotationKey] = hash
+	}
+	return podAnnotations
+}
+
 func (r *driverReconcile) getControllerPluginReplicas(
+	ctx context.Context,
 	log logr.Logger,
 	specReplicas *int32,
 ) *int32 {
@@ -1813,7 +2780,7 @@ func (r *driverReconcile) getControllerPluginReplicas(
 
 	var replicas int32 = defaultControllerPluginReplicas
 	nodeList := &corev1.NodeList{}
-	if err := r.List(r.ctx, nodeList); err != nil {
+	if err := r.List(ctx, nodeList); err != nil {
 		log.Error(err, "Failed to list nodes for replica calculation")
 		return &replicas
 	}
@@ -1851,6 +2818,257 @@ func getControllerPluginPodAffinity(
 	return affinity
 }
 
+// generateExtraContainers renders user defined containers, mounting the requested built-in volumes
+// at the same path used by the driver containers
+func generateExtraContainers(
+	specs []csiv1.ExtraContainerSpec,
+	builtinVolumeMounts []corev1.VolumeMount,
+) ([]corev1.Container, error) {
+	containers := make([]corev1.Container, 0, len(specs))
+	for i := range specs {
+		container := *specs[i].Container.DeepCopy()
+		for _, name := range specs[i].BuiltinVolumeMounts {
+			index := slices.IndexFunc(builtinVolumeMounts, func(mount corev1.VolumeMount) bool {
+				return mount.Name == name
+			})
+			if index == -1 {
+				return nil, fmt.Errorf("built-in volume %q is not available for container %q", name, container.Name)
+			}
+			container.VolumeMounts = append(container.VolumeMounts, builtinVolumeMounts[index])
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+// mergeExtraContainers merges user defined containers into the driver containers,
+// a user defined container replaces a driver container with the same name
+func mergeExtraContainers(containers, extraContainers []corev1.Container) []corev1.Container {
+	for i := range extraContainers {
+		index := slices.IndexFunc(containers, func(container corev1.Container) bool {
+			return container.Name == extraContainers[i].Name
+		})
+		if index != -1 {
+			containers[index] = extraContainers[i]
+		} else {
+			containers = append(containers, extraContainers[i])
+		}
+	}
+	return containers
+}
+
+// applyContainerOverrides applies user defined per container overrides on top of the generated containers
+func applyContainerOverrides(podSpec *corev1.PodSpec, overrides map[string]csiv1.ContainerOverridesSpec) {
+	if len(overrides) == 0 {
+		return
+	}
+
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			container := &containers[i]
+			override, ok := overrides[container.Name]
+			if !ok {
+				continue
+			}
+			override = *override.DeepCopy()
+
+			container.Env = utils.MapMergeByKey(
+				container.Env,
+				override.Env,
+				func(env corev1.EnvVar) (corev1.EnvVar, bool) {
+					return env, env.Name != ""
+				},
+				func(env corev1.EnvVar) string {
+					return env.Name
+				},
+			)
+			container.EnvFrom = append(container.EnvFrom, override.EnvFrom...)
+			container.SecurityContext = cmp.Or(override.SecurityContext, container.SecurityContext)
+			container.LivenessProbe = cmp.Or(override.LivenessProbe, container.LivenessProbe)
+			container.ReadinessProbe = cmp.Or(override.ReadinessProbe, container.ReadinessProbe)
+			container.StartupProbe = cmp.Or(override.StartupProbe, container.StartupProbe)
+		}
+	}
+}
+
+// applyWorkloadPatches applies the given user defined patches, in order, on top of a generated
+// workload. Patches are not allowed to change the identity of the workload or its pod selector.
+func applyWorkloadPatches(obj client.Object, patches []csiv1.WorkloadPatchSpec) error {
+	name, namespace := obj.GetName(), obj.GetNamespace()
+	selector := getWorkloadSelector(obj).DeepCopy()
+
+	for i := range patches {
+		patchType := utils.If(
+			patches[i].Type == csiv1.JSON6902WorkloadPatchType,
+			types.JSONPatchType,
+			types.StrategicMergePatchType,
+		)
+		err := utils.ApplyPatch(obj, patchType, patches[i].Patch)
+		if err == nil && (obj.GetName() != name || obj.GetNamespace() != namespace) {
+			err = fmt.Errorf("patch must not modify the workload name or namespace")
+		}
+		if err == nil && !reflect.DeepEqual(getWorkloadSelector(obj), selector) {
+			err = fmt.Errorf("patch must not modify the workload selector")
+		}
+		if err != nil {
+			return &workloadPatchError{workload: name, index: i, err: err}
+		}
+	}
+	return nil
+}
+
+func getWorkloadSelector(obj client.Object) *metav1.LabelSelector {
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		return workload.Spec.Selector
+	case *appsv1.DaemonSet:
+		return workload.Spec.Selector
+	}
+	return nil
+}
+
+// mergeNodePoolSpec returns a copy of the node plugin spec with the node pool overrides applied,
+// or the node plugin spec itself if pool is nil
+func mergeNodePoolSpec(pluginSpec *csiv1.NodePluginSpec, pool *csiv1.NodePoolSpec) *csiv1.NodePluginSpec {
+	if pool == nil {
+		return pluginSpec
+	}
+
+	pool = pool.DeepCopy()
+	merged := pluginSpec.DeepCopy()
+	merged.KubeletDirPath = cmp.Or(pool.KubeletDirPath, merged.KubeletDirPath)
+	merged.UpdateStrategy = cmp.Or(pool.UpdateStrategy, merged.UpdateStrategy)
+	if pool.Tolerations != nil {
+		merged.Tolerations = pool.Tolerations
+	}
+	merged.Resources.Registrar = cmp.Or(pool.Resources.Registrar, merged.Resources.Registrar)
+	merged.Resources.Liveness = cmp.Or(pool.Resources.Liveness, merged.Resources.Liveness)
+	merged.Resources.Addons = cmp.Or(pool.Resources.Addons, merged.Resources.Addons)
+	merged.Resources.LogRotator = cmp.Or(pool.Resources.LogRotator, merged.Resources.LogRotator)
+	merged.Resources.Plugin = cmp.Or(pool.Resources.Plugin, merged.Resources.Plugin)
+	return merged
+}
+
+// nodePoolNodeSelector returns a node selector matching the nodes of the pool at the given index
+// that are not matched by any of the preceding pools, making sure every node is matched by a single
+// pool. A negative index returns a node selector matching the nodes not matched by any of the pools,
+// or nil if there are no pools.
+func nodePoolNodeSelector(pools []csiv1.NodePoolSpec, index int) (*corev1.NodeSelector, error) {
+	if index < 0 && len(pools) == 0 {
+		return nil, nil
+	}
+
+	// Build the selector in disjunctive normal form, starting with the requirements of the
+	// selected pool and adding the negation of the requirements of every excluded pool.
+	baseTerm := []corev1.NodeSelectorRequirement{}
+	excludedPools := pools
+	if index >= 0 {
+		for _, key := range slices.Sorted(maps.Keys(pools[index].NodeSelector)) {
+			baseTerm = append(baseTerm, corev1.NodeSelectorRequirement{
+				Key:      key,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   []string{pools[index].NodeSelector[key]},
+			})
+		}
+		excludedPools = pools[:index]
+	}
+
+	terms := [][]corev1.NodeSelectorRequirement{baseTerm}
+	for i := range excludedPools {
+		nodeSelector := excludedPools[i].NodeSelector
+		nextTerms := [][]corev1.NodeSelectorRequirement{}
+		for _, term := range terms {
+			// Terms requiring a different value for one of the pool labels already exclude the pool
+			excluded := slices.ContainsFunc(term, func(req corev1.NodeSelectorRequirement) bool {
+				value, ok := nodeSelector[req.Key]
+				return ok && req.Operator == corev1.NodeSelectorOpIn && req.Values[0] != value
+			})
+			if excluded {
+				nextTerms = append(nextTerms, term)
+				continue
+			}
+			for _, key := range slices.Sorted(maps.Keys(nodeSelector)) {
+				// Terms requiring the same value for a pool label can't exclude the pool through it
+				required := slices.ContainsFunc(term, func(req corev1.NodeSelectorRequirement) bool {
+					return req.Key == key && req.Operator == corev1.NodeSelectorOpIn
+				})
+				if required {
+					continue
+				}
+				nextTerms = append(nextTerms, append(slices.Clone(term), corev1.NodeSelectorRequirement{
+					Key:      key,
+					Operator: corev1.NodeSelectorOpNotIn,
+					Values:   []string{nodeSelector[key]},
+				}))
+			}
+		}
+		terms = nextTerms
+		if len(terms) > maxNodePoolSelectorTerms {
+			return nil, fmt.Errorf("node pool selectors are too complex to be made mutually exclusive")
+		}
+	}
+
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("node pool %q does not match any node not already matched by preceding pools",
+			pools[index].Name)
+	}
+
+	return &corev1.NodeSelector{
+		NodeSelectorTerms: utils.MapSlice(terms, func(reqs []corev1.NodeSelectorRequirement) corev1.NodeSelectorTerm {
+			return corev1.NodeSelectorTerm{MatchExpressions: reqs}
+		}),
+	}, nil
+}
+
+// mergeNodeAffinity returns a copy of the affinity with a required node affinity restricted to nodes
+// matched by both the affinity and the given node selector
+func mergeNodeAffinity(affinity *corev1.Affinity, nodeSelector *corev1.NodeSelector) *corev1.Affinity {
+	if nodeSelector == nil {
+		return affinity
+	}
+
+	merged := &corev1.Affinity{}
+	if affinity != nil {
+		affinity.DeepCopyInto(merged)
+	}
+	if merged.NodeAffinity == nil {
+		merged.NodeAffinity = &corev1.NodeAffinity{}
+	}
+
+	required := merged.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) == 0 {
+		merged.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = nodeSelector.DeepCopy()
+		return merged
+	}
+
+	// Node selector terms are ORed, the intersection of both selectors is the cross product of their terms
+	terms := []corev1.NodeSelectorTerm{}
+	for i := range required.NodeSelectorTerms {
+		for j := range nodeSelector.NodeSelectorTerms {
+			term := required.NodeSelectorTerms[i].DeepCopy()
+			term.MatchExpressions = append(term.MatchExpressions, nodeSelector.NodeSelectorTerms[j].MatchExpressions...)
+			terms = append(terms, *term)
+		}
+	}
+	required.NodeSelectorTerms = terms
+	return merged
+}
+
+// logCreateOrUpdateResult logs the outcome of creating or updating a driver owned resource, counting
+// updates that corrected a drift from the desired state
+func (r *driverReconcile) logCreateOrUpdateResult(
+	log logr.Logger,
+	subject string,
+	obj client.Object,
+	opRes ctrlutil.OperationResult,
+	err error,
+) {
+	if err == nil {
+		r.recordAppliedState(obj, opRes)
+	}
+	logCreateOrUpdateResult(log, subject, obj, opRes, err)
+}
+
 func logCreateOrUpdateResult(
 	log logr.Logger,
 	subject string,
@@ -1931,6 +3149,12 @@ func mergeDriverSpecs(dest, src *csiv1.DriverSpec) {
 			if dest.ImagePullPolicy == "" {
 				dest.ImagePullPolicy = src.ImagePullPolicy
 			}
+			if dest.ExtraContainers == nil {
+				dest.ExtraContainers = src.ExtraContainers
+			}
+			if dest.InitContainers == nil {
+				dest.InitContainers = src.InitContainers
+			}
 			if dest.UpdateStrategy == nil {
 				dest.UpdateStrategy = src.UpdateStrategy
 			}
@@ -1958,6 +3182,15 @@ func mergeDriverSpecs(dest, src *csiv1.DriverSpec) {
 			if dest.ContainerExtraArgs == nil {
 				dest.ContainerExtraArgs = src.ContainerExtraArgs
 			}
+			if dest.ContainerOverrides == nil {
+				dest.ContainerOverrides = src.ContainerOverrides
+			}
+			if dest.NodePools == nil {
+				dest.NodePools = src.NodePools
+			}
+			if dest.Patches == nil {
+				dest.Patches = src.Patches
+			}
 		}
 	}
 	if src.ControllerPlugin != nil {
@@ -1989,6 +3222,12 @@ func mergeDriverSpecs(dest, src *csiv1.DriverSpec) {
 			if dest.ImagePullPolicy == "" {
 				dest.ImagePullPolicy = src.ImagePullPolicy
 			}
+			if dest.ExtraContainers == nil {
+				dest.ExtraContainers = src.ExtraContainers
+			}
+			if dest.InitContainers == nil {
+				dest.InitContainers = src.InitContainers
+			}
 			if dest.Replicas == nil {
 				dest.Replicas = src.Replicas
 			}
@@ -2025,11 +3264,41 @@ func mergeDriverSpecs(dest, src *csiv1.DriverSpec) {
 			if dest.ContainerExtraArgs == nil {
 				dest.ContainerExtraArgs = src.ContainerExtraArgs
 			}
+			if dest.ContainerOverrides == nil {
+				dest.ContainerOverrides = src.ContainerOverrides
+			}
+			if dest.Patches == nil {
+				dest.Patches = src.Patches
+			}
 		}
 	}
 	if dest.AttachRequired == nil {
 		dest.AttachRequired = src.AttachRequired
 	}
+	if dest.PodInfoOnMount == nil {
+		dest.PodInfoOnMount = src.PodInfoOnMount
+	}
+	if dest.SeLinuxMount == nil {
+		dest.SeLinuxMount = src.SeLinuxMount
+	}
+	if dest.TokenRequests == nil {
+		dest.TokenRequests = src.TokenRequests
+	}
+	if dest.RequiresRepublish == nil {
+		dest.RequiresRepublish = src.RequiresRepublish
+	}
+	if dest.StorageCapacity == nil {
+		dest.StorageCapacity = src.StorageCapacity
+	}
+	if dest.VolumeLifecycleModes == nil {
+		dest.VolumeLifecycleModes = src.VolumeLifecycleModes
+	}
+	if dest.NodeAllocatableUpdatePeriodSeconds == nil {
+		dest.NodeAllocatableUpdatePeriodSeconds = src.NodeAllocatableUpdatePeriodSeconds
+	}
+	if dest.VolumeHealth == nil {
+		dest.VolumeHealth = src.VolumeHealth
+	}
 	if dest.Liveness == nil {
 		dest.Liveness = src.Liveness
 	}
@@ -2039,6 +3308,18 @@ func mergeDriverSpecs(dest, src *csiv1.DriverSpec) {
 	if dest.DeployCsiAddons == nil {
 		dest.DeployCsiAddons = src.DeployCsiAddons
 	}
+	if dest.CsiAddons == nil {
+		dest.CsiAddons = src.CsiAddons
+	}
+	if dest.Monitoring == nil {
+		dest.Monitoring = src.Monitoring
+	}
+	if dest.SnapshotMetadata == nil {
+		dest.SnapshotMetadata = src.SnapshotMetadata
+	}
+	if dest.Certificates == nil {
+		dest.Certificates = src.Certificates
+	}
 	if dest.KernelMountOptions == nil {
 		dest.KernelMountOptions = src.KernelMountOptions
 	}
@@ -18,20 +18,40 @@ package controller
 
 import (
 	"context"
+	"encoding/base64"
+	stderrors "errors"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	csiv1 "github.com/ceph/ceph-csi-operator/api/v1"
+	"github.com/ceph/ceph-csi-operator/internal/utils"
 )
 
 var _ = Describe("Driver Controller", func() {
@@ -81,8 +101,14 @@ var _ = Describe("Driver Controller", func() {
 				NamespacedName: typeNamespacedName,
 			})
 			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+
+			By("Recording the final container arguments in the driver status")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, driver)).To(Succeed())
+			Expect(driver.Status.ContainerArgs).To(ContainElement(And(
+				HaveField("Workload", resourceName+"-ctrlplugin"),
+				HaveField("Container", "csi-provisioner"),
+				HaveField("Args", ContainElement("--extra-create-metadata=true")),
+			)))
 		})
 	})
 
@@ -105,12 +131,12 @@ var _ = Describe("Driver Controller", func() {
 
 		It("should return specReplicas when explicitly set", func() {
 			specReplicas := ptr.To(int32(5))
-			result := reconciler.getControllerPluginReplicas(log, specReplicas)
+			result := reconciler.getControllerPluginReplicas(reconciler.ctx, log, specReplicas)
 			Expect(result).To(Equal(specReplicas))
 		})
 
 		It("should return default replicas when no nodes exist", func() {
-			result := reconciler.getControllerPluginReplicas(log, nil)
+			result := reconciler.getControllerPluginReplicas(reconciler.ctx, log, nil)
 			Expect(*result).To(Equal(defaultControllerPluginReplicas))
 		})
 
@@ -125,7 +151,7 @@ var _ = Describe("Driver Controller", func() {
 				Expect(k8sClient.Delete(context.Background(), node)).To(Succeed())
 			}()
 
-			result := reconciler.getControllerPluginReplicas(log, nil)
+			result := reconciler.getControllerPluginReplicas(reconciler.ctx, log, nil)
 			Expect(*result).To(Equal(int32(1)))
 		})
 
@@ -145,7 +171,7 @@ var _ = Describe("Driver Controller", func() {
 				}
 			}()
 
-			result := reconciler.getControllerPluginReplicas(log, nil)
+			result := reconciler.getControllerPluginReplicas(reconciler.ctx, log, nil)
 			Expect(*result).To(Equal(defaultControllerPluginReplicas))
 		})
 
@@ -161,9 +187,1854 @@ var _ = Describe("Driver Controller", func() {
 			}()
 
 			specReplicas := ptr.To(int32(3))
-			result := reconciler.getControllerPluginReplicas(log, specReplicas)
+			result := reconciler.getControllerPluginReplicas(reconciler.ctx, log, specReplicas)
 			Expect(result).To(Equal(specReplicas))
 			Expect(*result).To(Equal(int32(3)))
 		})
 	})
+
+	Context("observeReconcileStep", func() {
+		It("should run the step with the context of its span", func() {
+			otel.SetTracerProvider(sdktrace.NewTracerProvider())
+			DeferCleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+			ctx, reconcileSpan := utils.StartSpan(context.Background(), "Driver.Reconcile")
+			defer reconcileSpan.End()
+
+			var stepSpan trace.Span
+			Expect(observeReconcileStep(ctx, "test", func(stepCtx context.Context) error {
+				stepSpan = trace.SpanFromContext(stepCtx)
+				return nil
+			})()).To(Succeed())
+
+			readOnlySpan, ok := stepSpan.(sdktrace.ReadOnlySpan)
+			Expect(ok).To(BeTrue())
+			Expect(readOnlySpan.Name()).To(Equal("reconcile.test"))
+			Expect(readOnlySpan.Parent().SpanID()).To(Equal(reconcileSpan.SpanContext().SpanID()))
+		})
+	})
+
+	Context("applyWorkloadPatches", func() {
+		var deploy *appsv1.Deployment
+
+		BeforeEach(func() {
+			deploy = &appsv1.Deployment{}
+			deploy.Name = "test.rbd.csi.ceph.com-ctrlplugin"
+			deploy.Namespace = "default"
+			deploy.Spec.Selector = &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": deploy.Name},
+			}
+			deploy.Spec.Template.Spec.Containers = []corev1.Container{
+				{Name: "csi-rbdplugin", Image: "quay.io/cephcsi/cephcsi:v3.14.0"},
+			}
+		})
+
+		It("should apply patches in order", func() {
+			patches := []csiv1.WorkloadPatchSpec{
+				{
+					Type:  csiv1.StrategicMergeWorkloadPatchType,
+					Patch: `{"spec":{"template":{"spec":{"containers":[{"name":"csi-rbdplugin","image":"custom:v1"}]}}}}`,
+				},
+				{
+					Type:  csiv1.JSON6902WorkloadPatchType,
+					Patch: `[{"op":"replace","path":"/spec/template/spec/containers/0/image","value":"custom:v2"}]`,
+				},
+			}
+			Expect(applyWorkloadPatches(deploy, patches)).To(Succeed())
+			Expect(deploy.Spec.Template.Spec.Containers[0].Image).To(Equal("custom:v2"))
+		})
+
+		It("should reject patches modifying the selector", func() {
+			patches := []csiv1.WorkloadPatchSpec{{
+				Type:  csiv1.StrategicMergeWorkloadPatchType,
+				Patch: `{"spec":{"selector":{"matchLabels":{"app":"other"}}}}`,
+			}}
+			err := applyWorkloadPatches(deploy, patches)
+			var patchErr *workloadPatchError
+			Expect(stderrors.As(err, &patchErr)).To(BeTrue())
+			Expect(patchErr.index).To(Equal(0))
+		})
+
+		It("should report the index of a patch that no longer applies", func() {
+			patches := []csiv1.WorkloadPatchSpec{
+				{
+					Type:  csiv1.StrategicMergeWorkloadPatchType,
+					Patch: `{"metadata":{"labels":{"foo":"bar"}}}`,
+				},
+				{
+					Type:  csiv1.JSON6902WorkloadPatchType,
+					Patch: `[{"op":"remove","path":"/spec/template/spec/containers/3"}]`,
+				},
+			}
+			err := applyWorkloadPatches(deploy, patches)
+			var patchErr *workloadPatchError
+			Expect(stderrors.As(err, &patchErr)).To(BeTrue())
+			Expect(patchErr.index).To(Equal(1))
+			Expect(patchErr.workload).To(Equal(deploy.Name))
+		})
+	})
+
+	Context("updatePatchesAppliedCondition", func() {
+		var r *driverReconcile
+
+		BeforeEach(func() {
+			r = &driverReconcile{}
+			r.driver.Generation = 2
+			r.driver.Spec.NodePlugin = &csiv1.NodePluginSpec{
+				Patches: []csiv1.WorkloadPatchSpec{{
+					Type:  csiv1.StrategicMergeWorkloadPatchType,
+					Patch: `{"metadata":{"labels":{"foo":"bar"}}}`,
+				}},
+			}
+		})
+
+		It("should report the patches as applied once the patched workloads reconciled", func() {
+			r.updatePatchesAppliedCondition([]error{stderrors.New("failed to reconcile the PrometheusRule")})
+			Expect(meta.FindStatusCondition(r.driver.Status.Conditions, csiv1.DriverPatchesAppliedCondition)).To(And(
+				HaveField("Status", metav1.ConditionTrue),
+				HaveField("Reason", csiv1.DriverPatchesAppliedReason),
+			))
+		})
+
+		It("should not report the patches as applied when a patched workload failed to reconcile", func() {
+			err := stderrors.New("the API server is unavailable")
+			r.recordPluginWorkloadError(err)
+			r.updatePatchesAppliedCondition([]error{err})
+			Expect(meta.FindStatusCondition(r.driver.Status.Conditions, csiv1.DriverPatchesAppliedCondition)).To(And(
+				HaveField("Status", metav1.ConditionUnknown),
+				HaveField("Reason", csiv1.DriverWorkloadReconcileFailedReason),
+			))
+		})
+
+		It("should report the failing patches", func() {
+			err := &workloadPatchError{workload: "nodeplugin", err: stderrors.New("invalid patch")}
+			r.recordPluginWorkloadError(err)
+			r.updatePatchesAppliedCondition([]error{err})
+			Expect(meta.FindStatusCondition(r.driver.Status.Conditions, csiv1.DriverPatchesAppliedCondition)).To(And(
+				HaveField("Status", metav1.ConditionFalse),
+				HaveField("Reason", csiv1.DriverPatchFailedReason),
+			))
+		})
+	})
+
+	Context("applyContainerOverrides", func() {
+		It("should merge env vars and replace security context and probes", func() {
+			podSpec := &corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "csi-rbdplugin",
+						Env: []corev1.EnvVar{
+							{Name: "POD_IP", Value: "1.1.1.1"},
+							{Name: "NODE_ID", Value: "node"},
+						},
+						SecurityContext: &corev1.SecurityContext{Privileged: ptr.To(true)},
+					},
+					{
+						Name:          "liveness-prometheus",
+						LivenessProbe: &corev1.Probe{InitialDelaySeconds: 10},
+					},
+				},
+			}
+			overrides := map[string]csiv1.ContainerOverridesSpec{
+				"csi-rbdplugin": {
+					Env: []corev1.EnvVar{
+						{Name: "NODE_ID", Value: "other"},
+						{Name: "GODEBUG", Value: "madvdontneed=1"},
+					},
+					EnvFrom: []corev1.EnvFromSource{{Prefix: "CEPH_"}},
+					SecurityContext: &corev1.SecurityContext{
+						Privileged:             ptr.To(true),
+						ReadOnlyRootFilesystem: ptr.To(true),
+					},
+				},
+				"liveness-prometheus": {
+					ReadinessProbe: &corev1.Probe{PeriodSeconds: 5},
+				},
+			}
+
+			applyContainerOverrides(podSpec, overrides)
+
+			plugin := podSpec.Containers[0]
+			Expect(plugin.Env).To(Equal([]corev1.EnvVar{
+				{Name: "POD_IP", Value: "1.1.1.1"},
+				{Name: "NODE_ID", Value: "other"},
+				{Name: "GODEBUG", Value: "madvdontneed=1"},
+			}))
+			Expect(plugin.EnvFrom).To(HaveLen(1))
+			Expect(plugin.SecurityContext.ReadOnlyRootFilesystem).To(Equal(ptr.To(true)))
+
+			liveness := podSpec.Containers[1]
+			Expect(liveness.LivenessProbe.InitialDelaySeconds).To(Equal(int32(10)))
+			Expect(liveness.ReadinessProbe.PeriodSeconds).To(Equal(int32(5)))
+		})
+	})
+
+	Context("generateExtraContainers", func() {
+		builtinVolumeMounts := []corev1.VolumeMount{
+			{Name: "socket-dir", MountPath: "/csi"},
+		}
+
+		It("should mount requested built-in volumes", func() {
+			specs := []csiv1.ExtraContainerSpec{{
+				Container: corev1.Container{
+					Name:         "sidecar",
+					VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+				},
+				BuiltinVolumeMounts: []string{"socket-dir"},
+			}}
+			containers, err := generateExtraContainers(specs, builtinVolumeMounts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(containers).To(HaveLen(1))
+			Expect(containers[0].VolumeMounts).To(Equal([]corev1.VolumeMount{
+				{Name: "data", MountPath: "/data"},
+				{Name: "socket-dir", MountPath: "/csi"},
+			}))
+			Expect(specs[0].Container.VolumeMounts).To(HaveLen(1))
+		})
+
+		It("should fail when a built-in volume is not available", func() {
+			specs := []csiv1.ExtraContainerSpec{{
+				Container:           corev1.Container{Name: "sidecar"},
+				BuiltinVolumeMounts: []string{"plugin-dir"},
+			}}
+			_, err := generateExtraContainers(specs, builtinVolumeMounts)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should replace driver containers with the same name", func() {
+			containers := mergeExtraContainers(
+				[]corev1.Container{{Name: "csi-rbdplugin"}, {Name: "csi-provisioner"}},
+				[]corev1.Container{{Name: "csi-provisioner", Image: "custom"}, {Name: "sidecar"}},
+			)
+			Expect(containers).To(Equal([]corev1.Container{
+				{Name: "csi-rbdplugin"},
+				{Name: "csi-provisioner", Image: "custom"},
+				{Name: "sidecar"},
+			}))
+		})
+	})
+
+	Context("nodePoolNodeSelector", func() {
+		pools := []csiv1.NodePoolSpec{
+			{Name: "k3s", NodeSelector: map[string]string{"distro": "k3s"}},
+			{Name: "edge", NodeSelector: map[string]string{"zone": "edge", "arch": "arm64"}},
+		}
+
+		It("should return nil for the default daemonset when there are no pools", func() {
+			nodeSelector, err := nodePoolNodeSelector(nil, -1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nodeSelector).To(BeNil())
+		})
+
+		It("should match the first pool's nodes", func() {
+			nodeSelector, err := nodePoolNodeSelector(pools, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nodeSelector.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "distro", Operator: corev1.NodeSelectorOpIn, Values: []string{"k3s"}},
+				},
+			}}))
+		})
+
+		It("should exclude nodes of preceding pools", func() {
+			nodeSelector, err := nodePoolNodeSelector(pools, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nodeSelector.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "arch", Operator: corev1.NodeSelectorOpIn, Values: []string{"arm64"}},
+					{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"edge"}},
+					{Key: "distro", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"k3s"}},
+				},
+			}}))
+		})
+
+		It("should exclude nodes of all pools from the default daemonset", func() {
+			nodeSelector, err := nodePoolNodeSelector(pools, -1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nodeSelector.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{
+				{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "distro", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"k3s"}},
+						{Key: "arch", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"arm64"}},
+					},
+				},
+				{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "distro", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"k3s"}},
+						{Key: "zone", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"edge"}},
+					},
+				},
+			}))
+		})
+
+		It("should skip exclusions implied by the pool's own labels", func() {
+			pools := []csiv1.NodePoolSpec{
+				{Name: "a", NodeSelector: map[string]string{"zone": "a"}},
+				{Name: "b", NodeSelector: map[string]string{"zone": "b"}},
+			}
+			nodeSelector, err := nodePoolNodeSelector(pools, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nodeSelector.NodeSelectorTerms).To(HaveLen(1))
+			Expect(nodeSelector.NodeSelectorTerms[0].MatchExpressions).To(HaveLen(1))
+		})
+
+		It("should fail for a pool shadowed by a preceding pool", func() {
+			pools := []csiv1.NodePoolSpec{
+				{Name: "a", NodeSelector: map[string]string{"zone": "a"}},
+				{Name: "b", NodeSelector: map[string]string{"zone": "a", "arch": "arm64"}},
+			}
+			_, err := nodePoolNodeSelector(pools, 1)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("mergeNodeAffinity", func() {
+		nodeSelector := &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}},
+				}},
+			},
+		}
+
+		It("should keep the affinity as is without a node selector", func() {
+			affinity := &corev1.Affinity{}
+			Expect(mergeNodeAffinity(affinity, nil)).To(BeIdenticalTo(affinity))
+		})
+
+		It("should intersect the user node affinity with the node selector", func() {
+			affinity := &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{
+							{MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: "role", Operator: corev1.NodeSelectorOpIn, Values: []string{"storage"}},
+							}},
+							{MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: "role", Operator: corev1.NodeSelectorOpIn, Values: []string{"compute"}},
+							}},
+						},
+					},
+				},
+			}
+			merged := mergeNodeAffinity(affinity, nodeSelector)
+			terms := merged.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+			Expect(terms).To(HaveLen(2))
+			for _, term := range terms {
+				Expect(term.MatchExpressions).To(HaveLen(2))
+				Expect(term.MatchExpressions[1].Key).To(Equal("zone"))
+			}
+			Expect(affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].
+				MatchExpressions).To(HaveLen(1))
+		})
+	})
+
+	Context("When reconciling a resource with node pools", func() {
+		const resourceName = "pools.cephfs.csi.ceph.com"
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		It("should deploy a daemonset per node pool and prune removed pools", func() {
+			driver := &csiv1.Driver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: csiv1.DriverSpec{
+					DeployCsiAddons:    ptr.To(true),
+					KernelMountOptions: map[string]string{"ms_mode": "crc"},
+					NodePlugin: &csiv1.NodePluginSpec{
+						NodePools: []csiv1.NodePoolSpec{{
+							Name:               "k3s",
+							NodeSelector:       map[string]string{"node.kubernetes.io/instance-type": "k3s"},
+							KubeletDirPath:     "/var/lib/rancher/k3s/agent/kubelet",
+							KernelMountOptions: map[string]string{"ms_mode": "secure"},
+						}},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, driver)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, driver)).To(Succeed())
+			}()
+
+			controllerReconciler := &DriverReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			poolDaemonSet := &appsv1.DaemonSet{}
+			poolDaemonSetKey := types.NamespacedName{
+				Name:      resourceName + "-nodeplugin-pool-k3s",
+				Namespace: "default",
+			}
+			Expect(k8sClient.Get(ctx, poolDaemonSetKey, poolDaemonSet)).To(Succeed())
+			Expect(poolDaemonSet.Labels).To(HaveKeyWithValue(nodePoolLabelKey, "k3s"))
+			Expect(poolDaemonSet.Spec.Template.Spec.Volumes).To(ContainElement(
+				HaveField("VolumeSource.HostPath.Path", HavePrefix("/var/lib/rancher/k3s/agent/kubelet")),
+			))
+			Expect(poolDaemonSet.Spec.Template.Spec.Containers).To(ContainElement(And(
+				HaveField("Name", "csi-cephfsplugin"),
+				HaveField("Args", ContainElement("--kernelmountoptions=ms_mode=secure")),
+			)))
+
+			defaultDaemonSet := &appsv1.DaemonSet{}
+			defaultDaemonSetKey := types.NamespacedName{Name: resourceName + "-nodeplugin", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, defaultDaemonSetKey, defaultDaemonSet)).To(Succeed())
+			Expect(defaultDaemonSet.Spec.Template.Spec.Affinity.NodeAffinity).NotTo(BeNil())
+			Expect(defaultDaemonSet.Spec.Template.Spec.Containers).To(ContainElement(And(
+				HaveField("Name", "csi-cephfsplugin"),
+				HaveField("Args", ContainElement("--kernelmountoptions=ms_mode=crc")),
+			)))
+
+			By("Deploying a CSI-Addons daemonset per node pool")
+			poolCsiAddonsDaemonSet := &appsv1.DaemonSet{}
+			poolCsiAddonsDaemonSetKey := types.NamespacedName{
+				Name:      resourceName + "-nodeplugin-pool-k3s-csi-addons",
+				Namespace: "default",
+			}
+			Expect(k8sClient.Get(ctx, poolCsiAddonsDaemonSetKey, poolCsiAddonsDaemonSet)).To(Succeed())
+			Expect(poolCsiAddonsDaemonSet.Labels).To(HaveKeyWithValue(csiAddonsNodePoolLabelKey, "k3s"))
+			Expect(poolCsiAddonsDaemonSet.Spec.Template.Spec.Affinity).
+				To(Equal(poolDaemonSet.Spec.Template.Spec.Affinity))
+			Expect(poolCsiAddonsDaemonSet.Spec.Template.Spec.Volumes).To(ContainElement(
+				HaveField("VolumeSource.HostPath.Path", HavePrefix("/var/lib/rancher/k3s/agent/kubelet")),
+			))
+
+			defaultCsiAddonsDaemonSet := &appsv1.DaemonSet{}
+			defaultCsiAddonsDaemonSetKey := types.NamespacedName{
+				Name:      resourceName + "-nodeplugin-csi-addons",
+				Namespace: "default",
+			}
+			Expect(k8sClient.Get(ctx, defaultCsiAddonsDaemonSetKey, defaultCsiAddonsDaemonSet)).To(Succeed())
+			Expect(defaultCsiAddonsDaemonSet.Spec.Template.Spec.Affinity).
+				To(Equal(defaultDaemonSet.Spec.Template.Spec.Affinity))
+
+			np := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, defaultCsiAddonsDaemonSetKey, np)).To(Succeed())
+			Expect(np.Spec.PodSelector.MatchExpressions).To(ConsistOf(HaveField(
+				"Values",
+				ConsistOf(defaultCsiAddonsDaemonSetKey.Name, poolCsiAddonsDaemonSetKey.Name),
+			)))
+
+			By("Removing the node pool")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, driver)).To(Succeed())
+			driver.Spec.NodePlugin.NodePools = nil
+			Expect(k8sClient.Update(ctx, driver)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, poolDaemonSetKey, poolDaemonSet))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, poolCsiAddonsDaemonSetKey, poolCsiAddonsDaemonSet))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, defaultCsiAddonsDaemonSetKey, defaultCsiAddonsDaemonSet)).To(Succeed())
+		})
+
+		It("should keep the app label of node pools with long names within the label value limit", func() {
+			const driverName = "rbd.csi.ceph.com"
+			const poolName = "a-twenty-chars-pool0"
+			Expect(poolName).To(HaveLen(20))
+
+			driver := &csiv1.Driver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      driverName,
+					Namespace: "default",
+				},
+				Spec: csiv1.DriverSpec{
+					DeployCsiAddons: ptr.To(true),
+					NodePlugin: &csiv1.NodePluginSpec{
+						NodePools: []csiv1.NodePoolSpec{{
+							Name:         poolName,
+							NodeSelector: map[string]string{"node.kubernetes.io/instance-type": "large"},
+						}},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, driver)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, driver)).To(Succeed())
+			}()
+
+			controllerReconciler := &DriverReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: driverName, Namespace: "default"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			poolCsiAddonsDaemonSet := &appsv1.DaemonSet{}
+			poolCsiAddonsDaemonSetKey := types.NamespacedName{
+				Name:      driverName + "-nodeplugin-pool-" + poolName + "-csi-addons",
+				Namespace: "default",
+			}
+			Expect(len(poolCsiAddonsDaemonSetKey.Name)).To(BeNumerically(">", validation.LabelValueMaxLength))
+			Expect(k8sClient.Get(ctx, poolCsiAddonsDaemonSetKey, poolCsiAddonsDaemonSet)).To(Succeed())
+
+			appLabel := poolCsiAddonsDaemonSet.Spec.Selector.MatchLabels["app"]
+			Expect(validation.IsValidLabelValue(appLabel)).To(BeEmpty())
+			Expect(appLabel).To(HavePrefix(driverName + "-nodeplugin-pool-"))
+			Expect(poolCsiAddonsDaemonSet.Spec.Template.Labels).To(HaveKeyWithValue("app", appLabel))
+
+			np := &networkingv1.NetworkPolicy{}
+			npKey := types.NamespacedName{Name: driverName + "-nodeplugin-csi-addons", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, npKey, np)).To(Succeed())
+			Expect(np.Spec.PodSelector.MatchExpressions).To(ConsistOf(HaveField(
+				"Values",
+				ConsistOf(npKey.Name, appLabel),
+			)))
+
+			poolDaemonSet := &appsv1.DaemonSet{}
+			poolDaemonSetKey := types.NamespacedName{
+				Name:      driverName + "-nodeplugin-pool-" + poolName,
+				Namespace: "default",
+			}
+			Expect(k8sClient.Get(ctx, poolDaemonSetKey, poolDaemonSet)).To(Succeed())
+			Expect(poolDaemonSet.Spec.Selector.MatchLabels).To(HaveKeyWithValue("app", poolDaemonSetKey.Name))
+		})
+	})
+
+	Context("platform detection", func() {
+		newNode := func(name string, labels, annotations map[string]string, kubeletVersion string) corev1.Node {
+			node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations}}
+			node.Status.NodeInfo.KubeletVersion = kubeletVersion
+			return node
+		}
+
+		It("should detect the platform of a node", func() {
+			Expect(detectNodePlatform(ptr.To(newNode("a", map[string]string{openShiftNodeOSLabelKey: "rhcos"}, nil, "")))).
+				To(Equal(csiv1.OpenShiftPlatformType))
+			Expect(detectNodePlatform(ptr.To(newNode("a", map[string]string{microK8sNodeLabelKey: "true"}, nil, "")))).
+				To(Equal(csiv1.MicroK8sPlatformType))
+			Expect(detectNodePlatform(ptr.To(newNode("a", nil, nil, "v1.31.1+k0s")))).
+				To(Equal(csiv1.K0sPlatformType))
+			Expect(detectNodePlatform(ptr.To(newNode("a", nil, nil, "v1.31.1+rke2r1")))).
+				To(Equal(csiv1.RKE2PlatformType))
+			Expect(detectNodePlatform(ptr.To(newNode("a", nil, nil, "v1.31.1")))).
+				To(Equal(csiv1.KubernetesPlatformType))
+		})
+
+		It("should prefer the API server version over nodes", func() {
+			nodes := []corev1.Node{newNode("a", map[string]string{microK8sNodeLabelKey: "true"}, nil, "")}
+			Expect(detectClusterPlatform(&version.Info{GitVersion: "v1.31.1+k3s1"}, nodes)).
+				To(Equal(csiv1.K3sPlatformType))
+			Expect(detectClusterPlatform(&version.Info{GitVersion: "v1.31.1"}, nodes)).
+				To(Equal(csiv1.MicroK8sPlatformType))
+			Expect(detectClusterPlatform(nil, nil)).To(Equal(csiv1.KubernetesPlatformType))
+		})
+
+		It("should detect the kubelet root dir from k3s node arguments", func() {
+			node := newNode("a", nil, map[string]string{
+				k3sNodeArgsAnnotationKey: `["agent","--kubelet-arg","root-dir=/data/kubelet","--kubelet-arg=v=2"]`,
+			}, "")
+			path, known := detectNodeKubeletDirPath(&node)
+			Expect(known).To(BeTrue())
+			Expect(path).To(Equal("/data/kubelet"))
+
+			_, known = detectNodeKubeletDirPath(ptr.To(newNode("b", nil, nil, "v1.31.1")))
+			Expect(known).To(BeFalse())
+		})
+
+		It("should detect a cluster DNS disabled on k3s and rke2 servers", func() {
+			nodes := []corev1.Node{
+				newNode("a", nil, map[string]string{k3sNodeArgsAnnotationKey: `["agent"]`}, ""),
+				newNode("b", nil, map[string]string{
+					k3sNodeArgsAnnotationKey: `["server","--disable","traefik","--disable=metrics-server,coredns"]`,
+				}, ""),
+			}
+			Expect(clusterDNSDisabled(nodes, csiv1.K3sPlatformType)).To(BeTrue())
+			Expect(clusterDNSDisabled(nodes[:1], csiv1.K3sPlatformType)).To(BeFalse())
+			Expect(clusterDNSDisabled(nodes, csiv1.RKE2PlatformType)).To(BeFalse())
+			Expect(clusterDNSDisabled(nodes, csiv1.KubernetesPlatformType)).To(BeFalse())
+
+			reconciler := driverReconcile{}
+			Expect(reconciler.podDNSPolicy(true)).To(Equal(corev1.DNSClusterFirstWithHostNet))
+			Expect(reconciler.podDNSPolicy(false)).To(Equal(corev1.DNSClusterFirst))
+			reconciler.clusterDNSDisabled = true
+			Expect(reconciler.podDNSPolicy(true)).To(Equal(corev1.DNSDefault))
+		})
+
+		It("should only reconcile on node changes affecting the detection", func() {
+			oldNode := newNode("a", map[string]string{"zone": "a"}, nil, "v1.31.1")
+			newNode := oldNode.DeepCopy()
+			newNode.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady}}
+			Expect(nodeChangedPredicate.Update(event.UpdateEvent{ObjectOld: &oldNode, ObjectNew: newNode})).To(BeFalse())
+			newNode.Labels["zone"] = "b"
+			Expect(nodeChangedPredicate.Update(event.UpdateEvent{ObjectOld: &oldNode, ObjectNew: newNode})).To(BeTrue())
+			Expect(nodeChangedPredicate.Create(event.CreateEvent{Object: newNode})).To(BeTrue())
+		})
+
+		It("should report nodes with a mismatching kubelet root dir", func() {
+			nodes := []corev1.Node{
+				newNode("a", map[string]string{microK8sNodeLabelKey: "true", "zone": "a"}, nil, ""),
+				newNode("b", map[string]string{"zone": "a"}, nil, "v1.31.1+k0s"),
+				newNode("c", map[string]string{"zone": "b"}, nil, "v1.31.1+k0s"),
+			}
+			nodeSelector := &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}},
+					}},
+				},
+			}
+			zoneNodes := filterNodesBySelector(nodes, nodeSelector)
+			Expect(zoneNodes).To(HaveLen(2))
+			// Nodes do not agree on a single path, falling back to the cluster platform default
+			Expect(detectKubeletDirPath(zoneNodes, csiv1.KubernetesPlatformType)).To(Equal(defaultKubeletDirPath))
+
+			mismatches := kubeletDirPathMismatches("ds", nil, defaultKubeletDirPath, zoneNodes)
+			Expect(mismatches).To(HaveLen(2))
+			Expect(mismatches[0].DetectedKubeletDirPath).To(Equal(platformKubeletDirPaths[csiv1.K0sPlatformType]))
+			Expect(mismatches[0].Nodes).To(Equal([]string{"b"}))
+			Expect(mismatches[1].Nodes).To(Equal([]string{"a"}))
+		})
+
+		It("should require the operator SCC only when it is available", func() {
+			reconciler := driverReconcile{}
+			Expect(reconciler.generatePodAnnotations(nil)).To(BeNil())
+
+			reconciler.requiredSCC = openShiftSCCName
+			Expect(reconciler.generatePodAnnotations(nil)).
+				To(HaveKeyWithValue(openShiftRequiredSCCAnnotationKey, openShiftSCCName))
+			Expect(reconciler.generatePodAnnotations(map[string]string{openShiftRequiredSCCAnnotationKey: "custom"})).
+				To(HaveKeyWithValue(openShiftRequiredSCCAnnotationKey, "custom"))
+		})
+	})
+
+	Context("When reconciling a resource on a detected platform", func() {
+		const resourceName = "platform.rbd.csi.ceph.com"
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		It("should use the kubelet root dir detected on the nodes", func() {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "microk8s-node",
+					Labels: map[string]string{microK8sNodeLabelKey: "true"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, node)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, node)).To(Succeed())
+			}()
+
+			driver := &csiv1.Driver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+			}
+			Expect(k8sClient.Create(ctx, driver)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, driver)).To(Succeed())
+			}()
+
+			controllerReconciler := &DriverReconciler{
+				Client:        k8sClient,
+				Scheme:        k8sClient.Scheme(),
+				ServerVersion: &version.Info{GitVersion: "v1.31.1"},
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			daemonSet := &appsv1.DaemonSet{}
+			daemonSetKey := types.NamespacedName{Name: resourceName + "-nodeplugin", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, daemonSetKey, daemonSet)).To(Succeed())
+			Expect(daemonSet.Spec.Template.Spec.Volumes).To(ContainElement(
+				HaveField("VolumeSource.HostPath.Path", HavePrefix(platformKubeletDirPaths[csiv1.MicroK8sPlatformType])),
+			))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, driver)).To(Succeed())
+			Expect(driver.Status.Platform).To(Equal(csiv1.MicroK8sPlatformType))
+			Expect(driver.Status.KubeletDirPathMismatches).To(BeEmpty())
+		})
+	})
+
+	Context("validateCsiDriverSpec", func() {
+		newReconciler := func(driverType DriverType, spec csiv1.DriverSpec) *driverReconcile {
+			return &driverReconcile{driverType: driverType, driver: csiv1.Driver{Spec: spec}}
+		}
+
+		It("should accept storage capacity and ephemeral volumes for rbd and cephfs drivers", func() {
+			spec := csiv1.DriverSpec{
+				StorageCapacity:      ptr.To(true),
+				VolumeLifecycleModes: []storagev1.VolumeLifecycleMode{storagev1.VolumeLifecycleEphemeral},
+			}
+			Expect(newReconciler(RbdDriverType, spec).validateCsiDriverSpec()).To(Succeed())
+			Expect(newReconciler(CephFsDriverType, spec).validateCsiDriverSpec()).To(Succeed())
+		})
+
+		It("should reject storage capacity and ephemeral volumes for other drivers", func() {
+			Expect(newReconciler(NfsDriverType, csiv1.DriverSpec{StorageCapacity: ptr.To(true)}).
+				validateCsiDriverSpec()).NotTo(Succeed())
+			Expect(newReconciler(NvmeofDriverType, csiv1.DriverSpec{
+				VolumeLifecycleModes: []storagev1.VolumeLifecycleMode{storagev1.VolumeLifecycleEphemeral},
+			}).validateCsiDriverSpec()).NotTo(Succeed())
+		})
+
+		It("should reject ephemeral volumes without pod info on mount", func() {
+			Expect(newReconciler(RbdDriverType, csiv1.DriverSpec{
+				PodInfoOnMount:       ptr.To(false),
+				VolumeLifecycleModes: []storagev1.VolumeLifecycleMode{storagev1.VolumeLifecycleEphemeral},
+			}).validateCsiDriverSpec()).NotTo(Succeed())
+		})
+
+		It("should reject volume health for unsupported drivers and node reporting without csi-addons", func() {
+			Expect(newReconciler(NfsDriverType, csiv1.DriverSpec{VolumeHealth: &csiv1.VolumeHealthSpec{}}).
+				validateCsiDriverSpec()).NotTo(Succeed())
+			Expect(newReconciler(RbdDriverType, csiv1.DriverSpec{
+				VolumeHealth: &csiv1.VolumeHealthSpec{NodeReporting: ptr.To(true)},
+			}).validateCsiDriverSpec()).NotTo(Succeed())
+			Expect(newReconciler(RbdDriverType, csiv1.DriverSpec{VolumeHealth: &csiv1.VolumeHealthSpec{}}).
+				validateCsiDriverSpec()).To(Succeed())
+		})
+
+		It("should warn about the deprecated volume condition annotation and reject invalid values", func() {
+			recorder := events.NewFakeRecorder(10)
+			reconciler := newReconciler(RbdDriverType, csiv1.DriverSpec{DeployCsiAddons: ptr.To(true)})
+			reconciler.Recorder = recorder
+			reconciler.driver.Annotations = map[string]string{driverCSIAddonsFeatureVolumeCondition: "true"}
+			Expect(reconciler.validateCsiDriverSpec()).To(Succeed())
+			Expect(recorder.Events).To(Receive(ContainSubstring(deprecatedAnnotationReason)))
+
+			reconciler.driver.Annotations[driverCSIAddonsFeatureVolumeCondition] = "yes please"
+			Expect(reconciler.validateCsiDriverSpec()).NotTo(Succeed())
+		})
+
+		It("should reject CSI-Addons sidecars sharing a port", func() {
+			Expect(newReconciler(RbdDriverType, csiv1.DriverSpec{
+				CsiAddons: &csiv1.CsiAddonsSpec{
+					Controller: &csiv1.CsiAddonsSidecarSpec{Port: ptr.To(int32(9071))},
+				},
+			}).validateCsiDriverSpec()).NotTo(Succeed())
+			Expect(newReconciler(RbdDriverType, csiv1.DriverSpec{
+				CsiAddons: &csiv1.CsiAddonsSpec{
+					Controller: &csiv1.CsiAddonsSidecarSpec{Port: ptr.To(int32(9071))},
+					Node:       &csiv1.CsiAddonsSidecarSpec{Port: ptr.To(int32(9072))},
+				},
+			}).validateCsiDriverSpec()).To(Succeed())
+			Expect(newReconciler(RbdDriverType, csiv1.DriverSpec{
+				CsiAddons: &csiv1.CsiAddonsSpec{
+					Controller: &csiv1.CsiAddonsSidecarSpec{Port: ptr.To(int32(9071))},
+					Features:   &csiv1.CsiAddonsFeaturesSpec{ReclaimSpace: ptr.To(false), KeyRotation: ptr.To(false)},
+				},
+			}).validateCsiDriverSpec()).To(Succeed())
+		})
+
+		It("should reject volume health node reporting with the volume condition feature disabled", func() {
+			Expect(newReconciler(RbdDriverType, csiv1.DriverSpec{
+				VolumeHealth: &csiv1.VolumeHealthSpec{NodeReporting: ptr.To(true)},
+				CsiAddons: &csiv1.CsiAddonsSpec{
+					Features: &csiv1.CsiAddonsFeaturesSpec{VolumeCondition: ptr.To(false)},
+				},
+			}).validateCsiDriverSpec()).NotTo(Succeed())
+		})
+
+		It("should reject invalid certificate settings", func() {
+			Expect(newReconciler(CephFsDriverType, csiv1.DriverSpec{SnapshotMetadata: &csiv1.SnapshotMetadataSpec{}}).
+				validateCsiDriverSpec()).NotTo(Succeed())
+			Expect(newReconciler(RbdDriverType, csiv1.DriverSpec{
+				Certificates: &csiv1.CertificatesSpec{RenewBefore: &metav1.Duration{Duration: 100 * 24 * time.Hour}},
+			}).validateCsiDriverSpec()).NotTo(Succeed())
+			Expect(newReconciler(RbdDriverType, csiv1.DriverSpec{
+				Certificates: &csiv1.CertificatesSpec{Issuer: csiv1.CertManagerCertificateIssuer},
+			}).validateCsiDriverSpec()).NotTo(Succeed())
+		})
+
+		It("should reject monitoring without liveness", func() {
+			Expect(newReconciler(RbdDriverType, csiv1.DriverSpec{Monitoring: &csiv1.MonitoringSpec{}}).
+				validateCsiDriverSpec()).NotTo(Succeed())
+			Expect(newReconciler(RbdDriverType, csiv1.DriverSpec{
+				Monitoring: &csiv1.MonitoringSpec{},
+				Liveness:   &csiv1.LivenessSpec{MetricsPort: 9080},
+			}).validateCsiDriverSpec()).To(Succeed())
+		})
+
+		It("should reject a liveness health port sharing the metrics port", func() {
+			Expect(newReconciler(RbdDriverType, csiv1.DriverSpec{
+				Liveness: &csiv1.LivenessSpec{MetricsPort: 9808},
+			}).validateCsiDriverSpec()).NotTo(Succeed())
+			Expect(newReconciler(RbdDriverType, csiv1.DriverSpec{
+				Liveness: &csiv1.LivenessSpec{MetricsPort: 9808, HealthPort: ptr.To(int32(9809))},
+			}).validateCsiDriverSpec()).To(Succeed())
+		})
+
+		It("should reject duplicate token request audiences", func() {
+			Expect(newReconciler(RbdDriverType, csiv1.DriverSpec{
+				TokenRequests: []storagev1.TokenRequest{{Audience: "vault"}, {Audience: "vault"}},
+			}).validateCsiDriverSpec()).NotTo(Succeed())
+		})
+	})
+
+	Context("When reconciling a resource with liveness", func() {
+		const resourceName = "liveness.cephfs.csi.ceph.com"
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		It("should probe the plugin containers over their CSI socket", func() {
+			driver := &csiv1.Driver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: csiv1.DriverSpec{
+					Liveness: &csiv1.LivenessSpec{MetricsPort: 9081},
+				},
+			}
+			Expect(k8sClient.Create(ctx, driver)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, driver)).To(Succeed())
+			}()
+
+			controllerReconciler := &DriverReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			deploy := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-ctrlplugin", Namespace: "default"}, deploy)).
+				To(Succeed())
+			daemonSet := &appsv1.DaemonSet{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-nodeplugin", Namespace: "default"}, daemonSet)).
+				To(Succeed())
+
+			for _, podSpec := range []corev1.PodSpec{deploy.Spec.Template.Spec, daemonSet.Spec.Template.Spec} {
+				Expect(podSpec.Containers).To(ContainElement(And(
+					HaveField("Name", "csi-cephfsplugin"),
+					HaveField("LivenessProbe.HTTPGet.Path", "/healthz"),
+					HaveField("LivenessProbe.HTTPGet.Port.IntVal", int32(9818)),
+				)))
+				Expect(podSpec.Containers).To(ContainElement(And(
+					HaveField("Name", "liveness-probe"),
+					HaveField("Image", imageDefaults["livenessprobe"]),
+					HaveField("Args", ContainElements("--csi-address=unix:///csi/csi.sock", "--http-endpoint=:9818")),
+					HaveField("VolumeMounts", ContainElement(HaveField("MountPath", utils.SocketDir))),
+				)))
+			}
+		})
+	})
+
+	Context("When reconciling a resource with inline volumes", func() {
+		const resourceName = "inline.rbd.csi.ceph.com"
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		It("should enable inline volumes on the CSIDriver and restrict them to the allowed namespaces", func() {
+			clientProfile := &csiv1.ClientProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: "inline-profile", Namespace: "default"},
+				Spec: csiv1.ClientProfileSpec{
+					CephConnectionRef: corev1.LocalObjectReference{Name: "ceph-connection"},
+					InlineVolumes:     &csiv1.InlineVolumesSpec{AllowedNamespaces: []string{"scratch", "ci"}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, clientProfile)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, clientProfile)).To(Succeed())
+			}()
+
+			driver := &csiv1.Driver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: csiv1.DriverSpec{
+					VolumeLifecycleModes: []storagev1.VolumeLifecycleMode{
+						storagev1.VolumeLifecyclePersistent,
+						storagev1.VolumeLifecycleEphemeral,
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, driver)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, driver)).To(Succeed())
+			}()
+
+			controllerReconciler := &DriverReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			csiDriver := &storagev1.CSIDriver{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName}, csiDriver)).To(Succeed())
+			Expect(csiDriver.Spec.VolumeLifecycleModes).To(ContainElement(storagev1.VolumeLifecycleEphemeral))
+			Expect(k8sClient.Delete(ctx, csiDriver)).To(Succeed())
+
+			By("Restricting the inline volumes of the driver with an admission policy")
+			policyKey := types.NamespacedName{Name: resourceName + "-inline-volumes"}
+			policy := &admissionregistrationv1.ValidatingAdmissionPolicy{}
+			Expect(k8sClient.Get(ctx, policyKey, policy)).To(Succeed())
+			Expect(policy.Spec.Variables).To(ConsistOf(admissionregistrationv1.Variable{
+				Name:       "allowedNamespaces",
+				Expression: `{"inline-profile": ["scratch", "ci"]}`,
+			}))
+			Expect(policy.Spec.Validations).To(ConsistOf(
+				HaveField("Expression", ContainSubstring(`v.csi.driver != "`+resourceName+`"`)),
+			))
+			binding := &admissionregistrationv1.ValidatingAdmissionPolicyBinding{}
+			Expect(k8sClient.Get(ctx, policyKey, binding)).To(Succeed())
+			Expect(binding.Spec.PolicyName).To(Equal(policy.Name))
+			Expect(binding.Spec.ValidationActions).To(ConsistOf(admissionregistrationv1.Deny))
+
+			By("Deleting the admission policy once inline volumes are disabled")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, driver)).To(Succeed())
+			driver.Spec.VolumeLifecycleModes = nil
+			Expect(k8sClient.Update(ctx, driver)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, policyKey, policy))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, policyKey, binding))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName}, csiDriver)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, csiDriver)).To(Succeed())
+		})
+
+		It("should only allow the inline volumes of client profiles with an allowlist", func() {
+			Expect(inlineVolumesAllowedNamespacesExpression(nil)).To(Equal("{}"))
+			Expect(inlineVolumesAllowedNamespacesExpression([]csiv1.ClientProfile{
+				{ObjectMeta: metav1.ObjectMeta{Name: "b"}, Spec: csiv1.ClientProfileSpec{
+					InlineVolumes: &csiv1.InlineVolumesSpec{AllowedNamespaces: []string{"*"}},
+				}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "none"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "a"}, Spec: csiv1.ClientProfileSpec{
+					InlineVolumes: &csiv1.InlineVolumesSpec{AllowedNamespaces: []string{"ci"}},
+				}},
+			})).To(Equal(`{"a": ["ci"], "b": ["*"]}`))
+		})
+	})
+
+	Context("When reconciling a resource with volume health monitoring", func() {
+		const resourceName = "health.rbd.csi.ceph.com"
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		It("should deploy the health monitor and enable node reporting", func() {
+			driver := &csiv1.Driver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: csiv1.DriverSpec{
+					DeployCsiAddons: ptr.To(true),
+					VolumeHealth: &csiv1.VolumeHealthSpec{
+						MonitorInterval: &metav1.Duration{Duration: 5 * time.Minute},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, driver)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, driver)).To(Succeed())
+			}()
+
+			controllerReconciler := &DriverReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			deploy := &appsv1.Deployment{}
+			deployKey := types.NamespacedName{Name: resourceName + "-ctrlplugin", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, deployKey, deploy)).To(Succeed())
+			Expect(deploy.Spec.Template.Spec.Containers).To(ContainElement(And(
+				HaveField("Name", "csi-external-health-monitor-controller"),
+				HaveField("Image", imageDefaults["health-monitor"]),
+				HaveField("Args", ContainElement("--monitor-interval=5m0s")),
+			)))
+
+			daemonSet := &appsv1.DaemonSet{}
+			daemonSetKey := types.NamespacedName{Name: resourceName + "-nodeplugin-csi-addons", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, daemonSetKey, daemonSet)).To(Succeed())
+			Expect(daemonSet.Spec.Template.Spec.Containers).To(ContainElement(And(
+				HaveField("Name", "csi-addons"),
+				HaveField("Args", ContainElement(utils.CsiAddonsVolumeConditionArg)),
+			)))
+
+			csiDriver := &storagev1.CSIDriver{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName}, csiDriver)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, csiDriver)).To(Succeed())
+		})
+	})
+
+	Context("csiAddonsConfig", func() {
+		newReconciler := func(driverType DriverType, spec csiv1.DriverSpec) *driverReconcile {
+			return &driverReconcile{driverType: driverType, driver: csiv1.Driver{Spec: spec}}
+		}
+
+		It("should fall back to the deprecated deployCsiAddons flag", func() {
+			config := newReconciler(RbdDriverType, csiv1.DriverSpec{DeployCsiAddons: ptr.To(true)}).csiAddonsConfig()
+			Expect(config.enabled).To(BeTrue())
+			Expect(config.controller).To(BeTrue())
+			Expect(config.node).To(BeTrue())
+			Expect(config.stagingPath).To(BeTrue())
+			Expect(config.volumeCondition).To(BeFalse())
+			Expect(config.controllerPort).To(Equal(utils.ControllerPluginCsiAddonsContainerRbdPort))
+			Expect(config.nodePort).To(Equal(utils.NodePluginCsiAddonsContainerPort))
+
+			Expect(newReconciler(RbdDriverType, csiv1.DriverSpec{}).csiAddonsConfig().enabled).To(BeFalse())
+		})
+
+		It("should give the csiAddons block precedence over deployCsiAddons", func() {
+			spec := csiv1.DriverSpec{
+				DeployCsiAddons: ptr.To(true),
+				CsiAddons:       &csiv1.CsiAddonsSpec{Enabled: ptr.To(false)},
+			}
+			Expect(newReconciler(RbdDriverType, spec).csiAddonsConfig().enabled).To(BeFalse())
+
+			spec = csiv1.DriverSpec{DeployCsiAddons: ptr.To(false), CsiAddons: &csiv1.CsiAddonsSpec{}}
+			Expect(newReconciler(CephFsDriverType, spec).csiAddonsConfig().enabled).To(BeTrue())
+		})
+
+		It("should never enable CSI-Addons for nfs drivers", func() {
+			spec := csiv1.DriverSpec{CsiAddons: &csiv1.CsiAddonsSpec{}}
+			Expect(newReconciler(NfsDriverType, spec).csiAddonsConfig().enabled).To(BeFalse())
+		})
+
+		It("should report the volume condition when volume health node reporting is enabled", func() {
+			config := newReconciler(RbdDriverType, csiv1.DriverSpec{
+				VolumeHealth: &csiv1.VolumeHealthSpec{},
+				CsiAddons:    &csiv1.CsiAddonsSpec{},
+			}).csiAddonsConfig()
+			Expect(config.enabled).To(BeTrue())
+			Expect(config.volumeCondition).To(BeTrue())
+
+			config = newReconciler(RbdDriverType, csiv1.DriverSpec{
+				VolumeHealth: &csiv1.VolumeHealthSpec{NodeReporting: ptr.To(false)},
+				CsiAddons:    &csiv1.CsiAddonsSpec{},
+			}).csiAddonsConfig()
+			Expect(config.volumeCondition).To(BeFalse())
+		})
+
+		It("should only deploy the sidecars serving enabled features", func() {
+			config := newReconciler(RbdDriverType, csiv1.DriverSpec{
+				CsiAddons: &csiv1.CsiAddonsSpec{
+					Features: &csiv1.CsiAddonsFeaturesSpec{
+						ReclaimSpace: ptr.To(false),
+						KeyRotation:  ptr.To(false),
+					},
+				},
+			}).csiAddonsConfig()
+			Expect(config.enabled).To(BeTrue())
+			Expect(config.controller).To(BeTrue())
+			Expect(config.node).To(BeFalse())
+
+			config = newReconciler(RbdDriverType, csiv1.DriverSpec{
+				VolumeHealth: &csiv1.VolumeHealthSpec{},
+				CsiAddons: &csiv1.CsiAddonsSpec{
+					Features: &csiv1.CsiAddonsFeaturesSpec{
+						ReclaimSpace:           ptr.To(false),
+						KeyRotation:            ptr.To(false),
+						NetworkFence:           ptr.To(false),
+						VolumeReplication:      ptr.To(false),
+						VolumeGroupReplication: ptr.To(false),
+					},
+				},
+			}).csiAddonsConfig()
+			Expect(config.controller).To(BeFalse())
+			Expect(config.node).To(BeTrue())
+			Expect(config.stagingPath).To(BeFalse())
+			Expect(config.volumeCondition).To(BeTrue())
+
+			config = newReconciler(RbdDriverType, csiv1.DriverSpec{
+				VolumeHealth: &csiv1.VolumeHealthSpec{},
+				CsiAddons: &csiv1.CsiAddonsSpec{
+					Features: &csiv1.CsiAddonsFeaturesSpec{VolumeCondition: ptr.To(false)},
+				},
+			}).csiAddonsConfig()
+			Expect(config.volumeCondition).To(BeFalse())
+		})
+
+		It("should honor the deprecated volume condition annotation unless volume health is set", func() {
+			reconciler := newReconciler(RbdDriverType, csiv1.DriverSpec{CsiAddons: &csiv1.CsiAddonsSpec{}})
+			reconciler.driver.Annotations = map[string]string{driverCSIAddonsFeatureVolumeCondition: "true"}
+			Expect(reconciler.csiAddonsConfig().volumeCondition).To(BeTrue())
+
+			reconciler.driver.Spec.VolumeHealth = &csiv1.VolumeHealthSpec{NodeReporting: ptr.To(false)}
+			Expect(reconciler.csiAddonsConfig().volumeCondition).To(BeFalse())
+		})
+	})
+
+	Context("When reconciling a resource with a csiAddons block", func() {
+		const resourceName = "addons.rbd.csi.ceph.com"
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		It("should render the node sidecar from the block", func() {
+			driver := &csiv1.Driver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: csiv1.DriverSpec{
+					CsiAddons: &csiv1.CsiAddonsSpec{
+						Features: &csiv1.CsiAddonsFeaturesSpec{
+							NetworkFence:           ptr.To(false),
+							VolumeReplication:      ptr.To(false),
+							VolumeGroupReplication: ptr.To(false),
+							ReclaimSpace:           ptr.To(false),
+							KeyRotation:            ptr.To(false),
+							VolumeCondition:        ptr.To(true),
+						},
+						Node: &csiv1.CsiAddonsSidecarSpec{Port: ptr.To(int32(9171))},
+						TLS: &csiv1.CsiAddonsTLSSpec{
+							SecretName: "csi-addons-tls",
+							MinVersion: csiv1.TLSVersion13,
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, driver)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, driver)).To(Succeed())
+			}()
+
+			controllerReconciler := &DriverReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Skipping the controller sidecar when no controller side feature is enabled")
+			deploy := &appsv1.Deployment{}
+			deployKey := types.NamespacedName{Name: resourceName + "-ctrlplugin", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, deployKey, deploy)).To(Succeed())
+			Expect(deploy.Spec.Template.Spec.Containers).NotTo(ContainElement(HaveField("Name", "csi-addons")))
+			Expect(deploy.Spec.Template.Spec.Containers).To(ContainElement(And(
+				HaveField("Name", "csi-rbdplugin"),
+				HaveField("Args", Not(ContainElement(utils.CsiAddonsEndpointContainerArg))),
+			)))
+
+			By("Rendering the node sidecar args and mounts")
+			daemonSet := &appsv1.DaemonSet{}
+			daemonSetKey := types.NamespacedName{Name: resourceName + "-nodeplugin-csi-addons", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, daemonSetKey, daemonSet)).To(Succeed())
+			Expect(daemonSet.Spec.Template.Spec.Containers).To(ContainElement(And(
+				HaveField("Name", "csi-addons"),
+				HaveField("Args", ContainElements(
+					"--controller-port=9171",
+					utils.CsiAddonsVolumeConditionArg,
+					utils.CsiAddonsTlsCertFileContainerArg,
+					utils.CsiAddonsTlsKeyFileContainerArg,
+					"--tls-min-version=VersionTLS13",
+				)),
+				HaveField("Args", Not(ContainElement(HavePrefix("--stagingpath=")))),
+				HaveField("VolumeMounts", ContainElements(
+					utils.CsiAddonsTlsVolumeMount,
+					utils.PodsMountDirVolumeMount(defaultKubeletDirPath),
+				)),
+			)))
+			Expect(daemonSet.Spec.Template.Spec.Volumes).To(ContainElement(utils.CsiAddonsTlsVolume("csi-addons-tls")))
+
+			By("Allowing the configured port in the node network policy")
+			np := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, daemonSetKey, np)).To(Succeed())
+			Expect(np.Spec.Ingress).To(HaveLen(1))
+			Expect(np.Spec.Ingress[0].Ports[0].Port.IntVal).To(Equal(int32(9171)))
+
+			csiDriver := &storagev1.CSIDriver{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName}, csiDriver)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, csiDriver)).To(Succeed())
+		})
+	})
+
+	Context("When reconciling a resource with operator issued certificates", func() {
+		const resourceName = "certs.rbd.csi.ceph.com"
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		It("should issue, mount and report the serving certificates", func() {
+			driver := &csiv1.Driver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: csiv1.DriverSpec{
+					SnapshotMetadata: &csiv1.SnapshotMetadataSpec{},
+					Liveness:         &csiv1.LivenessSpec{MetricsPort: 9080, TLS: ptr.To(true)},
+				},
+			}
+			Expect(k8sClient.Create(ctx, driver)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, driver)).To(Succeed())
+			}()
+
+			controllerReconciler := &DriverReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 59*24*time.Hour))
+
+			By("Issuing the certificates from the driver certificate authority")
+			caSecret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-ca", Namespace: "default"}, caSecret)).
+				To(Succeed())
+			ca, err := utils.ParseCertificateAuthority(caSecret.Data["tls.crt"], caSecret.Data["tls.key"])
+			Expect(err).NotTo(HaveOccurred())
+
+			caBundle := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-ca-bundle", Namespace: "default"}, caBundle)).
+				To(Succeed())
+			Expect(caBundle.Data).To(HaveKeyWithValue("ca.crt", string(ca.CertPEM)))
+
+			secret := &corev1.Secret{}
+			secretKey := types.NamespacedName{Name: resourceName + "-snapshot-metadata-tls", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, secretKey, secret)).To(Succeed())
+			Expect(secret.Type).To(Equal(corev1.SecretTypeTLS))
+			cert, err := utils.ParseCertificate(secret.Data["tls.crt"])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.CheckSignatureFrom(ca.Cert)).To(Succeed())
+			Expect(cert.DNSNames).To(ContainElement("certs-rbd-csi-ceph-com-snapshot-metadata.default.svc"))
+			Expect(secret.Data).To(HaveKeyWithValue("ca.crt", ca.CertPEM))
+
+			metricsSecret := &corev1.Secret{}
+			metricsSecretKey := types.NamespacedName{Name: resourceName + "-metrics-tls", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, metricsSecretKey, metricsSecret)).To(Succeed())
+			metricsCert, err := utils.ParseCertificate(metricsSecret.Data["tls.crt"])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metricsCert.DNSNames).To(ContainElements(
+				"certs-rbd-csi-ceph-com-ctrlplugin-metrics.default.svc",
+				"certs-rbd-csi-ceph-com-nodeplugin-metrics.default.svc",
+			))
+
+			By("Mounting the certificates in the controller plugin")
+			deploy := &appsv1.Deployment{}
+			deployKey := types.NamespacedName{Name: resourceName + "-ctrlplugin", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, deployKey, deploy)).To(Succeed())
+			podSpec := deploy.Spec.Template.Spec
+			Expect(podSpec.Containers).To(ContainElement(And(
+				HaveField("Name", "csi-snapshot-metadata"),
+				HaveField("VolumeMounts", ContainElement(utils.SnapshotMetadataTlsVolumeMount)),
+			)))
+			Expect(podSpec.Containers).To(ContainElement(And(
+				HaveField("Name", "liveness-prometheus"),
+				HaveField("LivenessProbe", BeNil()),
+				HaveField("Env", ConsistOf(utils.LoopbackPodIpEnvVar)),
+			)))
+			Expect(podSpec.Containers).To(ContainElement(And(
+				HaveField("Name", "kube-rbac-proxy"),
+				HaveField("Image", imageDefaults["kube-rbac-proxy"]),
+				HaveField("Args", ContainElements(
+					"--secure-listen-address=$(POD_IP):9080",
+					"--upstream=http://127.0.0.1:9080/",
+					utils.MetricsProxyTlsCertFileContainerArg,
+				)),
+				HaveField("VolumeMounts", ContainElement(utils.MetricsTlsVolumeMount)),
+			)))
+			Expect(podSpec.Containers).To(ContainElement(And(
+				HaveField("Name", "csi-rbdplugin"),
+				HaveField("LivenessProbe.HTTPGet.Path", "/healthz"),
+				HaveField("LivenessProbe.HTTPGet.Port.IntVal", int32(9808)),
+			)))
+			Expect(podSpec.Volumes).To(ContainElements(
+				utils.SnapshotMetadataTlsVolume(secretKey.Name),
+				utils.MetricsTlsVolume(metricsSecretKey.Name),
+			))
+			hash := deploy.Spec.Template.Annotations[certificatesHashAnnotationKey]
+			Expect(hash).NotTo(BeEmpty())
+
+			By("Reporting the certificates in the status")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, driver)).To(Succeed())
+			Expect(driver.Status.Certificates).To(ConsistOf(
+				And(
+					HaveField("Component", "snapshot-metadata"),
+					HaveField("Issuer", csiv1.InternalCertificateIssuer),
+					HaveField("NotAfter.Time", BeTemporally("~", cert.NotAfter, time.Second)),
+				),
+				HaveField("Component", "metrics"),
+			))
+
+			By("Serving the node plugin metrics through the metrics proxy")
+			daemonSet := &appsv1.DaemonSet{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-nodeplugin", Namespace: "default"}, daemonSet)).
+				To(Succeed())
+			Expect(daemonSet.Spec.Template.Spec.Containers).To(ContainElement(HaveField("Name", "kube-rbac-proxy")))
+			Expect(daemonSet.Spec.Template.Spec.Volumes).To(ContainElement(utils.MetricsTlsVolume(metricsSecretKey.Name)))
+			Expect(daemonSet.Spec.Template.Annotations).To(HaveKey(certificatesHashAnnotationKey))
+
+			By("Keeping valid certificates on the next reconcile")
+			resourceVersion := driver.ResourceVersion
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, deployKey, deploy)).To(Succeed())
+			Expect(deploy.Spec.Template.Annotations).To(HaveKeyWithValue(certificatesHashAnnotationKey, hash))
+
+			By("Removing the certificates that are no longer needed")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, driver)).To(Succeed())
+			Expect(driver.ResourceVersion).To(Equal(resourceVersion), "the status should not be updated again")
+			driver.Spec.SnapshotMetadata = nil
+			driver.Spec.Liveness = nil
+			Expect(k8sClient.Update(ctx, driver)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, secretKey, secret))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, metricsSecretKey, metricsSecret))).To(BeTrue())
+			Expect(errors.IsNotFound(
+				k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-ca", Namespace: "default"}, caSecret),
+			)).To(BeTrue())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, driver)).To(Succeed())
+			Expect(driver.Status.Certificates).To(BeEmpty())
+
+			csiDriver := &storagev1.CSIDriver{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName}, csiDriver)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, csiDriver)).To(Succeed())
+		})
+	})
+
+	Context("When reconciling a resource with cert-manager issued certificates", func() {
+		const resourceName = "certmanager.rbd.csi.ceph.com"
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		It("should hand the certificates off to cert-manager", func() {
+			driver := &csiv1.Driver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: csiv1.DriverSpec{
+					CsiAddons: &csiv1.CsiAddonsSpec{TLS: &csiv1.CsiAddonsTLSSpec{}},
+					Certificates: &csiv1.CertificatesSpec{
+						Issuer:               csiv1.CertManagerCertificateIssuer,
+						CertManagerIssuerRef: &csiv1.CertManagerIssuerReference{Name: "ca-issuer", Kind: "ClusterIssuer"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, driver)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, driver)).To(Succeed())
+			}()
+
+			controllerReconciler := &DriverReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("<=", certManagerPendingRequeueDelay))
+
+			certificate := &unstructured.Unstructured{}
+			certificate.SetGroupVersionKind(certManagerCertificateGroupVersionKind)
+			certificateKey := types.NamespacedName{Name: resourceName + "-csi-addons-tls", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, certificateKey, certificate)).To(Succeed())
+			Expect(certificate.Object).To(HaveKeyWithValue("spec", And(
+				HaveKeyWithValue("secretName", certificateKey.Name),
+				HaveKeyWithValue("issuerRef", HaveKeyWithValue("kind", "ClusterIssuer")),
+			)))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, driver)).To(Succeed())
+			Expect(driver.Status.Certificates).To(ConsistOf(And(
+				HaveField("Component", "csi-addons"),
+				HaveField("Issuer", csiv1.CertManagerCertificateIssuer),
+				HaveField("NotAfter", BeNil()),
+			)))
+			Expect(errors.IsNotFound(k8sClient.Get(
+				ctx,
+				types.NamespacedName{Name: resourceName + "-ca", Namespace: "default"},
+				&corev1.Secret{},
+			))).To(BeTrue())
+
+			csiDriver := &storagev1.CSIDriver{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName}, csiDriver)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, csiDriver)).To(Succeed())
+		})
+	})
+
+	Context("When reconciling a resource with snapshot metadata", func() {
+		const resourceName = "smd.rbd.csi.ceph.com"
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		It("should expose the snapshot-metadata sidecar to backup applications", func() {
+			driver := &csiv1.Driver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: csiv1.DriverSpec{
+					SnapshotMetadata: &csiv1.SnapshotMetadataSpec{
+						ClientServiceAccounts: []csiv1.ServiceAccountReference{{Name: "backup", Namespace: "velero"}},
+						ManageClientTokens:    ptr.To(true),
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, driver)).To(Succeed())
+
+			controllerReconciler := &DriverReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Creating the service of the sidecar")
+			service := &corev1.Service{}
+			serviceKey := types.NamespacedName{Name: "smd-rbd-csi-ceph-com-snapshot-metadata", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, serviceKey, service)).To(Succeed())
+			Expect(service.Spec.Selector).To(HaveKeyWithValue("app", resourceName+"-ctrlplugin"))
+			Expect(service.Spec.Ports).To(ConsistOf(And(
+				HaveField("Port", int32(snapshotMetadataServicePort)),
+				HaveField("TargetPort.IntVal", utils.SnapshotMetadataGrpcPort.ContainerPort),
+			)))
+
+			By("Advertising the sidecar with a SnapshotMetadataService")
+			secret := &corev1.Secret{}
+			secretKey := types.NamespacedName{Name: resourceName + "-snapshot-metadata-tls", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, secretKey, secret)).To(Succeed())
+			sms := &unstructured.Unstructured{}
+			sms.SetGroupVersionKind(snapshotMetadataServiceGroupVersionKind)
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName}, sms)).To(Succeed())
+			Expect(sms.Object).To(HaveKeyWithValue("spec", And(
+				HaveKeyWithValue("address", serviceKey.Name+".default:6443"),
+				HaveKeyWithValue("audience", resourceName),
+				HaveKeyWithValue("caCert", base64.StdEncoding.EncodeToString(secret.Data["ca.crt"])),
+			)))
+
+			By("Granting the backup applications access to the snapshot metadata")
+			clusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+			clusterRoleBindingKey := types.NamespacedName{Name: "smd-rbd-csi-ceph-com-snapshot-metadata-client"}
+			Expect(k8sClient.Get(ctx, clusterRoleBindingKey, clusterRoleBinding)).To(Succeed())
+			Expect(clusterRoleBinding.RoleRef.Name).To(Equal(clusterRoleBindingKey.Name))
+			Expect(clusterRoleBinding.Subjects).To(ConsistOf(rbacv1.Subject{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      "backup",
+				Namespace: "velero",
+			}))
+			clusterRole := &rbacv1.ClusterRole{}
+			Expect(k8sClient.Get(ctx, clusterRoleBindingKey, clusterRole)).To(Succeed())
+			Expect(clusterRole.Rules).NotTo(ContainElement(HaveField("Resources", ContainElement("serviceaccounts/token"))))
+			roleKey := types.NamespacedName{Name: clusterRoleBindingKey.Name, Namespace: "velero"}
+			role := &rbacv1.Role{}
+			Expect(k8sClient.Get(ctx, roleKey, role)).To(Succeed())
+			Expect(role.Rules).To(ConsistOf(And(
+				HaveField("Resources", ConsistOf("serviceaccounts/token")),
+				HaveField("ResourceNames", ConsistOf("backup")),
+			)))
+			roleBinding := &rbacv1.RoleBinding{}
+			Expect(k8sClient.Get(ctx, roleKey, roleBinding)).To(Succeed())
+			Expect(roleBinding.Subjects).To(ConsistOf(HaveField("Name", "backup")))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, driver)).To(Succeed())
+			Expect(driver.Finalizers).To(ContainElement(snapshotMetadataFinalizer))
+
+			By("Removing the token roles when the driver does not manage the client tokens")
+			driver.Spec.SnapshotMetadata.ManageClientTokens = nil
+			Expect(k8sClient.Update(ctx, driver)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, roleKey, role))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, roleKey, roleBinding))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, clusterRoleBindingKey, clusterRoleBinding)).To(Succeed())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, driver)).To(Succeed())
+
+			By("Removing the resources when snapshot metadata is disabled")
+			driver.Spec.SnapshotMetadata = nil
+			Expect(k8sClient.Update(ctx, driver)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, serviceKey, service))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName}, sms))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, clusterRoleBindingKey, clusterRoleBinding))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, clusterRoleBindingKey, clusterRole))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, roleKey, role))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, roleKey, roleBinding))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, driver)).To(Succeed())
+			Expect(driver.Finalizers).NotTo(ContainElement(snapshotMetadataFinalizer))
+
+			By("Removing the cluster resources when the driver is deleted")
+			driver.Spec.SnapshotMetadata = &csiv1.SnapshotMetadataSpec{}
+			Expect(k8sClient.Update(ctx, driver)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, clusterRoleBindingKey, clusterRole)).To(Succeed())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, clusterRoleBindingKey, clusterRoleBinding))).To(BeTrue())
+
+			// An invalid desired state does not keep the driver from being deleted
+			Expect(k8sClient.Get(ctx, typeNamespacedName, driver)).To(Succeed())
+			driver.Spec.ImageSet = &corev1.LocalObjectReference{Name: "missing-image-set"}
+			Expect(k8sClient.Update(ctx, driver)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, driver)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, driver))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName}, sms))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, clusterRoleBindingKey, clusterRole))).To(BeTrue())
+
+			csiDriver := &storagev1.CSIDriver{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName}, csiDriver)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, csiDriver)).To(Succeed())
+		})
+	})
+
+	Context("When reconciling a resource with monitoring", func() {
+		const resourceName = "monitoring.cephfs.csi.ceph.com"
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		It("should expose the driver metrics to Prometheus", func() {
+			driver := &csiv1.Driver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: csiv1.DriverSpec{
+					Liveness: &csiv1.LivenessSpec{MetricsPort: 9081},
+					Monitoring: &csiv1.MonitoringSpec{
+						Labels:   map[string]string{"release": "prometheus"},
+						Interval: &metav1.Duration{Duration: time.Minute},
+						Alerts: &csiv1.MonitoringAlertsSpec{
+							GrpcErrorRatioPercent: ptr.To(int32(25)),
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, driver)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, driver)).To(Succeed())
+			}()
+
+			controllerReconciler := &DriverReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Serving the sidecar metrics")
+			deploy := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-ctrlplugin", Namespace: "default"}, deploy)).
+				To(Succeed())
+			Expect(deploy.Spec.Template.Spec.Containers).To(ContainElements(
+				And(HaveField("Name", "csi-provisioner"), HaveField("Args", ContainElement("--http-endpoint=:8190"))),
+				And(HaveField("Name", "csi-snapshotter"), HaveField("Args", ContainElement("--http-endpoint=:8193"))),
+			))
+
+			By("Creating metrics services selecting the plugin pods")
+			service := &corev1.Service{}
+			ctrlPluginServiceKey := types.NamespacedName{Name: "monitoring-cephfs-csi-ceph-com-ctrlplugin-metrics", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, ctrlPluginServiceKey, service)).To(Succeed())
+			Expect(service.Spec.Selector).To(HaveKeyWithValue("app", resourceName+"-ctrlplugin"))
+			Expect(service.Spec.Ports).To(HaveLen(5))
+			Expect(service.Spec.Ports[0].TargetPort.IntValue()).To(Equal(9081))
+			nodePluginServiceKey := types.NamespacedName{Name: "monitoring-cephfs-csi-ceph-com-nodeplugin-metrics", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, nodePluginServiceKey, service)).To(Succeed())
+			Expect(service.Spec.Selector).To(HaveKeyWithValue("contains", resourceName+"-nodeplugin-metrics"))
+			Expect(service.Spec.Ports).To(ConsistOf(HaveField("Name", livenessMetricsPortName)))
+
+			np := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-ctrlplugin", Namespace: "default"}, np)).
+				To(Succeed())
+			Expect(np.Spec.Ingress).To(ContainElement(HaveField("Ports", HaveLen(5))))
+
+			By("Creating the ServiceMonitor and the PrometheusRule")
+			serviceMonitor := &unstructured.Unstructured{}
+			serviceMonitor.SetGroupVersionKind(serviceMonitorGroupVersionKind)
+			serviceMonitorKey := types.NamespacedName{Name: resourceName + "-metrics", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, serviceMonitorKey, serviceMonitor)).To(Succeed())
+			Expect(serviceMonitor.GetLabels()).To(HaveKeyWithValue("release", "prometheus"))
+			endpoints, _, _ := unstructured.NestedSlice(serviceMonitor.Object, "spec", "endpoints")
+			Expect(endpoints).To(HaveLen(5))
+			Expect(endpoints[0]).To(And(
+				HaveKeyWithValue("port", livenessMetricsPortName),
+				HaveKeyWithValue("interval", "60s"),
+			))
+
+			prometheusRule := &unstructured.Unstructured{}
+			prometheusRule.SetGroupVersionKind(prometheusRuleGroupVersionKind)
+			prometheusRuleKey := types.NamespacedName{Name: resourceName + "-alerts", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, prometheusRuleKey, prometheusRule)).To(Succeed())
+			groups, _, _ := unstructured.NestedSlice(prometheusRule.Object, "spec", "groups")
+			Expect(groups).To(HaveLen(1))
+			Expect(groups[0]).To(HaveKeyWithValue("rules", ConsistOf(
+				And(HaveKeyWithValue("alert", "CephCSIPluginDown"), HaveKeyWithValue("for", "300s")),
+				And(
+					HaveKeyWithValue("alert", "CephCSIHighGRPCErrorRatio"),
+					HaveKeyWithValue("expr", HaveSuffix("> 25")),
+				),
+				And(
+					HaveKeyWithValue("alert", "CephCSINodePluginMissing"),
+					HaveKeyWithValue("expr", ContainSubstring("monitoring\\.cephfs\\.csi\\.ceph\\.com-nodeplugin")),
+				),
+			)))
+
+			By("Scraping the liveness metrics over TLS")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, driver)).To(Succeed())
+			driver.Spec.Liveness.TLS = ptr.To(true)
+			Expect(k8sClient.Update(ctx, driver)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, serviceMonitorKey, serviceMonitor)).To(Succeed())
+			endpoints, _, _ = unstructured.NestedSlice(serviceMonitor.Object, "spec", "endpoints")
+			Expect(endpoints[0]).To(And(
+				HaveKeyWithValue("scheme", "https"),
+				HaveKeyWithValue("bearerTokenFile", "/var/run/secrets/kubernetes.io/serviceaccount/token"),
+				HaveKeyWithValue("tlsConfig", HaveKeyWithValue(
+					"serverName", "monitoring-cephfs-csi-ceph-com-ctrlplugin-metrics.default.svc",
+				)),
+			))
+
+			By("Removing the Prometheus resources when monitoring is disabled")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, driver)).To(Succeed())
+			driver.Spec.Monitoring = nil
+			Expect(k8sClient.Update(ctx, driver)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, serviceMonitorKey, serviceMonitor))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, prometheusRuleKey, prometheusRule))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, ctrlPluginServiceKey, service)).To(Succeed())
+			Expect(service.Spec.Ports).To(HaveLen(1))
+
+			csiDriver := &storagev1.CSIDriver{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName}, csiDriver)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, csiDriver)).To(Succeed())
+		})
+	})
+
+	Context("When collecting operator metrics", func() {
+		const resourceName = "metrics.nfs.csi.ceph.com"
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		// metricValue sums the samples of a gathered metric family matching the provided label
+		metricValue := func(registry *prometheus.Registry, name, labelName, labelValue string) float64 {
+			families, err := registry.Gather()
+			Expect(err).NotTo(HaveOccurred())
+			value := 0.0
+			for _, family := range families {
+				if family.GetName() != name {
+					continue
+				}
+				for _, metric := range family.GetMetric() {
+					for _, label := range metric.GetLabel() {
+						if label.GetName() != labelName || label.GetValue() != labelValue {
+							continue
+						}
+						switch {
+						case metric.GetGauge() != nil:
+							value += metric.GetGauge().GetValue()
+						case metric.GetCounter() != nil:
+							value += metric.GetCounter().GetValue()
+						case metric.GetHistogram() != nil:
+							value += float64(metric.GetHistogram().GetSampleCount())
+						}
+					}
+				}
+			}
+			return value
+		}
+
+		It("should report the inventory, reconcile steps and drift corrections", func() {
+			recorder := events.NewFakeRecorder(50)
+			registry := prometheus.NewPedanticRegistry()
+			Expect(RegisterMetrics(registry, k8sClient)).To(Succeed())
+
+			driver := &csiv1.Driver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+			}
+			Expect(k8sClient.Create(ctx, driver)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, driver)).To(Succeed())
+			}()
+
+			By("Counting drivers by type")
+			Expect(metricValue(registry, "ceph_csi_operator_drivers", "type", "nfs")).To(BeNumerically(">=", 1))
+			Expect(metricValue(registry, "ceph_csi_operator_client_profile_replications", "phase", "Rejected")).
+				To(BeZero())
+
+			controllerReconciler := &DriverReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+			stepsBefore := metricValue(registry, "ceph_csi_operator_driver_reconcile_step_duration_seconds", "step", "csi_driver")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Observing the reconcile steps")
+			Expect(metricValue(registry, "ceph_csi_operator_driver_reconcile_step_duration_seconds", "step", "csi_driver")).
+				To(Equal(stepsBefore + 1))
+
+			By("Counting the correction of a modified deployment")
+			driftBefore := metricValue(registry, "ceph_csi_operator_drift_corrections_total", "kind", "Deployment")
+			deploy := &appsv1.Deployment{}
+			deployKey := types.NamespacedName{Name: resourceName + "-ctrlplugin", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, deployKey, deploy)).To(Succeed())
+			deploy.Spec.Replicas = ptr.To(int32(5))
+			Expect(k8sClient.Update(ctx, deploy)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, deployKey, deploy)).To(Succeed())
+			Expect(deploy.Spec.Replicas).NotTo(Equal(ptr.To(int32(5))))
+			Expect(metricValue(registry, "ceph_csi_operator_drift_corrections_total", "kind", "Deployment")).
+				To(Equal(driftBefore + 1))
+			Expect(recorder.Events).To(Receive(And(
+				HavePrefix(corev1.EventTypeNormal+" "+driftCorrectedReason),
+				ContainSubstring("Deployment "+deploy.Name),
+			)))
+
+			By("Recording the rollout of a node plugin daemonset with a reverted pod template")
+			daemonSet := &appsv1.DaemonSet{}
+			daemonSetKey := types.NamespacedName{Name: resourceName + "-nodeplugin", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, daemonSetKey, daemonSet)).To(Succeed())
+			daemonSet.Spec.Template.Spec.PriorityClassName = "drifted"
+			Expect(k8sClient.Update(ctx, daemonSet)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			recorded := []string{}
+			for len(recorder.Events) > 0 {
+				recorded = append(recorded, <-recorder.Events)
+			}
+			Expect(recorded).To(ContainElements(
+				And(HavePrefix(corev1.EventTypeNormal+" "+daemonSetRolledReason), ContainSubstring(daemonSet.Name)),
+				And(HavePrefix(corev1.EventTypeNormal+" "+driftCorrectedReason), ContainSubstring("DaemonSet "+daemonSet.Name)),
+			))
+
+			By("Not counting an update of the desired state as a drift correction")
+			driftBefore = metricValue(registry, "ceph_csi_operator_drift_corrections_total", "kind", "Deployment")
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "drift-node"}}
+			Expect(k8sClient.Create(ctx, node)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, node)).To(Succeed())
+			}()
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, deployKey, deploy)).To(Succeed())
+			Expect(deploy.Spec.Replicas).To(Equal(ptr.To(int32(1))))
+			Expect(metricValue(registry, "ceph_csi_operator_drift_corrections_total", "kind", "Deployment")).
+				To(Equal(driftBefore))
+			for len(recorder.Events) > 0 {
+				Expect(<-recorder.Events).NotTo(HavePrefix(corev1.EventTypeNormal + " " + driftCorrectedReason))
+			}
+		})
+	})
+
+	Context("csiDriverChangedFields", func() {
+		It("should report the changed fields by their json name", func() {
+			current := &storagev1.CSIDriverSpec{AttachRequired: ptr.To(true), PodInfoOnMount: ptr.To(true)}
+			desired := current.DeepCopy()
+			Expect(csiDriverChangedFields(current, desired)).To(BeEmpty())
+
+			desired.AttachRequired = ptr.To(false)
+			desired.VolumeLifecycleModes = []storagev1.VolumeLifecycleMode{storagev1.VolumeLifecycleEphemeral}
+			Expect(csiDriverChangedFields(current, desired)).To(ConsistOf("attachRequired", "volumeLifecycleModes"))
+		})
+	})
+
+	Context("When reconciling a resource with CSIDriver settings", func() {
+		const resourceName = "csidriver.cephfs.csi.ceph.com"
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		It("should set the CSIDriver spec from the driver spec", func() {
+			driver := &csiv1.Driver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: csiv1.DriverSpec{
+					SeLinuxMount:                       ptr.To(false),
+					TokenRequests:                      []storagev1.TokenRequest{{Audience: "vault"}},
+					RequiresRepublish:                  ptr.To(true),
+					StorageCapacity:                    ptr.To(true),
+					NodeAllocatableUpdatePeriodSeconds: ptr.To(int64(60)),
+				},
+			}
+			Expect(k8sClient.Create(ctx, driver)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, driver)).To(Succeed())
+			}()
+
+			controllerReconciler := &DriverReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			csiDriver := &storagev1.CSIDriver{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName}, csiDriver)).To(Succeed())
+			Expect(csiDriver.Spec.PodInfoOnMount).To(HaveValue(BeTrue()))
+			Expect(csiDriver.Spec.SELinuxMount).To(HaveValue(BeFalse()))
+			Expect(csiDriver.Spec.TokenRequests).To(Equal(driver.Spec.TokenRequests))
+			Expect(csiDriver.Spec.RequiresRepublish).To(HaveValue(BeTrue()))
+			Expect(csiDriver.Spec.StorageCapacity).To(HaveValue(BeTrue()))
+			Expect(csiDriver.Spec.VolumeLifecycleModes).To(
+				Equal([]storagev1.VolumeLifecycleMode{storagev1.VolumeLifecyclePersistent}),
+			)
+			Expect(csiDriver.Spec.NodeAllocatableUpdatePeriodSeconds).To(HaveValue(Equal(int64(60))))
+
+			By("Enabling capacity tracking on the provisioner")
+			deploy := &appsv1.Deployment{}
+			deployKey := types.NamespacedName{Name: resourceName + "-ctrlplugin", Namespace: "default"}
+			Expect(k8sClient.Get(ctx, deployKey, deploy)).To(Succeed())
+			Expect(deploy.Spec.Template.Spec.Containers).To(ContainElement(And(
+				HaveField("Name", "csi-provisioner"),
+				HaveField("Args", ContainElements("--enable-capacity=true", "--capacity-ownerref-level=2")),
+				HaveField("Env", ContainElements(
+					HaveField("Name", "POD_NAME"),
+					HaveField("Name", "NAMESPACE"),
+				)),
+			)))
+			Expect(k8sClient.Delete(ctx, csiDriver)).To(Succeed())
+		})
+	})
 })
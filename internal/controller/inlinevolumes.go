@@ -0,0 +1,156 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	csiv1 "github.com/ceph/ceph-csi-operator/api/v1"
+)
+
+// inlineVolumesValidationExpression admits pods whose inline volumes of the driver use a ClientProfile, set
+// through the clusterID volume attribute, allowing the namespace of the pod
+const inlineVolumesValidationExpression = `!has(object.spec.volumes) || object.spec.volumes.all(v,
+  !has(v.csi) || v.csi.driver != %s || (
+    has(v.csi.volumeAttributes) && "clusterID" in v.csi.volumeAttributes &&
+    v.csi.volumeAttributes["clusterID"] in variables.allowedNamespaces &&
+    variables.allowedNamespaces[v.csi.volumeAttributes["clusterID"]].exists(ns, ns == "*" || ns == request.namespace)
+  )
+)`
+
+// inlineVolumesEnabled returns whether the driver serves inline ephemeral volumes
+func (r *driverReconcile) inlineVolumesEnabled() bool {
+	return slices.Contains(r.driver.Spec.VolumeLifecycleModes, storagev1.VolumeLifecycleEphemeral)
+}
+
+// reconcileInlineVolumesPolicy reconciles the ValidatingAdmissionPolicy restricting the inline ephemeral
+// volumes of the driver to the namespaces allowed by their ClientProfile. The policy and its binding are
+// cluster scoped, they are marked as owned by the driver through the owner ref annotation
+func (r *driverReconcile) reconcileInlineVolumesPolicy(ctx context.Context) error {
+	policy := &admissionregistrationv1.ValidatingAdmissionPolicy{}
+	policy.Name = r.generateName("inline-volumes")
+	binding := &admissionregistrationv1.ValidatingAdmissionPolicyBinding{}
+	binding.Name = policy.Name
+
+	log := r.log.WithValues("validatingAdmissionPolicy", policy.Name)
+	log.V(1).Info("Reconciling inline volumes admission policy")
+
+	if !r.inlineVolumesEnabled() {
+		return errors.Join(
+			r.deleteOwnedClusterResource(ctx, binding),
+			r.deleteOwnedClusterResource(ctx, policy),
+		)
+	}
+
+	clientProfileList := csiv1.ClientProfileList{}
+	if err := r.List(ctx, &clientProfileList, client.InNamespace(r.driver.Namespace)); err != nil {
+		log.Error(err, "Failed listing ClientProfile CRs in namespace", "namespace", r.driver.Namespace)
+		return err
+	}
+
+	opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, policy, func() error {
+		if err := r.setOwnerRefAnnotation(policy); err != nil {
+			return err
+		}
+		// Server side defaults are set explicitly to keep the policy from being updated on every reconcile
+		policy.Spec = admissionregistrationv1.ValidatingAdmissionPolicySpec{
+			FailurePolicy: ptr.To(admissionregistrationv1.Fail),
+			MatchConstraints: &admissionregistrationv1.MatchResources{
+				NamespaceSelector: &metav1.LabelSelector{},
+				ObjectSelector:    &metav1.LabelSelector{},
+				MatchPolicy:       ptr.To(admissionregistrationv1.Equivalent),
+				// The volumes of a pod are immutable, only created pods are validated
+				ResourceRules: []admissionregistrationv1.NamedRuleWithOperations{{
+					RuleWithOperations: admissionregistrationv1.RuleWithOperations{
+						Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+						Rule: admissionregistrationv1.Rule{
+							APIGroups:   []string{""},
+							APIVersions: []string{"v1"},
+							Resources:   []string{"pods"},
+							Scope:       ptr.To(admissionregistrationv1.AllScopes),
+						},
+					},
+				}},
+			},
+			Variables: []admissionregistrationv1.Variable{{
+				Name:       "allowedNamespaces",
+				Expression: inlineVolumesAllowedNamespacesExpression(clientProfileList.Items),
+			}},
+			Validations: []admissionregistrationv1.Validation{{
+				Expression: fmt.Sprintf(inlineVolumesValidationExpression, strconv.Quote(r.driver.Name)),
+				Message: fmt.Sprintf(
+					"inline volumes of driver %s are only allowed in the namespaces listed in "+
+						"the inlineVolumes.allowedNamespaces of their ClientProfile",
+					r.driver.Name,
+				),
+			}},
+		}
+		return nil
+	})
+	r.logCreateOrUpdateResult(log, "ValidatingAdmissionPolicy", policy, opResult, err)
+	if err != nil {
+		return err
+	}
+
+	log = r.log.WithValues("validatingAdmissionPolicyBinding", binding.Name)
+	opResult, err = ctrlutil.CreateOrUpdate(ctx, r.Client, binding, func() error {
+		if err := r.setOwnerRefAnnotation(binding); err != nil {
+			return err
+		}
+		binding.Spec.PolicyName = policy.Name
+		binding.Spec.ValidationActions = []admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny}
+		return nil
+	})
+	r.logCreateOrUpdateResult(log, "ValidatingAdmissionPolicyBinding", binding, opResult, err)
+	return err
+}
+
+// inlineVolumesAllowedNamespacesExpression returns a CEL map of the namespaces allowed to use inline
+// volumes by ClientProfile name. ClientProfiles without an allowlist are left out, their inline volumes
+// are rejected
+func inlineVolumesAllowedNamespacesExpression(clientProfiles []csiv1.ClientProfile) string {
+	entries := []string{}
+	for i := range clientProfiles {
+		inlineVolumes := clientProfiles[i].Spec.InlineVolumes
+		if inlineVolumes == nil || clientProfiles[i].DeletionTimestamp != nil {
+			continue
+		}
+		namespaces := make([]string, len(inlineVolumes.AllowedNamespaces))
+		for j, namespace := range inlineVolumes.AllowedNamespaces {
+			namespaces[j] = strconv.Quote(namespace)
+		}
+		entries = append(entries, fmt.Sprintf(
+			"%s: [%s]",
+			strconv.Quote(clientProfiles[i].Name),
+			strings.Join(namespaces, ", "),
+		))
+	}
+	slices.Sort(entries)
+	return fmt.Sprintf("{%s}", strings.Join(entries, ", "))
+}
@@ -0,0 +1,324 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	csiv1 "github.com/ceph/ceph-csi-operator/api/v1"
+	"github.com/ceph/ceph-csi-operator/internal/utils"
+)
+
+const (
+	operatorMetricsNamespace = "ceph_csi_operator"
+
+	// The phase reported for resources that were not reconciled yet
+	unknownPhase = "Unknown"
+
+	// Upper bound for listing the inventory of resources on a scrape
+	inventoryListTimeout = 10 * time.Second
+)
+
+var (
+	driverReconcileStepDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: operatorMetricsNamespace,
+			Name:      "driver_reconcile_step_duration_seconds",
+			Help:      "Duration of the driver reconcile steps",
+			Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"step"},
+	)
+
+	driverReconcileStepErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: operatorMetricsNamespace,
+			Name:      "driver_reconcile_step_errors_total",
+			Help:      "Number of failed driver reconcile steps",
+		},
+		[]string{"step"},
+	)
+
+	driftCorrections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: operatorMetricsNamespace,
+			Name:      "drift_corrections_total",
+			Help:      "Number of driver owned resources reverted to their desired state while the driver and operator config were unchanged",
+		},
+		[]string{"kind"},
+	)
+
+	csiConfigSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: operatorMetricsNamespace,
+			Name:      "csi_config_size_bytes",
+			Help:      "Size of the ceph-csi cluster configuration stored in the ceph-csi-config ConfigMap",
+		},
+		[]string{"namespace"},
+	)
+
+	driversDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(operatorMetricsNamespace, "", "drivers"),
+		"Number of Drivers by driver type",
+		[]string{"type"},
+		nil,
+	)
+
+	clientProfilesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(operatorMetricsNamespace, "", "client_profiles"),
+		"Number of ClientProfiles by phase",
+		[]string{"phase"},
+		nil,
+	)
+
+	clientProfileReplicationsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(operatorMetricsNamespace, "", "client_profile_replications"),
+		"Number of ClientProfileReplications by phase",
+		[]string{"phase"},
+		nil,
+	)
+)
+
+// The hashes of the state the driver owned resources were last applied with. Used to tell drift
+// corrections apart from updates of the desired state.
+var appliedStates sync.Map
+
+type appliedStateKey struct {
+	driver    types.NamespacedName
+	kind      string
+	namespace string
+	name      string
+}
+
+// RegisterMetrics registers the operator metrics with the provided registerer. The resource inventory
+// is listed from the provided reader on every scrape.
+func RegisterMetrics(registerer prometheus.Registerer, reader client.Reader) error {
+	return errors.Join(
+		registerer.Register(driverReconcileStepDuration),
+		registerer.Register(driverReconcileStepErrors),
+		registerer.Register(driftCorrections),
+		registerer.Register(csiConfigSize),
+		registerer.Register(&inventoryCollector{reader: reader}),
+	)
+}
+
+// inventoryCollector reports the number of operator custom resources by type or phase
+type inventoryCollector struct {
+	reader client.Reader
+}
+
+func (c *inventoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- driversDesc
+	ch <- clientProfilesDesc
+	ch <- clientProfileReplicationsDesc
+}
+
+func (c *inventoryCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), inventoryListTimeout)
+	defer cancel()
+
+	driverList := csiv1.DriverList{}
+	if err := c.reader.List(ctx, &driverList); err != nil {
+		ch <- prometheus.NewInvalidMetric(driversDesc, err)
+	} else {
+		counts := map[string]int{}
+		for _, driverType := range []DriverType{RbdDriverType, CephFsDriverType, NfsDriverType, NvmeofDriverType} {
+			counts[string(driverType)] = 0
+		}
+		for i := range driverList.Items {
+			if matches := nameRegExp.FindStringSubmatch(driverList.Items[i].Name); len(matches) == 2 {
+				counts[strings.ToLower(matches[1])]++
+			}
+		}
+		collectCounts(ch, driversDesc, counts)
+	}
+
+	clientProfileList := csiv1.ClientProfileList{}
+	if err := c.reader.List(ctx, &clientProfileList); err != nil {
+		ch <- prometheus.NewInvalidMetric(clientProfilesDesc, err)
+	} else {
+		counts := phaseCounts(
+			csiv1.ClientProfilePhaseReady,
+			csiv1.ClientProfilePhaseFailed,
+			csiv1.ClientProfilePhasePending,
+		)
+		for i := range clientProfileList.Items {
+			counts[phaseOrUnknown(clientProfileList.Items[i].Status.Phase)]++
+		}
+		collectCounts(ch, clientProfilesDesc, counts)
+	}
+
+	replicationList := csiv1.ClientProfileReplicationList{}
+	if err := c.reader.List(ctx, &replicationList); err != nil {
+		ch <- prometheus.NewInvalidMetric(clientProfileReplicationsDesc, err)
+	} else {
+		counts := phaseCounts(
+			csiv1.ClientProfileReplicationPhaseReady,
+			csiv1.ClientProfileReplicationPhaseRejected,
+			csiv1.ClientProfileReplicationPhasePending,
+		)
+		for i := range replicationList.Items {
+			counts[phaseOrUnknown(replicationList.Items[i].Status.Phase)]++
+		}
+		collectCounts(ch, clientProfileReplicationsDesc, counts)
+	}
+}
+
+// phaseCounts returns zeroed counts for the known phases, so that they are reported even when empty
+func phaseCounts(phases ...string) map[string]int {
+	counts := map[string]int{unknownPhase: 0}
+	for _, phase := range phases {
+		counts[phase] = 0
+	}
+	return counts
+}
+
+func phaseOrUnknown(phase string) string {
+	if phase == "" {
+		return unknownPhase
+	}
+	return phase
+}
+
+func collectCounts(ch chan<- prometheus.Metric, desc *prometheus.Desc, counts map[string]int) {
+	labels := make([]string, 0, len(counts))
+	for label := range counts {
+		labels = append(labels, label)
+	}
+	slices.Sort(labels)
+	for _, label := range labels {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(counts[label]), label)
+	}
+}
+
+// observeReconcileStep wraps a driver reconcile step, recording its duration and failures, and tracing
+// it as a child span of the reconcile span. The step runs with the context of its span, so that the
+// client calls it makes are traced under it.
+func observeReconcileStep(ctx context.Context, step string, reconcileStep func(context.Context) error) func() error {
+	return func() error {
+		stepCtx, span := utils.StartSpan(ctx, "reconcile."+step, attribute.String("step", step))
+		start := time.Now()
+		err := reconcileStep(stepCtx)
+		driverReconcileStepDuration.WithLabelValues(step).Observe(time.Since(start).Seconds())
+		if err != nil {
+			driverReconcileStepErrors.WithLabelValues(step).Inc()
+		}
+		utils.EndSpan(span, err)
+		return err
+	}
+}
+
+// startReconcileSpan starts the root span of a reconcile iteration
+func startReconcileSpan(ctx context.Context, kind string, req ctrl.Request) (context.Context, trace.Span) {
+	return utils.StartSpan(
+		ctx,
+		kind+".Reconcile",
+		attribute.String("k8s.kind", kind),
+		attribute.String("k8s.namespace", req.Namespace),
+		attribute.String("k8s.name", req.Name),
+	)
+}
+
+// recordAppliedState remembers the state a driver owned resource was applied with, and counts its
+// update as a drift correction when the applied state did not change since the previous reconcile,
+// i.e. the resource was modified by someone else and reverted to its desired state. Updates of the
+// desired state, e.g. a rolled certificate, an updated image set or a node change, are not counted.
+func (r *driverReconcile) recordAppliedState(obj client.Object, opRes ctrlutil.OperationResult) {
+	kind := "Unknown"
+	if gvk, err := apiutil.GVKForObject(obj, r.Scheme); err == nil {
+		kind = gvk.Kind
+	}
+	hash, err := appliedStateHash(obj)
+	if err != nil {
+		r.log.Error(err, "Failed to hash the applied state", "kind", kind, "name", obj.GetName())
+		return
+	}
+	key := appliedStateKey{
+		driver:    client.ObjectKeyFromObject(&r.driver),
+		kind:      kind,
+		namespace: obj.GetNamespace(),
+		name:      obj.GetName(),
+	}
+	previous, found := appliedStates.Swap(key, hash)
+	if opRes != ctrlutil.OperationResultUpdated || !found || previous != hash {
+		return
+	}
+	driftCorrections.WithLabelValues(kind).Inc()
+	r.recordEvent(
+		corev1.EventTypeNormal,
+		driftCorrectedReason,
+		"Update",
+		"Reverted %s %s to its desired state",
+		kind,
+		obj.GetName(),
+	)
+}
+
+// forgetAppliedStates drops the applied states of the resources owned by a deleted driver
+func forgetAppliedStates(driver types.NamespacedName) {
+	appliedStates.Range(func(key, _ any) bool {
+		if key.(appliedStateKey).driver == driver {
+			appliedStates.Delete(key)
+		}
+		return true
+	})
+}
+
+// appliedStateHash hashes the state of a resource the operator manages, its labels and everything
+// besides its metadata and status, which are also written by the API server and other controllers
+func appliedStateHash(obj client.Object) (string, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return "", err
+	}
+	delete(content, "status")
+	content["metadata"] = obj.GetLabels()
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// recordCsiConfigSize reports the size of the data stored in a ceph-csi-config ConfigMap
+func recordCsiConfigSize(configMap *corev1.ConfigMap) {
+	size := 0
+	for key, value := range configMap.Data {
+		size += len(key) + len(value)
+	}
+	csiConfigSize.WithLabelValues(configMap.Namespace).Set(float64(size))
+}
@@ -0,0 +1,444 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	csiv1 "github.com/ceph/ceph-csi-operator/api/v1"
+	"github.com/ceph/ceph-csi-operator/internal/utils"
+)
+
+const (
+	defaultMonitoringInterval    = 30 * time.Second
+	defaultPluginDownFor         = 5 * time.Minute
+	defaultGrpcErrorRatioPercent = 10
+	defaultGrpcErrorRatioFor     = 15 * time.Minute
+	defaultNodePluginMissingFor  = 10 * time.Minute
+
+	// Service port of the metrics served by the liveness sidecar
+	livenessMetricsPortName    = "csi-http-metrics"
+	livenessMetricsServicePort = 8080
+)
+
+var serviceMonitorGroupVersionKind = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "ServiceMonitor",
+}
+
+var prometheusRuleGroupVersionKind = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "PrometheusRule",
+}
+
+// Controller plugin CSI sidecars serving metrics, in the order of their metrics ports
+var controllerPluginMetricsSidecars = []string{
+	"csi-provisioner",
+	"csi-resizer",
+	"csi-attacher",
+	"csi-snapshotter",
+}
+
+// controllerPluginSidecarMetricsPorts returns the metrics ports of the controller plugin CSI sidecars,
+// keyed by container name, when monitoring is enabled
+func (r *driverReconcile) controllerPluginSidecarMetricsPorts() map[string]int32 {
+	monitoring := r.driver.Spec.Monitoring
+	if monitoring == nil {
+		return nil
+	}
+
+	// The drivers use different ports, as the controller plugins may run on the host network
+	basePort := ptr.Deref(monitoring.SidecarMetricsPort, 0)
+	if basePort == 0 {
+		switch r.driverType {
+		case RbdDriverType:
+			basePort = 8090
+		case CephFsDriverType:
+			basePort = 8190
+		case NfsDriverType:
+			basePort = 8290
+		case NvmeofDriverType:
+			basePort = 8390
+		}
+	}
+
+	snapshotter := cmp.Or(r.driver.Spec.SnapshotPolicy, csiv1.VolumeSnapshotSnapshotPolicy) != csiv1.NoneSnapshotPolicy
+	ports := map[string]int32{}
+	for i, container := range controllerPluginMetricsSidecars {
+		if container != "csi-snapshotter" || snapshotter {
+			ports[container] = basePort + int32(i)
+		}
+	}
+	return ports
+}
+
+// metricsServiceNames returns the names of the controller plugin and node plugin metrics services
+func (r *driverReconcile) metricsServiceNames() (string, string) {
+	return r.generateServiceName("ctrlplugin-metrics"), r.generateServiceName("nodeplugin-metrics")
+}
+
+// reconcileMetricsServices exposes the metrics of the controller plugin and node plugin pods
+func (r *driverReconcile) reconcileMetricsServices(ctx context.Context) error {
+	ctrlPluginServiceName, nodePluginServiceName := r.metricsServiceNames()
+
+	livenessPort := []corev1.ServicePort{}
+	if r.driver.Spec.Liveness != nil {
+		livenessPort = append(livenessPort, corev1.ServicePort{
+			Name:       livenessMetricsPortName,
+			Port:       livenessMetricsServicePort,
+			Protocol:   corev1.ProtocolTCP,
+			TargetPort: intstr.FromInt(r.driver.Spec.Liveness.MetricsPort),
+		})
+	}
+	ctrlPluginPorts := slices.Clone(livenessPort)
+	sidecarPorts := r.controllerPluginSidecarMetricsPorts()
+	for _, container := range controllerPluginMetricsSidecars {
+		if port, ok := sidecarPorts[container]; ok {
+			ctrlPluginPorts = append(ctrlPluginPorts, corev1.ServicePort{
+				Name:       fmt.Sprintf("%s-metrics", container),
+				Port:       port,
+				Protocol:   corev1.ProtocolTCP,
+				TargetPort: intstr.FromInt32(port),
+			})
+		}
+	}
+
+	// The liveness service of previous versions did not select any pod
+	legacyService := &corev1.Service{}
+	legacyService.Name = r.generateServiceName("liveness")
+	legacyService.Namespace = r.driver.Namespace
+
+	return errors.Join(
+		r.reconcileMetricsService(
+			ctx,
+			ctrlPluginServiceName,
+			map[string]string{"app": appLabelValue(r.generateName("ctrlplugin"))},
+			ctrlPluginPorts,
+		),
+		r.reconcileMetricsService(
+			ctx,
+			nodePluginServiceName,
+			map[string]string{"contains": fmt.Sprintf("%s-metrics", r.generateName("nodeplugin"))},
+			livenessPort,
+		),
+		r.deleteOwnedObject(ctx, legacyService),
+	)
+}
+
+// reconcileMetricsService reconciles a metrics service selecting the given pods, the service is
+// removed when no port is exposed
+func (r *driverReconcile) reconcileMetricsService(
+	ctx context.Context,
+	name string,
+	selector map[string]string,
+	ports []corev1.ServicePort,
+) error {
+	service := &corev1.Service{}
+	service.Namespace = r.driver.Namespace
+	service.Name = name
+
+	log := r.log.WithValues("service", service.Name)
+	log.Info("Reconciling metrics service")
+
+	if len(ports) == 0 {
+		return r.deleteOwnedObject(ctx, service)
+	}
+
+	opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, service, func() error {
+		if err := ctrlutil.SetControllerReference(&r.driver, service, r.Scheme); err != nil {
+			log.Error(err, "Failed setting an owner reference on service")
+			return err
+		}
+		if service.Labels == nil {
+			service.Labels = map[string]string{}
+		}
+		service.Labels["app"] = service.Name
+		service.Spec.Selector = selector
+		service.Spec.Ports = ports
+		return nil
+	})
+
+	r.logCreateOrUpdateResult(log, "metrics service", service, opResult, err)
+	return err
+}
+
+// reconcileServiceMonitor reconciles the ServiceMonitor scraping the driver metrics services, when the
+// Prometheus operator CRDs are installed. The CSI-Addons sidecars serve no metrics, the CSI-Addons metrics
+// are scraped from the CSI-Addons controller manager, which is not deployed by the operator
+func (r *driverReconcile) reconcileServiceMonitor(ctx context.Context) error {
+	serviceMonitor := &unstructured.Unstructured{}
+	serviceMonitor.SetGroupVersionKind(serviceMonitorGroupVersionKind)
+	serviceMonitor.SetName(r.generateName("metrics"))
+	serviceMonitor.SetNamespace(r.driver.Namespace)
+
+	log := r.log.WithValues("serviceMonitor", serviceMonitor.GetName())
+	log.Info("Reconciling ServiceMonitor")
+
+	monitoring := r.driver.Spec.Monitoring
+	if monitoring == nil {
+		return r.deleteOwnedObject(ctx, serviceMonitor)
+	}
+
+	ctrlPluginServiceName, nodePluginServiceName := r.metricsServiceNames()
+	interval := prometheusDuration(cmp.Or(monitoring.Interval, &metav1.Duration{Duration: defaultMonitoringInterval}))
+
+	livenessEndpoint := map[string]any{
+		"port":     livenessMetricsPortName,
+		"interval": interval,
+	}
+	if r.metricsTLSEnabled() {
+		livenessEndpoint["scheme"] = "https"
+		livenessEndpoint["tlsConfig"] = map[string]any{
+			"ca": map[string]any{
+				"secret": map[string]any{
+					"name": r.certificateSecretName(metricsCertificateComponent),
+					"key":  caCertKey,
+				},
+			},
+			// The certificate is valid for both metrics services
+			"serverName": fmt.Sprintf("%s.%s.svc", ctrlPluginServiceName, r.driver.Namespace),
+		}
+		// The metrics proxy authorizes Prometheus through its service account token
+		livenessEndpoint["bearerTokenFile"] = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	}
+	endpoints := []any{livenessEndpoint}
+	sidecarPorts := r.controllerPluginSidecarMetricsPorts()
+	for _, container := range controllerPluginMetricsSidecars {
+		if _, ok := sidecarPorts[container]; ok {
+			endpoints = append(endpoints, map[string]any{
+				"port":     fmt.Sprintf("%s-metrics", container),
+				"interval": interval,
+			})
+		}
+	}
+
+	opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, serviceMonitor, func() error {
+		if err := ctrlutil.SetControllerReference(&r.driver, serviceMonitor, r.Scheme); err != nil {
+			return err
+		}
+		serviceMonitor.SetLabels(utils.Call(func() map[string]string {
+			labels := serviceMonitor.GetLabels()
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			maps.Copy(labels, monitoring.Labels)
+			return labels
+		}))
+		serviceMonitor.Object["spec"] = map[string]any{
+			"namespaceSelector": map[string]any{
+				"matchNames": []any{r.driver.Namespace},
+			},
+			"selector": map[string]any{
+				"matchExpressions": []any{
+					map[string]any{
+						"key":      "app",
+						"operator": "In",
+						"values":   []any{ctrlPluginServiceName, nodePluginServiceName},
+					},
+				},
+			},
+			"endpoints": endpoints,
+		}
+		return nil
+	})
+	return r.logMonitoringResult(log, "ServiceMonitor", serviceMonitor, opResult, err)
+}
+
+// reconcilePrometheusRule reconciles the PrometheusRule holding the default driver alerts, when the
+// Prometheus operator CRDs are installed
+func (r *driverReconcile) reconcilePrometheusRule(ctx context.Context) error {
+	prometheusRule := &unstructured.Unstructured{}
+	prometheusRule.SetGroupVersionKind(prometheusRuleGroupVersionKind)
+	prometheusRule.SetName(r.generateName("alerts"))
+	prometheusRule.SetNamespace(r.driver.Namespace)
+
+	log := r.log.WithValues("prometheusRule", prometheusRule.GetName())
+	log.Info("Reconciling PrometheusRule")
+
+	monitoring := r.driver.Spec.Monitoring
+	alerts := cmp.Or(ptr.Deref(monitoring, csiv1.MonitoringSpec{}).Alerts, &csiv1.MonitoringAlertsSpec{})
+	if monitoring == nil || !ptr.Deref(alerts.Enabled, true) {
+		return r.deleteOwnedObject(ctx, prometheusRule)
+	}
+
+	ctrlPluginServiceName, nodePluginServiceName := r.metricsServiceNames()
+	namespace := r.driver.Namespace
+	services := fmt.Sprintf("%s|%s", ctrlPluginServiceName, nodePluginServiceName)
+	sidecarOperations := fmt.Sprintf(
+		`csi_sidecar_operations_seconds_count{namespace="%s",service="%s"}`,
+		namespace,
+		ctrlPluginServiceName,
+	)
+	failedSidecarOperations := fmt.Sprintf(
+		`csi_sidecar_operations_seconds_count{namespace="%s",service="%s",grpc_status_code!="OK"}`,
+		namespace,
+		ctrlPluginServiceName,
+	)
+	nodePluginDaemonSets := fmt.Sprintf("`%s(-pool-.+)?`", regexp.QuoteMeta(r.generateName("nodeplugin")))
+
+	rules := []any{
+		map[string]any{
+			"alert": "CephCSIPluginDown",
+			"expr": fmt.Sprintf(
+				`up{namespace="%[1]s",service=~"%[2]s",endpoint="%[3]s"} == 0 or csi_liveness{namespace="%[1]s",service=~"%[2]s"} == 0`,
+				namespace,
+				services,
+				livenessMetricsPortName,
+			),
+			"for": prometheusDuration(cmp.Or(alerts.PluginDownFor, &metav1.Duration{Duration: defaultPluginDownFor})),
+			"labels": map[string]any{
+				"severity": "critical",
+			},
+			"annotations": map[string]any{
+				"summary": fmt.Sprintf("A %s plugin is down", r.driver.Name),
+				"description": fmt.Sprintf(
+					"Pod {{ $labels.pod }} of the %s driver in namespace %s failed its liveness check.",
+					r.driver.Name,
+					namespace,
+				),
+			},
+		},
+		map[string]any{
+			"alert": "CephCSIHighGRPCErrorRatio",
+			"expr": fmt.Sprintf(
+				`100 * sum by (method_name) (rate(%s[5m])) / sum by (method_name) (rate(%s[5m])) > %d`,
+				failedSidecarOperations,
+				sidecarOperations,
+				ptr.Deref(alerts.GrpcErrorRatioPercent, defaultGrpcErrorRatioPercent),
+			),
+			"for": prometheusDuration(
+				cmp.Or(alerts.GrpcErrorRatioFor, &metav1.Duration{Duration: defaultGrpcErrorRatioFor}),
+			),
+			"labels": map[string]any{
+				"severity": "warning",
+			},
+			"annotations": map[string]any{
+				"summary": fmt.Sprintf("High gRPC error ratio of the %s controller plugin", r.driver.Name),
+				"description": fmt.Sprintf(
+					"{{ $value | humanize }}%% of the {{ $labels.method_name }} calls of the %s driver failed.",
+					r.driver.Name,
+				),
+			},
+		},
+		map[string]any{
+			"alert": "CephCSINodePluginMissing",
+			"expr": fmt.Sprintf(
+				`kube_daemonset_status_desired_number_scheduled{namespace="%[1]s",daemonset=~%[2]s} - kube_daemonset_status_number_available{namespace="%[1]s",daemonset=~%[2]s} > 0`,
+				namespace,
+				nodePluginDaemonSets,
+			),
+			"for": prometheusDuration(
+				cmp.Or(alerts.NodePluginMissingFor, &metav1.Duration{Duration: defaultNodePluginMissingFor}),
+			),
+			"labels": map[string]any{
+				"severity": "warning",
+			},
+			"annotations": map[string]any{
+				"summary": fmt.Sprintf("A %s node plugin is missing", r.driver.Name),
+				"description": fmt.Sprintf(
+					"{{ $value }} node plugin pods of daemonset {{ $labels.daemonset }} in namespace %s are not available, "+
+						"volumes of the %s driver cannot be mounted on the affected nodes.",
+					namespace,
+					r.driver.Name,
+				),
+			},
+		},
+	}
+
+	opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, prometheusRule, func() error {
+		if err := ctrlutil.SetControllerReference(&r.driver, prometheusRule, r.Scheme); err != nil {
+			return err
+		}
+		prometheusRule.SetLabels(utils.Call(func() map[string]string {
+			labels := prometheusRule.GetLabels()
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			maps.Copy(labels, monitoring.Labels)
+			return labels
+		}))
+		prometheusRule.Object["spec"] = map[string]any{
+			"groups": []any{
+				map[string]any{
+					"name":  r.driver.Name,
+					"rules": rules,
+				},
+			},
+		}
+		return nil
+	})
+	return r.logMonitoringResult(log, "PrometheusRule", prometheusRule, opResult, err)
+}
+
+// logMonitoringResult logs the outcome of reconciling a Prometheus operator resource, which is skipped
+// when the Prometheus operator CRDs are not installed
+func (r *driverReconcile) logMonitoringResult(
+	log logr.Logger,
+	subject string,
+	obj client.Object,
+	opResult ctrlutil.OperationResult,
+	err error,
+) error {
+	if meta.IsNoMatchError(err) {
+		log.Info(fmt.Sprintf("The %s CRD is not installed, skipping", subject))
+		return nil
+	}
+	r.logCreateOrUpdateResult(log, subject, obj, opResult, err)
+	return err
+}
+
+// deleteOwnedObject deletes a namespaced resource controlled by the driver. Resources that are not
+// controlled by the driver, and missing resource types, are ignored
+func (r *driverReconcile) deleteOwnedObject(ctx context.Context, obj client.Object) error {
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); k8serrors.IsNotFound(err) ||
+		meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(obj, &r.driver) {
+		return nil
+	}
+	return client.IgnoreNotFound(r.Delete(ctx, obj))
+}
+
+// prometheusDuration formats a duration in the format of the Prometheus configuration
+func prometheusDuration(duration *metav1.Duration) string {
+	return fmt.Sprintf("%ds", int64(duration.Seconds()))
+}
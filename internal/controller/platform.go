@@ -0,0 +1,316 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	csiv1 "github.com/ceph/ceph-csi-operator/api/v1"
+	"github.com/ceph/ceph-csi-operator/internal/utils"
+)
+
+const (
+	openShiftNodeOSLabelKey           = "node.openshift.io/os_id"
+	microK8sNodeLabelKey              = "microk8s.io/cluster"
+	k3sNodeArgsAnnotationKey          = "k3s.io/node-args"
+	rke2NodeArgsAnnotationKey         = "rke2.io/node-args"
+	openShiftRequiredSCCAnnotationKey = "openshift.io/required-scc"
+
+	// The name of the SCC shipped with the OpenShift deployment manifests of the operator
+	openShiftSCCName = "ceph-csi-scc"
+
+	// Max number of node names reported for a single kubelet dir path mismatch
+	maxReportedMismatchingNodes = 10
+)
+
+var openShiftSCCGroupVersionKind = schema.GroupVersionKind{
+	Group:   "security.openshift.io",
+	Version: "v1",
+	Kind:    "SecurityContextConstraints",
+}
+
+// The default kubelet root directory of platforms not using defaultKubeletDirPath
+var platformKubeletDirPaths = map[csiv1.PlatformType]string{
+	csiv1.K0sPlatformType:      "/var/lib/k0s/kubelet",
+	csiv1.MicroK8sPlatformType: "/var/snap/microk8s/common/var/lib/kubelet",
+}
+
+// The packaged cluster DNS component of platforms whose servers can be started without it
+var platformClusterDNSComponents = map[csiv1.PlatformType]string{
+	csiv1.K3sPlatformType:  "coredns",
+	csiv1.RKE2PlatformType: "rke2-coredns",
+}
+
+// Distributions that tag the Kubernetes version they ship with a build metadata suffix
+var platformVersionSuffixes = []struct {
+	suffix   string
+	platform csiv1.PlatformType
+}{
+	{"+rke2", csiv1.RKE2PlatformType},
+	{"+k3s", csiv1.K3sPlatformType},
+	{"+k0s", csiv1.K0sPlatformType},
+}
+
+// platformFromVersion detects the platform from the build metadata of a Kubernetes version,
+// returns an empty string if the version does not identify a platform
+func platformFromVersion(gitVersion string) csiv1.PlatformType {
+	for _, entry := range platformVersionSuffixes {
+		if strings.Contains(gitVersion, entry.suffix) {
+			return entry.platform
+		}
+	}
+	return ""
+}
+
+// detectNodePlatform detects the platform of a node based on its labels, annotations
+// and kubelet version
+func detectNodePlatform(node *corev1.Node) csiv1.PlatformType {
+	switch {
+	case node.Labels[openShiftNodeOSLabelKey] != "":
+		return csiv1.OpenShiftPlatformType
+	case node.Labels[microK8sNodeLabelKey] != "":
+		return csiv1.MicroK8sPlatformType
+	case node.Annotations[rke2NodeArgsAnnotationKey] != "":
+		return csiv1.RKE2PlatformType
+	case node.Annotations[k3sNodeArgsAnnotationKey] != "":
+		return csiv1.K3sPlatformType
+	}
+	return cmp.Or(platformFromVersion(node.Status.NodeInfo.KubeletVersion), csiv1.KubernetesPlatformType)
+}
+
+// detectClusterPlatform detects the platform of the cluster from the API server version, falling
+// back to the most common platform across nodes
+func detectClusterPlatform(serverVersion *version.Info, nodes []corev1.Node) csiv1.PlatformType {
+	if serverVersion != nil {
+		if platform := platformFromVersion(serverVersion.GitVersion); platform != "" {
+			return platform
+		}
+	}
+
+	counts := map[csiv1.PlatformType]int{}
+	for i := range nodes {
+		counts[detectNodePlatform(&nodes[i])]++
+	}
+	// Iterate in sorted order to break ties consistently
+	platform := csiv1.KubernetesPlatformType
+	for _, candidate := range slices.Sorted(maps.Keys(counts)) {
+		if counts[candidate] > counts[platform] {
+			platform = candidate
+		}
+	}
+	return platform
+}
+
+// platformKubeletDirPath returns the default kubelet root directory of a platform
+func platformKubeletDirPath(platform csiv1.PlatformType) string {
+	return cmp.Or(platformKubeletDirPaths[platform], defaultKubeletDirPath)
+}
+
+// detectNodeKubeletDirPath returns the kubelet root directory of a node, and whether it could be
+// detected. The directory is only known for nodes of a detected platform, as the kubelet
+// configuration of a generic Kubernetes node is not visible through the API.
+func detectNodeKubeletDirPath(node *corev1.Node) (string, bool) {
+	platform := detectNodePlatform(node)
+	switch platform {
+	case csiv1.KubernetesPlatformType:
+		return "", false
+	case csiv1.K3sPlatformType, csiv1.RKE2PlatformType:
+		// K3s and RKE2 publish the arguments the node was started with, including
+		// user provided kubelet arguments
+		if rootDir := kubeletRootDirFromNodeArgs(node.Annotations[nodeArgsAnnotationKey(platform)]); rootDir != "" {
+			return rootDir, true
+		}
+	}
+	return platformKubeletDirPath(platform), true
+}
+
+// nodeArgsAnnotationKey returns the annotation K3s and RKE2 publish the node arguments in
+func nodeArgsAnnotationKey(platform csiv1.PlatformType) string {
+	return utils.If(platform == csiv1.K3sPlatformType, k3sNodeArgsAnnotationKey, rke2NodeArgsAnnotationKey)
+}
+
+// nodeArgValues returns the values of a flag in a JSON encoded list of K3s/RKE2 node arguments,
+// e.g. ["server","--kubelet-arg","root-dir=/data/kubelet","--disable=traefik"]
+func nodeArgValues(nodeArgs string, flag string) []string {
+	args := []string{}
+	if err := json.Unmarshal([]byte(nodeArgs), &args); err != nil {
+		return nil
+	}
+
+	values := []string{}
+	for i, arg := range args {
+		value, found := strings.CutPrefix(arg, flag+"=")
+		if !found {
+			if arg != flag || i+1 >= len(args) {
+				continue
+			}
+			value = args[i+1]
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// kubeletRootDirFromNodeArgs extracts the kubelet root-dir from a JSON encoded list of K3s/RKE2
+// node arguments, e.g. ["server","--kubelet-arg","root-dir=/data/kubelet"]
+func kubeletRootDirFromNodeArgs(nodeArgs string) string {
+	rootDir := ""
+	for _, kubeletArg := range nodeArgValues(nodeArgs, "--kubelet-arg") {
+		// The last occurrence wins, similar to the kubelet flag parsing
+		if value, found := strings.CutPrefix(strings.TrimPrefix(kubeletArg, "--"), "root-dir="); found {
+			rootDir = value
+		}
+	}
+	return rootDir
+}
+
+// clusterDNSDisabled returns whether the cluster runs without the DNS component packaged with its
+// platform, i.e. a K3s or RKE2 server was started with the component disabled
+func clusterDNSDisabled(nodes []corev1.Node, platform csiv1.PlatformType) bool {
+	component, found := platformClusterDNSComponents[platform]
+	if !found {
+		return false
+	}
+	for i := range nodes {
+		for _, disabled := range nodeArgValues(nodes[i].Annotations[nodeArgsAnnotationKey(platform)], "--disable") {
+			if slices.Contains(strings.Split(disabled, ","), component) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// podDNSPolicy returns the DNS policy of driver pods. Pods resolve names through the cluster DNS,
+// e.g. the FQDN of Rook orchestrated mons, unless the platform runs without it.
+func (r *driverReconcile) podDNSPolicy(hostNetwork bool) corev1.DNSPolicy {
+	switch {
+	case r.clusterDNSDisabled:
+		return corev1.DNSDefault
+	case hostNetwork:
+		return corev1.DNSClusterFirstWithHostNet
+	}
+	return corev1.DNSClusterFirst
+}
+
+// nodeChangedPredicate filters node updates down to the changes affecting the detected platform,
+// kubelet directories and DNS policy, or the node pools of the node
+var nodeChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, oldOk := e.ObjectOld.(*corev1.Node)
+		newNode, newOk := e.ObjectNew.(*corev1.Node)
+		return !oldOk || !newOk ||
+			!maps.Equal(oldNode.Labels, newNode.Labels) ||
+			oldNode.Annotations[k3sNodeArgsAnnotationKey] != newNode.Annotations[k3sNodeArgsAnnotationKey] ||
+			oldNode.Annotations[rke2NodeArgsAnnotationKey] != newNode.Annotations[rke2NodeArgsAnnotationKey] ||
+			oldNode.Status.NodeInfo.KubeletVersion != newNode.Status.NodeInfo.KubeletVersion
+	},
+}
+
+// detectKubeletDirPath returns the kubelet root directory detected on the given nodes, falling back
+// to the default path of the cluster platform when nodes do not agree on a single directory
+func detectKubeletDirPath(nodes []*corev1.Node, clusterPlatform csiv1.PlatformType) string {
+	detected := ""
+	for _, node := range nodes {
+		path, known := detectNodeKubeletDirPath(node)
+		if !known {
+			continue
+		}
+		if detected != "" && detected != path {
+			return platformKubeletDirPath(clusterPlatform)
+		}
+		detected = path
+	}
+	return cmp.Or(detected, platformKubeletDirPath(clusterPlatform))
+}
+
+// kubeletDirPathMismatches reports the nodes whose detected kubelet root directory differs from
+// kubeletDirPath, grouped by the detected directory
+func kubeletDirPathMismatches(
+	daemonSetName string,
+	pool *csiv1.NodePoolSpec,
+	kubeletDirPath string,
+	nodes []*corev1.Node,
+) []csiv1.KubeletDirPathMismatchStatus {
+	nodeNames := map[string][]string{}
+	for _, node := range nodes {
+		if path, known := detectNodeKubeletDirPath(node); known && path != kubeletDirPath {
+			nodeNames[path] = append(nodeNames[path], node.Name)
+		}
+	}
+
+	mismatches := []csiv1.KubeletDirPathMismatchStatus{}
+	for _, path := range slices.Sorted(maps.Keys(nodeNames)) {
+		names := slices.Sorted(slices.Values(nodeNames[path]))
+		mismatches = append(mismatches, csiv1.KubeletDirPathMismatchStatus{
+			DaemonSet:              daemonSetName,
+			NodePool:               cmp.Or(pool, &csiv1.NodePoolSpec{}).Name,
+			KubeletDirPath:         kubeletDirPath,
+			DetectedKubeletDirPath: path,
+			NodeCount:              len(names),
+			Nodes:                  names[:min(len(names), maxReportedMismatchingNodes)],
+		})
+	}
+	return mismatches
+}
+
+// filterNodesBySelector returns the nodes matching a node selector, a nil selector matches all nodes.
+// Only label based match expressions are evaluated, which are the ones generated for node pools.
+func filterNodesBySelector(nodes []corev1.Node, nodeSelector *corev1.NodeSelector) []*corev1.Node {
+	selectors := []labels.Selector{}
+	if nodeSelector != nil {
+		for _, term := range nodeSelector.NodeSelectorTerms {
+			labelSelector := &metav1.LabelSelector{}
+			for _, expr := range term.MatchExpressions {
+				labelSelector.MatchExpressions = append(labelSelector.MatchExpressions, metav1.LabelSelectorRequirement{
+					Key:      expr.Key,
+					Operator: metav1.LabelSelectorOperator(expr.Operator),
+					Values:   expr.Values,
+				})
+			}
+			selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+			if err != nil {
+				continue
+			}
+			selectors = append(selectors, selector)
+		}
+	}
+
+	matching := []*corev1.Node{}
+	for i := range nodes {
+		node := &nodes[i]
+		// Node selector terms are ORed
+		if nodeSelector == nil || slices.ContainsFunc(selectors, func(selector labels.Selector) bool {
+			return selector.Matches(labels.Set(node.Labels))
+		}) {
+			matching = append(matching, node)
+		}
+	}
+	return matching
+}
@@ -0,0 +1,423 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	csiv1 "github.com/ceph/ceph-csi-operator/api/v1"
+	"github.com/ceph/ceph-csi-operator/internal/utils"
+)
+
+const (
+	// Finalizer removing the cluster scoped snapshot-metadata resources of a driver, which
+	// cannot be garbage collected through owner references
+	snapshotMetadataFinalizer = "csi.ceph.io/snapshot-metadata"
+
+	// Port of the snapshot-metadata service, as advertised to backup applications
+	snapshotMetadataServicePort = 6443
+
+	// Label set on the namespaced RBAC granting backup applications tokens for their own service accounts
+	snapshotMetadataClientLabelKey = "csi.ceph.io/snapshot-metadata-client"
+)
+
+var snapshotMetadataServiceGroupVersionKind = schema.GroupVersionKind{
+	Group:   "cbt.storage.k8s.io",
+	Version: "v1beta1",
+	Kind:    "SnapshotMetadataService",
+}
+
+// snapshotMetadataEnabled returns whether the snapshot-metadata sidecar and the resources exposing it
+// are managed for the driver. The sidecar deployed through the deprecated tls-key volume is exposed
+// by the admin
+func (r *driverReconcile) snapshotMetadataEnabled() bool {
+	return r.isRbdDriver() && r.driver.Spec.SnapshotMetadata != nil
+}
+
+// reconcileSnapshotMetadataService exposes the snapshot-metadata sidecar of the controller plugin
+func (r *driverReconcile) reconcileSnapshotMetadataService(ctx context.Context) error {
+	service := &corev1.Service{}
+	service.Namespace = r.driver.Namespace
+	service.Name = r.generateServiceName("snapshot-metadata")
+
+	log := r.log.WithValues("service", service.Name)
+	log.V(1).Info("Reconciling snapshot-metadata service")
+
+	// A service created by the admin for the deprecated tls-key volume is left in place
+	if !r.snapshotMetadataEnabled() {
+		return r.deleteOwnedObject(ctx, service)
+	}
+
+	opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, service, func() error {
+		if err := ctrlutil.SetControllerReference(&r.driver, service, r.Scheme); err != nil {
+			log.Error(err, "Failed setting an owner reference on service")
+			return err
+		}
+
+		service.Spec.Selector = map[string]string{"app": appLabelValue(r.generateName("ctrlplugin"))}
+		service.Spec.Ports = []corev1.ServicePort{
+			{
+				Name:       "snapshot-metadata-port",
+				Port:       snapshotMetadataServicePort,
+				Protocol:   corev1.ProtocolTCP,
+				TargetPort: intstr.FromInt32(utils.SnapshotMetadataGrpcPort.ContainerPort),
+			},
+		}
+		return nil
+	})
+
+	r.logCreateOrUpdateResult(log, "snapshot-metadata service", service, opResult, err)
+	return err
+}
+
+// reconcileSnapshotMetadataClusterResources reconciles the SnapshotMetadataService advertising the
+// sidecar to backup applications, and the RBAC granting them access to it. These resources are
+// cluster scoped, a finalizer on the driver removes them when the driver is deleted
+func (r *driverReconcile) reconcileSnapshotMetadataClusterResources(ctx context.Context) error {
+	if !r.snapshotMetadataEnabled() || r.driver.DeletionTimestamp != nil {
+		return r.deleteSnapshotMetadataClusterResources(ctx)
+	}
+
+	if err := r.updateSnapshotMetadataFinalizer(ctx, true); err != nil {
+		return err
+	}
+	return errors.Join(
+		r.reconcileSnapshotMetadataServiceResource(ctx),
+		r.reconcileSnapshotMetadataClientRBAC(ctx),
+	)
+}
+
+// reconcileSnapshotMetadataServiceResource reconciles the SnapshotMetadataService of the driver, named
+// after the driver as expected by the backup applications
+func (r *driverReconcile) reconcileSnapshotMetadataServiceResource(ctx context.Context) error {
+	log := r.log.WithValues("snapshotMetadataService", r.driver.Name)
+	log.V(1).Info("Reconciling SnapshotMetadataService")
+
+	// The CA bundle of the sidecar certificate, set in the secret by the operator and cert-manager
+	secret := &corev1.Secret{}
+	secret.Name = r.snapshotMetadataSecretName()
+	secret.Namespace = r.driver.Namespace
+	if err := r.Get(ctx, client.ObjectKeyFromObject(secret), secret); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	caCert := secret.Data[caCertKey]
+	if len(caCert) == 0 {
+		return fmt.Errorf("waiting for the %s key of the snapshot-metadata certificate secret %s", caCertKey, secret.Name)
+	}
+
+	sms := &unstructured.Unstructured{}
+	sms.SetGroupVersionKind(snapshotMetadataServiceGroupVersionKind)
+	sms.SetName(r.driver.Name)
+
+	opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, sms, func() error {
+		if err := r.setOwnerRefAnnotation(sms); err != nil {
+			return err
+		}
+		sms.Object["spec"] = map[string]any{
+			"address": fmt.Sprintf(
+				"%s.%s:%d",
+				r.generateServiceName("snapshot-metadata"),
+				r.driver.Namespace,
+				snapshotMetadataServicePort,
+			),
+			"audience": r.driver.Name,
+			"caCert":   base64.StdEncoding.EncodeToString(caCert),
+		}
+		return nil
+	})
+	r.logCreateOrUpdateResult(log, "SnapshotMetadataService", sms, opResult, err)
+	if meta.IsNoMatchError(err) {
+		return fmt.Errorf("snapshotMetadata requires the SnapshotMetadataService CRD to be installed: %w", err)
+	}
+	return err
+}
+
+// reconcileSnapshotMetadataClientRBAC reconciles the cluster role granting backup applications access
+// to the snapshot metadata, and binds it to the client service accounts listed on the driver. The
+// tokens of the client service accounts are granted by roles in their own namespaces, when the driver
+// opts in to it
+func (r *driverReconcile) reconcileSnapshotMetadataClientRBAC(ctx context.Context) error {
+	clusterRole := &rbacv1.ClusterRole{}
+	clusterRole.Name = r.generateServiceName("snapshot-metadata-client")
+
+	log := r.log.WithValues("clusterRole", clusterRole.Name)
+	log.V(1).Info("Reconciling snapshot-metadata client RBAC")
+
+	opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, clusterRole, func() error {
+		if err := r.setOwnerRefAnnotation(clusterRole); err != nil {
+			return err
+		}
+		clusterRole.Rules = []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{snapshotMetadataServiceGroupVersionKind.Group},
+				Resources: []string{"snapshotmetadataservices"},
+				Verbs:     []string{"get", "list"},
+			},
+			{
+				APIGroups: []string{"snapshot.storage.k8s.io"},
+				Resources: []string{"volumesnapshots", "volumesnapshotcontents"},
+				Verbs:     []string{"get", "list"},
+			},
+		}
+		return nil
+	})
+	r.logCreateOrUpdateResult(log, "ClusterRole", clusterRole, opResult, err)
+	if err != nil {
+		return err
+	}
+
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+	clusterRoleBinding.Name = clusterRole.Name
+	snapshotMetadata := r.driver.Spec.SnapshotMetadata
+	clientServiceAccounts := snapshotMetadata.ClientServiceAccounts
+	// Granting tokens requires the operator to hold the serviceaccounts/token permission, which is
+	// only installed on demand. Without it the token roles are removed
+	if err := r.reconcileSnapshotMetadataClientTokenRBAC(
+		ctx,
+		utils.If(ptr.Deref(snapshotMetadata.ManageClientTokens, false), clientServiceAccounts, nil),
+	); err != nil {
+		return err
+	}
+	if len(clientServiceAccounts) == 0 {
+		return r.deleteOwnedClusterResource(ctx, clusterRoleBinding)
+	}
+
+	log = r.log.WithValues("clusterRoleBinding", clusterRoleBinding.Name)
+	opResult, err = ctrlutil.CreateOrUpdate(ctx, r.Client, clusterRoleBinding, func() error {
+		if err := r.setOwnerRefAnnotation(clusterRoleBinding); err != nil {
+			return err
+		}
+		clusterRoleBinding.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     clusterRole.Name,
+		}
+		clusterRoleBinding.Subjects = utils.MapSlice(
+			clientServiceAccounts,
+			func(sa csiv1.ServiceAccountReference) rbacv1.Subject {
+				return rbacv1.Subject{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      sa.Name,
+					Namespace: sa.Namespace,
+				}
+			},
+		)
+		return nil
+	})
+	r.logCreateOrUpdateResult(log, "ClusterRoleBinding", clusterRoleBinding, opResult, err)
+	return err
+}
+
+// reconcileSnapshotMetadataClientTokenRBAC reconciles a role and a role binding in each namespace of
+// the client service accounts, allowing them to request tokens bound to the driver audience for
+// themselves only. The roles and role bindings of namespaces without client service accounts are deleted
+func (r *driverReconcile) reconcileSnapshotMetadataClientTokenRBAC(
+	ctx context.Context,
+	clientServiceAccounts []csiv1.ServiceAccountReference,
+) error {
+	name := r.generateServiceName("snapshot-metadata-client")
+	serviceAccountsByNamespace := map[string][]string{}
+	for _, sa := range clientServiceAccounts {
+		if !slices.Contains(serviceAccountsByNamespace[sa.Namespace], sa.Name) {
+			serviceAccountsByNamespace[sa.Namespace] = append(serviceAccountsByNamespace[sa.Namespace], sa.Name)
+		}
+	}
+
+	errList := []error{}
+	for namespace, serviceAccounts := range serviceAccountsByNamespace {
+		slices.Sort(serviceAccounts)
+
+		role := &rbacv1.Role{}
+		role.Name = name
+		role.Namespace = namespace
+		log := r.log.WithValues("role", client.ObjectKeyFromObject(role))
+		opResult, err := ctrlutil.CreateOrUpdate(ctx, r.Client, role, func() error {
+			if err := r.setOwnerRefAnnotation(role); err != nil {
+				return err
+			}
+			utils.AddLabel(role, snapshotMetadataClientLabelKey, "true")
+			role.Rules = []rbacv1.PolicyRule{
+				{
+					// Backup applications authenticate with a token bound to the driver audience
+					APIGroups:     []string{""},
+					Resources:     []string{"serviceaccounts/token"},
+					ResourceNames: serviceAccounts,
+					Verbs:         []string{"create"},
+				},
+			}
+			return nil
+		})
+		r.logCreateOrUpdateResult(log, "Role", role, opResult, err)
+		if err != nil {
+			errList = append(errList, err)
+			continue
+		}
+
+		roleBinding := &rbacv1.RoleBinding{}
+		roleBinding.Name = name
+		roleBinding.Namespace = namespace
+		log = r.log.WithValues("roleBinding", client.ObjectKeyFromObject(roleBinding))
+		opResult, err = ctrlutil.CreateOrUpdate(ctx, r.Client, roleBinding, func() error {
+			if err := r.setOwnerRefAnnotation(roleBinding); err != nil {
+				return err
+			}
+			utils.AddLabel(roleBinding, snapshotMetadataClientLabelKey, "true")
+			roleBinding.RoleRef = rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "Role",
+				Name:     role.Name,
+			}
+			roleBinding.Subjects = utils.MapSlice(serviceAccounts, func(sa string) rbacv1.Subject {
+				return rbacv1.Subject{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      sa,
+					Namespace: namespace,
+				}
+			})
+			return nil
+		})
+		r.logCreateOrUpdateResult(log, "RoleBinding", roleBinding, opResult, err)
+		errList = append(errList, err)
+	}
+
+	errList = append(errList, r.deleteSnapshotMetadataClientTokenRBAC(ctx, func(namespace string) bool {
+		return len(serviceAccountsByNamespace[namespace]) == 0
+	}))
+	return errors.Join(errList...)
+}
+
+// deleteSnapshotMetadataClientTokenRBAC deletes the client token roles and role bindings of the driver
+// in the namespaces selected by the given predicate
+func (r *driverReconcile) deleteSnapshotMetadataClientTokenRBAC(ctx context.Context, stale func(namespace string) bool) error {
+	name := r.generateServiceName("snapshot-metadata-client")
+	errList := []error{}
+	for _, list := range []client.ObjectList{&rbacv1.RoleBindingList{}, &rbacv1.RoleList{}} {
+		if err := r.List(ctx, list, client.HasLabels{snapshotMetadataClientLabelKey}); err != nil {
+			errList = append(errList, err)
+			continue
+		}
+		errList = append(errList, meta.EachListItem(list, func(item runtime.Object) error {
+			obj := item.(client.Object)
+			if obj.GetName() != name || !stale(obj.GetNamespace()) {
+				return nil
+			}
+			return r.deleteOwnedClusterResource(ctx, obj)
+		}))
+	}
+	return errors.Join(errList...)
+}
+
+// deleteSnapshotMetadataClusterResources removes the cluster scoped snapshot-metadata resources of the
+// driver, and the finalizer guarding them
+func (r *driverReconcile) deleteSnapshotMetadataClusterResources(ctx context.Context) error {
+	if !ctrlutil.ContainsFinalizer(&r.driver, snapshotMetadataFinalizer) {
+		return nil
+	}
+
+	sms := &unstructured.Unstructured{}
+	sms.SetGroupVersionKind(snapshotMetadataServiceGroupVersionKind)
+	sms.SetName(r.driver.Name)
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+	clusterRoleBinding.Name = r.generateServiceName("snapshot-metadata-client")
+	clusterRole := &rbacv1.ClusterRole{}
+	clusterRole.Name = clusterRoleBinding.Name
+
+	if err := errors.Join(
+		r.deleteOwnedClusterResource(ctx, sms),
+		r.deleteOwnedClusterResource(ctx, clusterRoleBinding),
+		r.deleteOwnedClusterResource(ctx, clusterRole),
+		r.deleteSnapshotMetadataClientTokenRBAC(ctx, func(string) bool { return true }),
+	); err != nil {
+		r.log.Error(err, "Failed to delete snapshot-metadata cluster resources")
+		return err
+	}
+	return r.updateSnapshotMetadataFinalizer(ctx, false)
+}
+
+// deleteOwnedClusterResource deletes a cluster scoped resource, or a resource of a namespace other than
+// the driver one, carrying the owner ref annotation of the driver. Resources that are not owned by the
+// driver, and missing resource types, are ignored
+func (r *driverReconcile) deleteOwnedClusterResource(ctx context.Context, obj client.Object) error {
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); k8serrors.IsNotFound(err) ||
+		meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	ownerObjKey := client.ObjectKey{}
+	if err := json.Unmarshal([]byte(obj.GetAnnotations()[ownerRefAnnotationKey]), &ownerObjKey); err != nil ||
+		ownerObjKey != client.ObjectKeyFromObject(&r.driver) {
+		return nil
+	}
+	return client.IgnoreNotFound(r.Delete(ctx, obj))
+}
+
+// setOwnerRefAnnotation marks a cluster scoped resource as owned by the driver
+func (r *driverReconcile) setOwnerRefAnnotation(obj client.Object) error {
+	bytes, err := json.Marshal(client.ObjectKeyFromObject(&r.driver))
+	if err != nil {
+		return err
+	}
+	utils.AddAnnotation(obj, ownerRefAnnotationKey, string(bytes))
+	return nil
+}
+
+// updateSnapshotMetadataFinalizer adds or removes the snapshot-metadata finalizer. The driver is patched
+// through its metadata only, as its spec was merged with the operator config defaults
+func (r *driverReconcile) updateSnapshotMetadataFinalizer(ctx context.Context, add bool) error {
+	driver := &csiv1.Driver{}
+	driver.Name = r.driver.Name
+	driver.Namespace = r.driver.Namespace
+	driver.ResourceVersion = r.driver.ResourceVersion
+	driver.Finalizers = slices.Clone(r.driver.Finalizers)
+	base := driver.DeepCopy()
+
+	var changed bool
+	if add {
+		changed = ctrlutil.AddFinalizer(driver, snapshotMetadataFinalizer)
+	} else {
+		changed = ctrlutil.RemoveFinalizer(driver, snapshotMetadataFinalizer)
+	}
+	if !changed {
+		return nil
+	}
+	if err := r.Patch(ctx, driver, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{})); err != nil {
+		r.log.Error(err, "Failed to update the snapshot-metadata finalizer of the driver")
+		return err
+	}
+	r.driver.Finalizers = driver.Finalizers
+	r.driver.ResourceVersion = driver.ResourceVersion
+	return nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Certificates are backdated to tolerate clock skew between the operator and the clients
+const certificateBackdate = 5 * time.Minute
+
+// CertificateAuthority is a certificate authority issuing serving certificates
+type CertificateAuthority struct {
+	Cert    *x509.Certificate
+	CertPEM []byte
+	KeyPEM  []byte
+
+	key crypto.Signer
+}
+
+// NewCertificateAuthority generates a self signed certificate authority
+func NewCertificateAuthority(commonName string, validity time.Duration, now time.Time) (*CertificateAuthority, error) {
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: commonName},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	certPEM, keyPEM, err := createCertificate(template, nil, nil, validity, now)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCertificateAuthority(certPEM, keyPEM)
+}
+
+// ParseCertificateAuthority loads a certificate authority from PEM encoded certificate and key
+func ParseCertificateAuthority(certPEM, keyPEM []byte) (*CertificateAuthority, error) {
+	cert, err := ParseCertificate(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	if !cert.IsCA {
+		return nil, errors.New("certificate is not a certificate authority")
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode private key PEM")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("private key cannot sign certificates")
+	}
+	return &CertificateAuthority{Cert: cert, CertPEM: certPEM, KeyPEM: keyPEM, key: signer}, nil
+}
+
+// IssueServingCertificate issues a TLS serving certificate valid for the given DNS names, returns
+// the PEM encoded certificate and private key
+func (ca *CertificateAuthority) IssueServingCertificate(
+	dnsNames []string,
+	validity time.Duration,
+	now time.Time,
+) ([]byte, []byte, error) {
+	if len(dnsNames) == 0 {
+		return nil, nil, errors.New("serving certificates require at least one DNS name")
+	}
+	// Serving certificates never outlive the certificate authority signing them
+	validity = min(validity, ca.Cert.NotAfter.Sub(now))
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:    dnsNames,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	return createCertificate(template, ca.Cert, ca.key, validity, now)
+}
+
+// ParseCertificate parses the first certificate of a PEM encoded certificate chain
+func ParseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("failed to decode certificate PEM")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// createCertificate generates a key pair and a certificate for it, self signed when parent is nil
+func createCertificate(
+	template, parent *x509.Certificate,
+	parentKey crypto.Signer,
+	validity time.Duration,
+	now time.Time,
+) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	template.SerialNumber = serialNumber
+	template.NotBefore = now.Add(-certificateBackdate)
+	template.NotAfter = now.Add(validity)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer})
+	return certPEM, keyPEM, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertificateAuthority(t *testing.T) {
+	now := time.Now()
+	ca, err := NewCertificateAuthority("test-ca", 24*time.Hour, now)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.True(t, ca.Cert.IsCA)
+	assert.Equal(t, "test-ca", ca.Cert.Subject.CommonName)
+
+	loaded, err := ParseCertificateAuthority(ca.CertPEM, ca.KeyPEM)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.True(t, loaded.Cert.Equal(ca.Cert))
+
+	tests := []struct {
+		name             string
+		dnsNames         []string
+		validity         time.Duration
+		expectedNotAfter time.Time
+		expectErr        bool
+	}{
+		{
+			name:             "issue a serving certificate",
+			dnsNames:         []string{"svc.ns.svc", "svc.ns.svc.cluster.local"},
+			validity:         time.Hour,
+			expectedNotAfter: now.Add(time.Hour),
+		},
+		{
+			name:             "cap the validity to the certificate authority expiry",
+			dnsNames:         []string{"svc.ns.svc"},
+			validity:         48 * time.Hour,
+			expectedNotAfter: ca.Cert.NotAfter,
+		},
+		{
+			name:      "reject certificates without DNS names",
+			validity:  time.Hour,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certPEM, keyPEM, err := loaded.IssueServingCertificate(tt.dnsNames, tt.validity, now)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.NotEmpty(t, keyPEM)
+
+			cert, err := ParseCertificate(certPEM)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, tt.dnsNames, cert.DNSNames)
+			assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, cert.ExtKeyUsage)
+			assert.WithinDuration(t, tt.expectedNotAfter, cert.NotAfter, time.Second)
+			assert.NoError(t, cert.CheckSignatureFrom(ca.Cert))
+		})
+	}
+}
+
+func TestParseCertificateAuthority(t *testing.T) {
+	ca, err := NewCertificateAuthority("test-ca", time.Hour, time.Now())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	certPEM, keyPEM, err := ca.IssueServingCertificate([]string{"svc.ns.svc"}, time.Hour, time.Now())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	_, err = ParseCertificateAuthority(certPEM, keyPEM)
+	assert.Error(t, err, "serving certificates are not certificate authorities")
+	_, err = ParseCertificateAuthority(ca.CertPEM, []byte("invalid"))
+	assert.Error(t, err)
+	_, err = ParseCertificateAuthority([]byte("invalid"), ca.KeyPEM)
+	assert.Error(t, err)
+}
@@ -18,9 +18,12 @@ package utils
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 )
 
@@ -28,6 +31,8 @@ const (
 	SocketDir         = "/csi"
 	csiEndpoint       = "unix://" + SocketDir + "/csi.sock"
 	csiAddonsEndpoint = "unix://" + SocketDir + "/csi-addons.sock"
+	metricsPath       = "/metrics"
+	healthzPath       = "/healthz"
 
 	kmsConfigVolumeName      = "ceph-csi-kms-config"
 	csiMountInfoVolumeName   = "ceph-csi-mountinfo"
@@ -35,6 +40,13 @@ const (
 	pluginDirVolumeName      = "plugin-dir"
 	podsMountDirVolumeName   = "pods-mount-dir"
 	pluginMountDirVolumeName = "plugin-mount-dir"
+	csiAddonsTlsVolumeName   = "csi-addons-tls"
+	csiAddonsTlsDir          = "/etc/csi-addons/tls"
+
+	snapshotMetadataTlsVolumeName = "snapshot-metadata-tls"
+	snapshotMetadataTlsDir        = "/tmp/certificates"
+	metricsTlsVolumeName          = "metrics-tls"
+	metricsTlsDir                 = "/etc/ceph-csi/metrics-tls"
 
 	CsiConfigMapConfigKey  = "config.json"
 	CsiConfigMapMappingKey = "cluster-mapping.json"
@@ -154,6 +166,36 @@ func KmsConfigVolume(configRef *corev1.LocalObjectReference) corev1.Volume {
 		},
 	}
 }
+func CsiAddonsTlsVolume(secretName string) corev1.Volume {
+	return corev1.Volume{
+		Name: csiAddonsTlsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+			},
+		},
+	}
+}
+func SnapshotMetadataTlsVolume(secretName string) corev1.Volume {
+	return corev1.Volume{
+		Name: snapshotMetadataTlsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+			},
+		},
+	}
+}
+func MetricsTlsVolume(secretName string) corev1.Volume {
+	return corev1.Volume{
+		Name: metricsTlsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+			},
+		},
+	}
+}
 func PluginMountDirVolume(kubeletDirPath string) corev1.Volume {
 	return corev1.Volume{
 		Name: pluginMountDirVolumeName,
@@ -266,6 +308,21 @@ var KmsConfigVolumeMount = corev1.VolumeMount{
 	MountPath: "/etc/ceph-csi-encryption-kms-config/",
 	ReadOnly:  true,
 }
+var CsiAddonsTlsVolumeMount = corev1.VolumeMount{
+	Name:      csiAddonsTlsVolumeName,
+	MountPath: csiAddonsTlsDir,
+	ReadOnly:  true,
+}
+var SnapshotMetadataTlsVolumeMount = corev1.VolumeMount{
+	Name:      snapshotMetadataTlsVolumeName,
+	MountPath: snapshotMetadataTlsDir,
+	ReadOnly:  true,
+}
+var MetricsTlsVolumeMount = corev1.VolumeMount{
+	Name:      metricsTlsVolumeName,
+	MountPath: metricsTlsDir,
+	ReadOnly:  true,
+}
 var PluginDirVolumeMount = corev1.VolumeMount{
 	Name:      pluginDirVolumeName,
 	MountPath: "/csi",
@@ -312,6 +369,13 @@ var PodIpEnvVar = corev1.EnvVar{
 		},
 	},
 }
+
+// LoopbackPodIpEnvVar overrides the pod IP of the liveness sidecar, which serves its metrics on the pod
+// IP, to only serve them to the metrics proxy of the pod
+var LoopbackPodIpEnvVar = corev1.EnvVar{
+	Name:  PodIpEnvVar.Name,
+	Value: "127.0.0.1",
+}
 var PodNameEnvVar = corev1.EnvVar{
 	Name: "POD_NAME",
 	ValueFrom: &corev1.EnvVarSource{
@@ -344,6 +408,14 @@ var NodeIdEnvVar = corev1.EnvVar{
 		},
 	},
 }
+var NamespaceEnvVar = corev1.EnvVar{
+	Name: "NAMESPACE",
+	ValueFrom: &corev1.EnvVarSource{
+		FieldRef: &corev1.ObjectFieldSelector{
+			FieldPath: "metadata.namespace",
+		},
+	},
+}
 var DriverNamespaceEnvVar = corev1.EnvVar{
 	Name: "DRIVER_NAMESPACE",
 	ValueFrom: &corev1.EnvVarSource{
@@ -395,24 +467,51 @@ var PodUidContainerArg = fmt.Sprintf("--pod-uid=$(%s)", PodUidEnvVar.Name)
 var PodContainerArg = fmt.Sprintf("--pod=$(%s)", PodNameEnvVar.Name)
 var NamespaceContainerArg = fmt.Sprintf("--namespace=$(%s)", PodNamespaceEnvVar.Name)
 var DriverNamespaceContainerArg = fmt.Sprintf("--drivernamespace=$(%s)", DriverNamespaceEnvVar.Name)
-var MetricsPathContainerArg = "--metricspath=/metrics"
+var MetricsPathContainerArg = fmt.Sprintf("--metricspath=%s", metricsPath)
 var PoolTimeContainerArg = "--polltime=60s"
 var ExtraCreateMetadataContainerArg = "--extra-create-metadata=true"
 var PreventVolumeModeConversionContainerArg = "--prevent-volume-mode-conversion=true"
+var EnableCapacityContainerArg = "--enable-capacity=true"
 var RecoverVolumeExpansionFailureContainerArg = "--feature-gates=RecoverVolumeExpansionFailure=true"
 var EnableVolumeGroupSnapshotsContainerArg = "--feature-gates=CSIVolumeGroupSnapshot=true"
 var ForceCephKernelClientContainerArg = "--forcecephkernelclient=true"
 var LogToStdErrContainerArg = "--logtostderr=false"
 var AlsoLogToStdErrContainerArg = "--alsologtostderr=true"
 var CsiAddonsVolumeConditionArg = "--enable-volume-condition=true"
+var CsiAddonsTlsCertFileContainerArg = fmt.Sprintf("--tls-cert-file=%s/tls.crt", csiAddonsTlsDir)
+var CsiAddonsTlsKeyFileContainerArg = fmt.Sprintf("--tls-key-file=%s/tls.key", csiAddonsTlsDir)
 var SnapshotMetadataGrpcServicePortArg = fmt.Sprintf("--port=%d", SnapshotMetadataGrpcPort.ContainerPort)
-var SnapshotMetadataTlsCertArg = "--tls-cert=/tmp/certificates/tls.crt"
-var SnapshotMetadataTlsKeyArg = "--tls-key=/tmp/certificates/tls.key"
+var SnapshotMetadataTlsCertArg = fmt.Sprintf("--tls-cert=%s/tls.crt", snapshotMetadataTlsDir)
+var SnapshotMetadataTlsKeyArg = fmt.Sprintf("--tls-key=%s/tls.key", snapshotMetadataTlsDir)
+var MetricsProxyTlsCertFileContainerArg = fmt.Sprintf("--tls-cert-file=%s/tls.crt", metricsTlsDir)
+var MetricsProxyTlsKeyFileContainerArg = fmt.Sprintf("--tls-private-key-file=%s/tls.key", metricsTlsDir)
+var MetricsProxyAllowPathsContainerArg = fmt.Sprintf("--allow-paths=%s", metricsPath)
+
+func MetricsProxySecureListenAddressContainerArg(port int) string {
+	return fmt.Sprintf("--secure-listen-address=$(%s):%d", PodIpEnvVar.Name, port)
+}
+func MetricsProxyUpstreamContainerArg(port int) string {
+	return fmt.Sprintf("--upstream=http://%s:%d/", LoopbackPodIpEnvVar.Value, port)
+}
+
+func TlsMinVersionContainerArg(version string) string {
+	return fmt.Sprintf("--tls-min-version=%s", version)
+}
 
 func SnapshotMetadataAudienceArg(driverName string) string {
 	return fmt.Sprintf("--audience=%s", driverName)
 }
 
+// The number of owner references to follow from the provisioner pod to the object owning the
+// CSIStorageCapacity objects, e.g. 2 for a Deployment (Pod -> ReplicaSet -> Deployment)
+func CapacityOwnerrefLevelContainerArg(level int) string {
+	return fmt.Sprintf("--capacity-ownerref-level=%d", level)
+}
+
+func MonitorIntervalContainerArg(interval time.Duration) string {
+	return fmt.Sprintf("--monitor-interval=%s", interval)
+}
+
 func LogVerbosityContainerArg(level int) string {
 	return fmt.Sprintf("--v=%d", Clamp(level, 0, 5))
 }
@@ -433,6 +532,9 @@ func SetFencingContainerArg(on bool) string {
 func TimeoutContainerArg(timeout int) string {
 	return fmt.Sprintf("--timeout=%ds", timeout)
 }
+func ProbeTimeoutContainerArg(timeout int) string {
+	return fmt.Sprintf("--probe-timeout=%ds", timeout)
+}
 func LeaderElectionNamespaceContainerArg(ns string) string {
 	return If(ns != "", fmt.Sprintf("--leader-election-namespace=%s", ns), "")
 }
@@ -454,6 +556,27 @@ func ClusterNameContainerArg(name string) string {
 func MetricsPortContainerArg(port int) string {
 	return fmt.Sprintf("--metricsport=%d", port)
 }
+func HttpEndpointContainerArg(port int32) string {
+	return If(port != 0, fmt.Sprintf("--http-endpoint=:%d", port), "")
+}
+
+// LivenessHealthProbe returns a probe querying the health endpoint served by the livenessprobe sidecar,
+// which fails when the CSI plugin does not answer a Probe call on its socket
+func LivenessHealthProbe(port int32) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: healthzPath,
+				Port: intstr.FromInt32(port),
+			},
+		},
+		InitialDelaySeconds: 10,
+		PeriodSeconds:       30,
+		TimeoutSeconds:      5,
+		FailureThreshold:    3,
+	}
+}
+
 func KubeletRegistrationPathContainerArg(kubeletDirPath string, driverName string) string {
 	return fmt.Sprintf("--kubelet-registration-path=%s/plugins/%s/csi.sock", kubeletDirPath, driverName)
 }
@@ -497,3 +620,43 @@ func GetExtraArgsForContainer(containerName string, extraArgs map[string][]strin
 	}
 	return extraArgs[containerName]
 }
+
+// RemoveArgPrefix marks a user provided extra argument as a request to remove the
+// operator set flag with the same name, e.g. "!--extra-create-metadata"
+const RemoveArgPrefix = "!"
+
+// MergeContainerArgs merges user provided extra arguments into the arguments set by the operator.
+// A flag in extraArgs replaces all flags with the same name in args, while a flag prefixed
+// with RemoveArgPrefix removes them. Any other argument in extraArgs is appended as is.
+func MergeContainerArgs(args []string, extraArgs []string) []string {
+	if len(extraArgs) == 0 {
+		return args
+	}
+
+	overridden := map[string]bool{}
+	userArgs := make([]string, 0, len(extraArgs))
+	for _, arg := range extraArgs {
+		remove := strings.HasPrefix(arg, RemoveArgPrefix)
+		if name := argFlagName(strings.TrimPrefix(arg, RemoveArgPrefix)); name != "" {
+			overridden[name] = true
+		}
+		if !remove {
+			userArgs = append(userArgs, arg)
+		}
+	}
+
+	merged := slices.DeleteFunc(slices.Clone(args), func(arg string) bool {
+		return overridden[argFlagName(arg)]
+	})
+	return append(merged, userArgs...)
+}
+
+// argFlagName returns the name of the flag set by a CLI argument, or an empty string
+// if the argument is not a flag
+func argFlagName(arg string) string {
+	if !strings.HasPrefix(arg, "-") {
+		return ""
+	}
+	name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+	return name
+}
@@ -47,3 +47,67 @@ func TestSnapshotMetadataAudienceArg(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeContainerArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		extraArgs []string
+		expected  []string
+	}{
+		{
+			name:      "no extra args",
+			args:      []string{"--v=0", "--timeout=150s"},
+			extraArgs: nil,
+			expected:  []string{"--v=0", "--timeout=150s"},
+		},
+		{
+			name:      "new flags are appended",
+			args:      []string{"--v=0"},
+			extraArgs: []string{"--foo=bar", "--enable-baz"},
+			expected:  []string{"--v=0", "--foo=bar", "--enable-baz"},
+		},
+		{
+			name:      "flag with the same name replaces the built-in flag",
+			args:      []string{"--v=0", "--timeout=150s", "--retry-interval-start=500ms"},
+			extraArgs: []string{"--timeout=300s"},
+			expected:  []string{"--v=0", "--retry-interval-start=500ms", "--timeout=300s"},
+		},
+		{
+			name:      "flag names are matched regardless of the number of dashes",
+			args:      []string{"--v=0", "--timeout=150s"},
+			extraArgs: []string{"-v=5"},
+			expected:  []string{"--timeout=150s", "-v=5"},
+		},
+		{
+			name:      "all built-in occurrences of a flag are replaced",
+			args:      []string{"--default-fstype=ext4", "--v=0", "--default-fstype=ext4"},
+			extraArgs: []string{"--default-fstype=xfs"},
+			expected:  []string{"--v=0", "--default-fstype=xfs"},
+		},
+		{
+			name:      "prefixed flag removes the built-in flag",
+			args:      []string{"--v=0", "--extra-create-metadata=true"},
+			extraArgs: []string{"!--extra-create-metadata"},
+			expected:  []string{"--v=0"},
+		},
+		{
+			name:      "repeated user flags are kept",
+			args:      []string{"--v=0", "--feature-gates=Topology=false"},
+			extraArgs: []string{"--feature-gates=Topology=true", "--feature-gates=Foo=true"},
+			expected:  []string{"--v=0", "--feature-gates=Topology=true", "--feature-gates=Foo=true"},
+		},
+		{
+			name:      "positional arguments are appended",
+			args:      []string{"--v=0"},
+			extraArgs: []string{"--timeout", "60s"},
+			expected:  []string{"--v=0", "--timeout", "60s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MergeContainerArgs(tt.args, tt.extraArgs))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+)
+
+type eventKey struct {
+	regarding types.UID
+	eventType string
+	reason    string
+	action    string
+	note      string
+}
+
+// deduplicatingEventRecorder drops events that are identical to an event recorded on the same
+// object within the deduplication window
+type deduplicatingEventRecorder struct {
+	recorder events.EventRecorder
+	window   time.Duration
+	now      func() time.Time
+
+	lock     sync.Mutex
+	lastSeen map[eventKey]time.Time
+}
+
+// NewDeduplicatingEventRecorder returns an event recorder that records an event only once per window
+// for a given object, type, reason, action and note, so that reconcile loops hitting the same condition
+// over and over do not flood the API server with events
+func NewDeduplicatingEventRecorder(recorder events.EventRecorder, window time.Duration) events.EventRecorder {
+	return &deduplicatingEventRecorder{
+		recorder: recorder,
+		window:   window,
+		now:      time.Now,
+		lastSeen: map[eventKey]time.Time{},
+	}
+}
+
+func (r *deduplicatingEventRecorder) Eventf(
+	regarding runtime.Object,
+	related runtime.Object,
+	eventType, reason, action, note string,
+	args ...any,
+) {
+	key := eventKey{
+		eventType: eventType,
+		reason:    reason,
+		action:    action,
+		note:      fmt.Sprintf(note, args...),
+	}
+	if obj, err := meta.Accessor(regarding); err == nil {
+		key.regarding = obj.GetUID()
+	}
+
+	if !r.shouldRecord(key) {
+		return
+	}
+	r.recorder.Eventf(regarding, related, eventType, reason, action, note, args...)
+}
+
+func (r *deduplicatingEventRecorder) shouldRecord(key eventKey) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.now()
+	// Forget about expired events to keep the cache bounded by the events of a single window
+	for seenKey, seenAt := range r.lastSeen {
+		if now.Sub(seenAt) >= r.window {
+			delete(r.lastSeen, seenKey)
+		}
+	}
+
+	if _, seen := r.lastSeen[key]; seen {
+		return false
+	}
+	r.lastSeen[key] = now
+	return true
+}
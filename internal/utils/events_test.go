@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/events"
+)
+
+func TestDeduplicatingEventRecorder(t *testing.T) {
+	fakeRecorder := events.NewFakeRecorder(10)
+	now := time.Now()
+	recorder := NewDeduplicatingEventRecorder(fakeRecorder, time.Minute).(*deduplicatingEventRecorder)
+	recorder.now = func() time.Time { return now }
+
+	first := &corev1.ConfigMap{}
+	first.UID = "first"
+	second := &corev1.ConfigMap{}
+	second.UID = "second"
+
+	recorder.Eventf(first, nil, corev1.EventTypeNormal, "Reason", "Action", "note %d", 1)
+	recorder.Eventf(first, nil, corev1.EventTypeNormal, "Reason", "Action", "note %d", 1)
+	assert.Len(t, fakeRecorder.Events, 1, "identical events should be recorded once")
+
+	recorder.Eventf(first, nil, corev1.EventTypeNormal, "Reason", "Action", "note %d", 2)
+	recorder.Eventf(second, nil, corev1.EventTypeNormal, "Reason", "Action", "note %d", 1)
+	recorder.Eventf(first, nil, corev1.EventTypeWarning, "Reason", "Action", "note %d", 1)
+	assert.Len(t, fakeRecorder.Events, 4, "events differing in note, object or type should be recorded")
+
+	now = now.Add(time.Minute)
+	recorder.Eventf(first, nil, corev1.EventTypeNormal, "Reason", "Action", "note %d", 1)
+	assert.Len(t, fakeRecorder.Events, 5, "events should be recorded again after the window")
+	assert.Len(t, recorder.lastSeen, 1, "expired events should be forgotten")
+}
@@ -27,10 +27,26 @@ func AddAnnotation(obj metav1.Object, key string, value string) bool {
 	annotations := obj.GetAnnotations()
 	if annotations == nil {
 		annotations = map[string]string{}
-		obj.SetAnnotations(annotations)
 	}
 	if oldValue, exist := annotations[key]; !exist || oldValue != value {
 		annotations[key] = value
+		// Unstructured objects return a copy of their annotations
+		obj.SetAnnotations(annotations)
+		return true
+	}
+	return false
+}
+
+// AddLabel adds a label to a resource metadata, returns true if added else false
+func AddLabel(obj metav1.Object, key string, value string) bool {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	if oldValue, exist := labels[key]; !exist || oldValue != value {
+		labels[key] = value
+		// Unstructured objects return a copy of their labels
+		obj.SetLabels(labels)
 		return true
 	}
 	return false
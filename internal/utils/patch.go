@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/yaml"
+)
+
+// ApplyPatch applies a strategic merge patch or a JSON (RFC 6902) patch on top of obj, in place.
+// The patch can be provided in either YAML or JSON format. obj is left untouched if the patch
+// cannot be applied.
+func ApplyPatch(obj runtime.Object, patchType types.PatchType, patch string) error {
+	patchJSON, err := yaml.YAMLToJSON([]byte(patch))
+	if err != nil {
+		return fmt.Errorf("invalid patch format: %w", err)
+	}
+
+	objJSON, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	var patchedJSON []byte
+	switch patchType {
+	case types.StrategicMergePatchType:
+		patchedJSON, err = strategicpatch.StrategicMergePatch(objJSON, patchJSON, obj)
+	case types.JSONPatchType:
+		var jsonPatch jsonpatch.Patch
+		if jsonPatch, err = jsonpatch.DecodePatch(patchJSON); err == nil {
+			patchedJSON, err = jsonPatch.Apply(objJSON)
+		}
+	default:
+		err = fmt.Errorf("unsupported patch type %q", patchType)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Decode into a fresh object, making sure fields removed by the patch are not
+	// retained from the original object
+	patched := reflect.New(reflect.TypeOf(obj).Elem())
+	if err := json.Unmarshal(patchedJSON, patched.Interface()); err != nil {
+		return err
+	}
+	reflect.ValueOf(obj).Elem().Set(patched.Elem())
+
+	return nil
+}
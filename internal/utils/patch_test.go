@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newPatchTestDeployment() *appsv1.Deployment {
+	deploy := &appsv1.Deployment{}
+	deploy.Name = "test"
+	deploy.Spec.Template.Spec.Containers = []corev1.Container{
+		{Name: "plugin", Image: "plugin:v1", Args: []string{"--v=0"}},
+		{Name: "provisioner", Image: "provisioner:v1"},
+	}
+	return deploy
+}
+
+func TestApplyPatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		patchType types.PatchType
+		patch     string
+		expectErr bool
+		verify    func(t *testing.T, deploy *appsv1.Deployment)
+	}{
+		{
+			name:      "strategic merge patch merges containers by name",
+			patchType: types.StrategicMergePatchType,
+			patch: `
+spec:
+  template:
+    spec:
+      containers:
+      - name: provisioner
+        image: provisioner:v2
+`,
+			verify: func(t *testing.T, deploy *appsv1.Deployment) {
+				containers := deploy.Spec.Template.Spec.Containers
+				assert.Len(t, containers, 2)
+				assert.Equal(t, "plugin:v1", containers[0].Image)
+				assert.Equal(t, "provisioner:v2", containers[1].Image)
+			},
+		},
+		{
+			name:      "strategic merge patch removes a field",
+			patchType: types.StrategicMergePatchType,
+			patch:     `{"spec":{"template":{"spec":{"containers":[{"name":"plugin","args":null}]}}}}`,
+			verify: func(t *testing.T, deploy *appsv1.Deployment) {
+				assert.Nil(t, deploy.Spec.Template.Spec.Containers[0].Args)
+			},
+		},
+		{
+			name:      "json patch adds an argument",
+			patchType: types.JSONPatchType,
+			patch: `
+- op: add
+  path: /spec/template/spec/containers/0/args/-
+  value: --foo=bar
+`,
+			verify: func(t *testing.T, deploy *appsv1.Deployment) {
+				assert.Equal(t, []string{"--v=0", "--foo=bar"}, deploy.Spec.Template.Spec.Containers[0].Args)
+			},
+		},
+		{
+			name:      "json patch with a missing path",
+			patchType: types.JSONPatchType,
+			patch:     `[{"op": "replace", "path": "/spec/template/spec/containers/5/image", "value": "x"}]`,
+			expectErr: true,
+		},
+		{
+			name:      "json patch that is not a list of operations",
+			patchType: types.JSONPatchType,
+			patch:     `{"op": "remove"}`,
+			expectErr: true,
+		},
+		{
+			name:      "malformed patch",
+			patchType: types.StrategicMergePatchType,
+			patch:     `spec: [`,
+			expectErr: true,
+		},
+		{
+			name:      "unsupported patch type",
+			patchType: types.MergePatchType,
+			patch:     `{}`,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deploy := newPatchTestDeployment()
+			err := ApplyPatch(deploy, tt.patchType, tt.patch)
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.Equal(t, newPatchTestDeployment(), deploy)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "test", deploy.Name)
+			tt.verify(t, deploy)
+		})
+	}
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	tracerName         = "github.com/ceph/ceph-csi-operator"
+	tracingServiceName = "ceph-csi-operator"
+)
+
+// TracingOptions configures the export of the operator traces
+type TracingOptions struct {
+	// The OTLP gRPC endpoint of a collector, as host:port
+	Endpoint string
+	// Connect to the collector without TLS
+	Insecure bool
+	// A file the spans are written to as JSON, one span per line
+	File string
+	// The fraction of the reconcile iterations that are traced
+	SamplingRatio float64
+}
+
+// Enabled returns true when the options configure at least one exporter
+func (o *TracingOptions) Enabled() bool {
+	return o.Endpoint != "" || o.File != ""
+}
+
+// SetupTracing registers a global tracer provider exporting spans to the configured exporters. The
+// returned function flushes the pending spans and stops the exporters.
+func SetupTracing(ctx context.Context, opts TracingOptions) (func(context.Context) error, error) {
+	providerOpts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SamplingRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(tracingServiceName),
+		)),
+	}
+	closers := []func() error{}
+
+	if opts.Endpoint != "" {
+		grpcOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+		if opts.Insecure {
+			grpcOpts = append(grpcOpts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err := otlptracegrpc.New(ctx, grpcOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the OTLP trace exporter: %w", err)
+		}
+		providerOpts = append(providerOpts, sdktrace.WithBatcher(exporter))
+	}
+
+	if opts.File != "" {
+		file, err := os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to create the file trace exporter: %w", err), file.Close())
+		}
+		providerOpts = append(providerOpts, sdktrace.WithSyncer(exporter))
+		closers = append(closers, file.Close)
+	}
+
+	provider := sdktrace.NewTracerProvider(providerOpts...)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return func(ctx context.Context) error {
+		errs := []error{provider.Shutdown(ctx)}
+		for _, closer := range closers {
+			errs = append(errs, closer())
+		}
+		return errors.Join(errs...)
+	}, nil
+}
+
+// StartSpan starts a span as a child of the span found in the context, if any
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan records the error an operation failed with, if any, and ends its span
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// LoggerWithTraceID adds the ID of the trace found in the context to the logger, when the trace is sampled
+func LoggerWithTraceID(ctx context.Context, log logr.Logger) logr.Logger {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsSampled() {
+		return log
+	}
+	return log.WithValues("traceID", spanContext.TraceID().String(), "spanID", spanContext.SpanID().String())
+}
+
+// tracingClient is a client starting a span for each API call
+type tracingClient struct {
+	client.Client
+}
+
+// NewTracingClient wraps a client so that each API call is traced as a child span of the span found
+// in the context of the call
+func NewTracingClient(c client.Client) client.Client {
+	return &tracingClient{Client: c}
+}
+
+func (c *tracingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	ctx, span := startClientSpan(ctx, c.Scheme(), "Get", obj, key)
+	err := c.Client.Get(ctx, key, obj, opts...)
+	endClientSpan(span, err)
+	return err
+}
+
+func (c *tracingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	ctx, span := startClientSpan(ctx, c.Scheme(), "List", list, client.ObjectKey{Namespace: listOpts.Namespace})
+	err := c.Client.List(ctx, list, opts...)
+	endClientSpan(span, err)
+	return err
+}
+
+func (c *tracingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	ctx, span := startClientSpan(ctx, c.Scheme(), "Create", obj, client.ObjectKeyFromObject(obj))
+	err := c.Client.Create(ctx, obj, opts...)
+	endClientSpan(span, err)
+	return err
+}
+
+func (c *tracingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	ctx, span := startClientSpan(ctx, c.Scheme(), "Update", obj, client.ObjectKeyFromObject(obj))
+	err := c.Client.Update(ctx, obj, opts...)
+	endClientSpan(span, err)
+	return err
+}
+
+func (c *tracingClient) Patch(
+	ctx context.Context,
+	obj client.Object,
+	patch client.Patch,
+	opts ...client.PatchOption,
+) error {
+	ctx, span := startClientSpan(ctx, c.Scheme(), "Patch", obj, client.ObjectKeyFromObject(obj))
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+	endClientSpan(span, err)
+	return err
+}
+
+func (c *tracingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	ctx, span := startClientSpan(ctx, c.Scheme(), "Delete", obj, client.ObjectKeyFromObject(obj))
+	err := c.Client.Delete(ctx, obj, opts...)
+	endClientSpan(span, err)
+	return err
+}
+
+func (c *tracingClient) Status() client.SubResourceWriter {
+	return &tracingStatusWriter{SubResourceWriter: c.Client.Status(), scheme: c.Scheme()}
+}
+
+// tracingStatusWriter is a status writer starting a span for each API call
+type tracingStatusWriter struct {
+	client.SubResourceWriter
+	scheme *runtime.Scheme
+}
+
+func (w *tracingStatusWriter) Update(
+	ctx context.Context,
+	obj client.Object,
+	opts ...client.SubResourceUpdateOption,
+) error {
+	ctx, span := startClientSpan(ctx, w.scheme, "UpdateStatus", obj, client.ObjectKeyFromObject(obj))
+	err := w.SubResourceWriter.Update(ctx, obj, opts...)
+	endClientSpan(span, err)
+	return err
+}
+
+func (w *tracingStatusWriter) Patch(
+	ctx context.Context,
+	obj client.Object,
+	patch client.Patch,
+	opts ...client.SubResourcePatchOption,
+) error {
+	ctx, span := startClientSpan(ctx, w.scheme, "PatchStatus", obj, client.ObjectKeyFromObject(obj))
+	err := w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+	endClientSpan(span, err)
+	return err
+}
+
+func startClientSpan(
+	ctx context.Context,
+	scheme *runtime.Scheme,
+	verb string,
+	obj runtime.Object,
+	key client.ObjectKey,
+) (context.Context, trace.Span) {
+	kind := "Unknown"
+	if gvk, err := apiutil.GVKForObject(obj, scheme); err == nil {
+		kind = gvk.Kind
+	}
+	return StartSpan(
+		ctx,
+		fmt.Sprintf("client.%s %s", verb, kind),
+		attribute.String("k8s.verb", verb),
+		attribute.String("k8s.kind", kind),
+		attribute.String("k8s.namespace", key.Namespace),
+		attribute.String("k8s.name", key.Name),
+	)
+}
+
+// endClientSpan ends a client call span, not found errors are expected and do not fail the span
+func endClientSpan(span trace.Span, err error) {
+	if k8serrors.IsNotFound(err) {
+		span.SetAttributes(attribute.Bool("k8s.not_found", true))
+		err = nil
+	}
+	EndSpan(span, err)
+}
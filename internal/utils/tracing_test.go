@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace/noop"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type exportedSpan struct {
+	Name        string
+	SpanContext struct {
+		TraceID string
+		SpanID  string
+	}
+	Parent struct {
+		SpanID string
+	}
+	Status struct {
+		Code string
+	}
+}
+
+func TestTracing(t *testing.T) {
+	ctx := context.Background()
+	traceFile := filepath.Join(t.TempDir(), "traces.json")
+	shutdown, err := SetupTracing(ctx, TracingOptions{File: traceFile, SamplingRatio: 1})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	tracingClient := NewTracingClient(fake.NewClientBuilder().Build())
+
+	ctx, span := StartSpan(ctx, "Test.Reconcile")
+	configMap := &corev1.ConfigMap{}
+	configMap.Name = "config"
+	configMap.Namespace = "default"
+	assert.NoError(t, client.IgnoreNotFound(tracingClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)))
+	assert.NoError(t, tracingClient.Create(ctx, configMap))
+	assert.Error(t, tracingClient.Create(ctx, configMap))
+
+	logLine := ""
+	log := funcr.New(func(_, args string) { logLine = args }, funcr.Options{})
+	LoggerWithTraceID(ctx, log).Info("reconciling")
+	assert.Contains(t, logLine, span.SpanContext().TraceID().String())
+
+	EndSpan(span, nil)
+	assert.NoError(t, shutdown(ctx))
+
+	file, err := os.Open(traceFile)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer func() { _ = file.Close() }()
+
+	spans := map[string][]exportedSpan{}
+	decoder := json.NewDecoder(file)
+	for {
+		exported := exportedSpan{}
+		if err := decoder.Decode(&exported); errors.Is(err, io.EOF) {
+			break
+		} else if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		spans[exported.Name] = append(spans[exported.Name], exported)
+	}
+
+	root := spans["Test.Reconcile"]
+	if !assert.Len(t, root, 1) {
+		t.FailNow()
+	}
+	gets := spans["client.Get ConfigMap"]
+	creates := spans["client.Create ConfigMap"]
+	assert.Len(t, gets, 1)
+	assert.Len(t, creates, 2)
+	for _, child := range append(gets, creates...) {
+		assert.Equal(t, root[0].SpanContext.TraceID, child.SpanContext.TraceID)
+		assert.Equal(t, root[0].SpanContext.SpanID, child.Parent.SpanID)
+	}
+	assert.Equal(t, "Unset", gets[0].Status.Code, "not found errors should not fail the span")
+	assert.Equal(t, "Error", creates[1].Status.Code, "failed calls should fail the span")
+}